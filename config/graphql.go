@@ -0,0 +1,135 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GraphQLConfig selects the caches and extensions of the GraphQL server and whether the playground is served
+type GraphQLConfig struct {
+	// Number of parsed and validated queries kept in memory
+	QueryCacheSize int
+	// Number of queries kept for automatic persisted queries
+	APQCacheSize int
+	// Interval of the keep-alive messages of the websocket connections of subscriptions
+	WebsocketKeepAlive time.Duration
+	// Largest multipart request and the part of it kept in memory, in bytes
+	UploadMaxSize   int64
+	UploadMaxMemory int64
+	// Deepest nesting of fields and highest cost of an operation, 0 turns the limit off
+	MaxDepth      int
+	MaxComplexity int
+	// Sha256 hashes (hex) and queries of the only operations run by the server, nil runs any operation
+	Allowlist map[string]string
+
+	Introspection bool
+	APQ           bool
+	Playground    bool
+}
+
+// DefaultGraphQLConfig enables every extension and the playground, it is meant for development
+func DefaultGraphQLConfig() GraphQLConfig {
+	return GraphQLConfig{
+		QueryCacheSize:     1000,
+		APQCacheSize:       100,
+		WebsocketKeepAlive: 10 * time.Second,
+		UploadMaxSize:      32 << 20,
+		UploadMaxMemory:    32 << 20,
+		MaxDepth:           12,
+		MaxComplexity:      10000,
+		Introspection:      true,
+		APQ:                true,
+		Playground:         true,
+	}
+}
+
+// NewGraphQLConfig reads the GraphQL server settings from environment variables, unset ones keep the defaults
+// of DefaultGraphQLConfig. GRAPHQL_INTROSPECTION, GRAPHQL_APQ and GRAPHQL_PLAYGROUND switch the extensions
+// and the playground route on or off ("true" or "false"). GRAPHQL_QUERY_CACHE_SIZE and GRAPHQL_APQ_CACHE_SIZE
+// are numbers of queries, GRAPHQL_WS_KEEPALIVE is a duration such as "10s". GRAPHQL_MAX_DEPTH and
+// GRAPHQL_MAX_COMPLEXITY limit the operations, 0 turns a limit off. GRAPHQL_ALLOWLIST_FILE is a JSON object
+// mapping the sha256 hash of each allowed query to the query, the other operations are refused when it is set.
+func NewGraphQLConfig() (GraphQLConfig, error) {
+	cfg := DefaultGraphQLConfig()
+
+	for name, value := range map[string]*bool{
+		"GRAPHQL_INTROSPECTION": &cfg.Introspection,
+		"GRAPHQL_APQ":           &cfg.APQ,
+		"GRAPHQL_PLAYGROUND":    &cfg.Playground,
+	} {
+		if env := strings.TrimSpace(os.Getenv(name)); env != "" {
+			enabled, err := strconv.ParseBool(env)
+			if err != nil {
+				return GraphQLConfig{}, fmt.Errorf("%s invalid format: %w", name, err)
+			}
+			*value = enabled
+		}
+	}
+
+	for name, value := range map[string]*int{
+		"GRAPHQL_QUERY_CACHE_SIZE": &cfg.QueryCacheSize,
+		"GRAPHQL_MAX_DEPTH":        &cfg.MaxDepth,
+		"GRAPHQL_MAX_COMPLEXITY":   &cfg.MaxComplexity,
+	} {
+		if env := strings.TrimSpace(os.Getenv(name)); env != "" {
+			count, err := strconv.Atoi(env)
+			if err != nil || count < 0 {
+				return GraphQLConfig{}, fmt.Errorf("%s invalid format: %q", name, env)
+			}
+			*value = count
+		}
+	}
+	if size := strings.TrimSpace(os.Getenv("GRAPHQL_APQ_CACHE_SIZE")); size != "" {
+		count, err := strconv.Atoi(size)
+		if err != nil || count <= 0 {
+			return GraphQLConfig{}, fmt.Errorf("GRAPHQL_APQ_CACHE_SIZE invalid format: %q", size)
+		}
+		cfg.APQCacheSize = count
+	}
+
+	if keepAlive := strings.TrimSpace(os.Getenv("GRAPHQL_WS_KEEPALIVE")); keepAlive != "" {
+		duration, err := time.ParseDuration(keepAlive)
+		if err != nil {
+			return GraphQLConfig{}, fmt.Errorf("GRAPHQL_WS_KEEPALIVE invalid format: %w", err)
+		}
+		cfg.WebsocketKeepAlive = duration
+	}
+
+	if path := strings.TrimSpace(os.Getenv("GRAPHQL_ALLOWLIST_FILE")); path != "" {
+		allowlist, err := loadAllowlist(path)
+		if err != nil {
+			return GraphQLConfig{}, err
+		}
+		cfg.Allowlist = allowlist
+	}
+	return cfg, nil
+}
+
+// loadAllowlist reads the queries of an allowlist file, each query has to be listed under its own hash
+func loadAllowlist(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("GRAPHQL_ALLOWLIST_FILE cannot be read: %w", err)
+	}
+
+	var allowlist map[string]string
+	if err := json.Unmarshal(data, &allowlist); err != nil {
+		return nil, fmt.Errorf("GRAPHQL_ALLOWLIST_FILE invalid format: %w", err)
+	}
+	if len(allowlist) == 0 {
+		return nil, fmt.Errorf("GRAPHQL_ALLOWLIST_FILE has no query")
+	}
+	for hash, query := range allowlist {
+		sum := sha256.Sum256([]byte(query))
+		if hash != hex.EncodeToString(sum[:]) {
+			return nil, fmt.Errorf("GRAPHQL_ALLOWLIST_FILE hash %s does not match its query", hash)
+		}
+	}
+	return allowlist, nil
+}
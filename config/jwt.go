@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/pkg/jwt"
+)
+
+const (
+	defaultJWTIssuer   = "friend-management"
+	defaultJWTAudience = "friend-management-api"
+	defaultJWTTTL      = 24 * time.Hour
+)
+
+// NewJWTConfig reads the token settings from environment variables.
+// JWT_ISSUER, JWT_AUDIENCE and JWT_TTL (a duration such as "24h") are optional.
+// JWT_KEYS lists the key files as "kid:algorithm:path" separated by commas, keys of rotated out signers
+// only need their public key. JWT_SIGNING_KEY_ID selects the key signing new tokens.
+// JWT_SECRET adds a HS256 key with id JWT_SECRET_KEY_ID (default "secret"), it is meant for development.
+func NewJWTConfig() (jwt.Config, error) {
+	cfg := jwt.Config{
+		Issuer:       envOrDefault("JWT_ISSUER", defaultJWTIssuer),
+		Audience:     envOrDefault("JWT_AUDIENCE", defaultJWTAudience),
+		TTL:          defaultJWTTTL,
+		SigningKeyID: strings.TrimSpace(os.Getenv("JWT_SIGNING_KEY_ID")),
+	}
+
+	if ttl := strings.TrimSpace(os.Getenv("JWT_TTL")); ttl != "" {
+		duration, err := time.ParseDuration(ttl)
+		if err != nil {
+			return jwt.Config{}, fmt.Errorf("JWT_TTL invalid format: %w", err)
+		}
+		cfg.TTL = duration
+	}
+
+	if keys := strings.TrimSpace(os.Getenv("JWT_KEYS")); keys != "" {
+		for _, entry := range strings.Split(keys, ",") {
+			parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+			if len(parts) != 3 {
+				return jwt.Config{}, fmt.Errorf("JWT_KEYS entry %q invalid format (ex: \"2021-12:RS256:keys/2021-12.pem\")", entry)
+			}
+			key, err := jwt.LoadKey(parts[0], parts[1], parts[2])
+			if err != nil {
+				return jwt.Config{}, err
+			}
+			cfg.Keys = append(cfg.Keys, key)
+		}
+	}
+
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		key, err := jwt.NewSecretKey(envOrDefault("JWT_SECRET_KEY_ID", "secret"), []byte(secret))
+		if err != nil {
+			return jwt.Config{}, err
+		}
+		cfg.Keys = append(cfg.Keys, key)
+	}
+
+	if len(cfg.Keys) == 0 {
+		return jwt.Config{}, errors.New("JWT_KEYS or JWT_SECRET not found")
+	}
+	if cfg.SigningKeyID == "" && len(cfg.Keys) == 1 {
+		cfg.SigningKeyID = cfg.Keys[0].ID
+	}
+	return cfg, nil
+}
+
+func envOrDefault(name string, defaultValue string) string {
+	if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+		return value
+	}
+	return defaultValue
+}
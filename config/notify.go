@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+)
+
+// NewNotifyConfig reads the notifier and dispatcher settings from environment variables, unset ones keep the defaults
+// of notify.DefaultConfig. NOTIFY_SMTP_ADDR ("host:port") sends the notifications by email from NOTIFY_SMTP_FROM,
+// NOTIFY_SMTP_USERNAME and NOTIFY_SMTP_PASSWORD are optional, without NOTIFY_SMTP_ADDR they are written to the log.
+// NOTIFY_POLL_INTERVAL, NOTIFY_BACKOFF_BASE and NOTIFY_BACKOFF_MAX are durations such as "30s",
+// NOTIFY_BATCH_SIZE and NOTIFY_MAX_ATTEMPTS are positive numbers.
+func NewNotifyConfig() (notify.Config, error) {
+	cfg := notify.DefaultConfig()
+	cfg.SMTPAddr = strings.TrimSpace(os.Getenv("NOTIFY_SMTP_ADDR"))
+	cfg.SMTPFrom = strings.TrimSpace(os.Getenv("NOTIFY_SMTP_FROM"))
+	cfg.SMTPUsername = strings.TrimSpace(os.Getenv("NOTIFY_SMTP_USERNAME"))
+	cfg.SMTPPassword = os.Getenv("NOTIFY_SMTP_PASSWORD")
+	if cfg.SMTPAddr != "" && cfg.SMTPFrom == "" {
+		return notify.Config{}, errors.New("NOTIFY_SMTP_FROM not found")
+	}
+
+	for name, value := range map[string]*time.Duration{
+		"NOTIFY_POLL_INTERVAL": &cfg.PollInterval,
+		"NOTIFY_BACKOFF_BASE":  &cfg.BackoffBase,
+		"NOTIFY_BACKOFF_MAX":   &cfg.BackoffMax,
+	} {
+		if env := strings.TrimSpace(os.Getenv(name)); env != "" {
+			duration, err := time.ParseDuration(env)
+			if err != nil || duration <= 0 {
+				return notify.Config{}, fmt.Errorf("%s invalid format: %q", name, env)
+			}
+			*value = duration
+		}
+	}
+
+	for name, value := range map[string]*int{
+		"NOTIFY_BATCH_SIZE":   &cfg.BatchSize,
+		"NOTIFY_MAX_ATTEMPTS": &cfg.MaxAttempts,
+	} {
+		if env := strings.TrimSpace(os.Getenv(name)); env != "" {
+			count, err := strconv.Atoi(env)
+			if err != nil || count <= 0 {
+				return notify.Config{}, fmt.Errorf("%s invalid format: %q", name, env)
+			}
+			*value = count
+		}
+	}
+
+	if cfg.BackoffMax < cfg.BackoffBase {
+		return notify.Config{}, errors.New("NOTIFY_BACKOFF_MAX must not be less than NOTIFY_BACKOFF_BASE")
+	}
+	return cfg, nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+)
+
+// NewWebhookConfig reads the webhook dispatcher settings from environment variables, unset ones keep the defaults
+// of webhooks.DefaultConfig. WEBHOOK_POLL_INTERVAL, WEBHOOK_BACKOFF_BASE, WEBHOOK_BACKOFF_MAX and WEBHOOK_TIMEOUT
+// are durations such as "30s", WEBHOOK_BATCH_SIZE and WEBHOOK_MAX_ATTEMPTS are positive numbers.
+func NewWebhookConfig() (webhooks.Config, error) {
+	cfg := webhooks.DefaultConfig()
+
+	for name, value := range map[string]*time.Duration{
+		"WEBHOOK_POLL_INTERVAL": &cfg.PollInterval,
+		"WEBHOOK_BACKOFF_BASE":  &cfg.BackoffBase,
+		"WEBHOOK_BACKOFF_MAX":   &cfg.BackoffMax,
+		"WEBHOOK_TIMEOUT":       &cfg.Timeout,
+	} {
+		if env := strings.TrimSpace(os.Getenv(name)); env != "" {
+			duration, err := time.ParseDuration(env)
+			if err != nil || duration <= 0 {
+				return webhooks.Config{}, fmt.Errorf("%s invalid format: %q", name, env)
+			}
+			*value = duration
+		}
+	}
+
+	for name, value := range map[string]*int{
+		"WEBHOOK_BATCH_SIZE":   &cfg.BatchSize,
+		"WEBHOOK_MAX_ATTEMPTS": &cfg.MaxAttempts,
+	} {
+		if env := strings.TrimSpace(os.Getenv(name)); env != "" {
+			count, err := strconv.Atoi(env)
+			if err != nil || count <= 0 {
+				return webhooks.Config{}, fmt.Errorf("%s invalid format: %q", name, env)
+			}
+			*value = count
+		}
+	}
+
+	if cfg.BackoffMax < cfg.BackoffBase {
+		return webhooks.Config{}, errors.New("WEBHOOK_BACKOFF_MAX must not be less than WEBHOOK_BACKOFF_BASE")
+	}
+	return cfg, nil
+}
@@ -0,0 +1,7 @@
+-- Reverses the corresponding up script
+
+BEGIN;
+
+ALTER TABLE users DROP COLUMN IF EXISTS password;
+
+COMMIT;
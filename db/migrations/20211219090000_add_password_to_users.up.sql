@@ -0,0 +1,12 @@
+-- Add password column to users table, the value is a bcrypt hash used by the login mutation.
+
+BEGIN;
+
+-- An empty password never matches a hash, users without password cannot login
+ALTER TABLE users ADD COLUMN password VARCHAR(100) NOT NULL DEFAULT '';
+
+-- dummy data for testing, the password of dummy users is "password"
+UPDATE users SET password = '$2a$10$IJHVXhLnbbKxYuQq0mRd8.1xXIVh0.p9u177pyEYJLh8O8U3KcM1.'
+WHERE email IN ('john@example.com', 'andy@example.com', 'common@example.com', 'lisa@example.com', 'kate@example.com');
+
+COMMIT;
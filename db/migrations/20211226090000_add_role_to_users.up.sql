@@ -0,0 +1,11 @@
+-- Add role column to users table, it is checked by the @hasRole directive of the GraphQL schema.
+
+BEGIN;
+
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'USER';
+ALTER TABLE users ADD CONSTRAINT constraint_users_role CHECK (role IN ('USER', 'ADMIN'));
+
+-- dummy data for testing
+UPDATE users SET role = 'ADMIN' WHERE email = 'common@example.com';
+
+COMMIT;
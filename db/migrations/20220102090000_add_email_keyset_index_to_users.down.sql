@@ -0,0 +1,7 @@
+-- Reverses the corresponding up script
+
+BEGIN;
+
+DROP INDEX IF EXISTS users_email_keyset;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Add a byte-wise index on users.email, it serves the keyset pagination of the GraphQL lists ordered by email.
+
+BEGIN;
+
+CREATE INDEX users_email_keyset ON users (email COLLATE "C");
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Reverses the corresponding up script
+
+BEGIN;
+
+DROP TABLE update_deliveries;
+DROP TABLE updates;
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- Setup updates and update_deliveries tables, an update published by a sender is delivered once to each of its recipients.
+
+BEGIN;
+
+CREATE TABLE updates (
+    id SERIAL PRIMARY KEY,
+    sender_id INTEGER REFERENCES users ON DELETE CASCADE NOT NULL,
+    text TEXT NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+CREATE INDEX sender_id_on_updates ON updates(sender_id);
+
+CREATE TABLE update_deliveries (
+    id SERIAL PRIMARY KEY,
+    update_id INTEGER REFERENCES updates ON DELETE CASCADE NOT NULL,
+    recipient_id INTEGER REFERENCES users ON DELETE CASCADE NOT NULL,
+    CONSTRAINT constraint_update_deliveries_pkey UNIQUE (update_id, recipient_id)
+);
+CREATE INDEX recipient_id_on_update_deliveries ON update_deliveries(recipient_id);
+
+COMMIT;
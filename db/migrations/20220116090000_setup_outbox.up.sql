@@ -0,0 +1,24 @@
+-- Setup outbox table, the notifications of a change are written in the transaction of the change and delivered
+-- later by the dispatcher. A notification failing too many times is dead and kept with its last error.
+
+BEGIN;
+
+CREATE TABLE outbox (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(30) NOT NULL,
+    recipient VARCHAR(100) NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL,
+    last_error TEXT,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    CONSTRAINT constraint_outbox_status CHECK (status IN ('pending', 'sent', 'dead'))
+);
+
+-- The dispatcher only looks for the pending notifications which are due
+CREATE INDEX pending_on_outbox ON outbox(next_attempt_at) WHERE status = 'pending';
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Reverses the corresponding up script
+
+BEGIN;
+
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
+
+COMMIT;
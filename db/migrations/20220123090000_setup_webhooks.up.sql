@@ -0,0 +1,36 @@
+-- Setup webhooks table, the endpoints of the downstream services with the relationship events they receive,
+-- and webhook_deliveries table, the events queued for each endpoint with the result of their last attempt.
+-- A delivery failing too many times is dead and kept with its last error.
+
+BEGIN;
+
+CREATE TABLE webhooks (
+    id SERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    -- An empty list receives every event
+    events VARCHAR(30)[] NOT NULL DEFAULT '{}',
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+CREATE TABLE webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    webhook_id INTEGER REFERENCES webhooks ON DELETE CASCADE NOT NULL,
+    event VARCHAR(30) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL,
+    response_status INTEGER,
+    last_error TEXT,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    CONSTRAINT constraint_webhook_deliveries_status CHECK (status IN ('pending', 'delivered', 'dead'))
+);
+
+CREATE INDEX webhook_id_on_webhook_deliveries ON webhook_deliveries(webhook_id);
+-- The dispatcher only looks for the pending deliveries which are due
+CREATE INDEX pending_on_webhook_deliveries ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+COMMIT;
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+)
+
+// contextKey is the type of keys stored by this package, it prevents collisions with other packages
+type contextKey struct {
+	name string
+}
+
+var userCtxKey = &contextKey{"user"}
+
+var errInvalidToken = errors.New("Invalid token")
+
+// TokenParser verifies a token and returns the email of the authenticated user
+type TokenParser interface {
+	ParseToken(tokenStr string) (string, error)
+}
+
+// Middleware reads the Bearer token of a request and puts the email of the authenticated user into the context.
+// Requests without a token pass through anonymously, requests with an invalid token are rejected.
+func Middleware(tokens TokenParser) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			email, err := parseBearer(tokens, header)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), email)))
+		})
+	}
+}
+
+// WebsocketInit authenticates a websocket connection with the "Authorization" field of its connection_init payload,
+// since browsers cannot set headers on websockets. Connections without the field keep the user of the upgrade request.
+func WebsocketInit(tokens TokenParser) transport.WebsocketInitFunc {
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, error) {
+		header := payload.Authorization()
+		if header == "" {
+			return ctx, nil
+		}
+
+		email, err := parseBearer(tokens, header)
+		if err != nil {
+			return ctx, err
+		}
+		return WithUser(ctx, email), nil
+	}
+}
+
+// parseBearer verifies the token of a "Bearer <token>" value and returns the email of its user
+func parseBearer(tokens TokenParser, header string) (string, error) {
+	tokenStr := strings.TrimPrefix(header, "Bearer ")
+	if tokenStr == header || tokenStr == "" {
+		return "", errInvalidToken
+	}
+	email, err := tokens.ParseToken(tokenStr)
+	if err != nil || email == "" {
+		return "", errInvalidToken
+	}
+	return email, nil
+}
+
+// WithUser returns a copy of ctx carrying the email of the authenticated user
+func WithUser(ctx context.Context, email string) context.Context {
+	return context.WithValue(ctx, userCtxKey, email)
+}
+
+// ForContext finds the email of the authenticated user, it is empty for anonymous requests
+func ForContext(ctx context.Context) string {
+	email, _ := ctx.Value(userCtxKey).(string)
+	return email
+}
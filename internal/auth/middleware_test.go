@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/pkg/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTokens creates a token manager signing with a HS256 test key
+func newTestTokens(t *testing.T) *jwt.Manager {
+	key, err := jwt.NewSecretKey("test", []byte("test-secret-with-at-least-32-bytes"))
+	require.NoError(t, err)
+	tokens, err := jwt.NewManager(jwt.Config{
+		Issuer:       "test",
+		Audience:     "test",
+		TTL:          time.Hour,
+		SigningKeyID: key.ID,
+		Keys:         []jwt.Key{key},
+	})
+	require.NoError(t, err)
+	return tokens
+}
+
+func TestMiddleware(t *testing.T) {
+	tokens := newTestTokens(t)
+	token, err := tokens.GenerateToken("andy@example.com")
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		header    string
+		expStatus int
+		expUser   string
+	}{
+		"success with a valid token": {
+			header:    "Bearer " + token,
+			expStatus: http.StatusOK,
+			expUser:   "andy@example.com",
+		},
+		"success with an anonymous request": {
+			expStatus: http.StatusOK,
+		},
+		"failed with an invalid token": {
+			header:    "Bearer invalid",
+			expStatus: http.StatusUnauthorized,
+		},
+		"failed with a token without Bearer scheme": {
+			header:    token,
+			expStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			var user string
+			handler := Middleware(tokens)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				user = ForContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/query", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expStatus, rr.Code)
+			require.Equal(t, tc.expUser, user)
+		})
+	}
+}
+
+func TestWebsocketInit(t *testing.T) {
+	tokens := newTestTokens(t)
+	token, err := tokens.GenerateToken("andy@example.com")
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		ctx      context.Context
+		payload  transport.InitPayload
+		expUser  string
+		expError error
+	}{
+		"success with a valid token in the payload": {
+			ctx:     context.Background(),
+			payload: transport.InitPayload{"Authorization": "Bearer " + token},
+			expUser: "andy@example.com",
+		},
+		"success with the user of the upgrade request": {
+			ctx:     WithUser(context.Background(), "lisa@example.com"),
+			payload: transport.InitPayload{},
+			expUser: "lisa@example.com",
+		},
+		"success with an anonymous connection": {
+			ctx:     context.Background(),
+			payload: transport.InitPayload{},
+		},
+		"failed with an invalid token": {
+			ctx:      context.Background(),
+			payload:  transport.InitPayload{"authorization": "Bearer invalid"},
+			expError: errors.New("Invalid token"),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx, err := WebsocketInit(tokens)(tc.ctx, tc.payload)
+			require.Equal(t, tc.expError, err)
+			require.Equal(t, tc.expUser, ForContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/auth"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+)
+
+// requireParty finds the authenticated user of a write, it has to be one of the parties like @isSelf
+// of the GraphQL mutations. The rejected requests are answered and ok is false.
+func requireParty(w http.ResponseWriter, r *http.Request, parties ...string) (actor string, ok bool) {
+	actor = auth.ForContext(r.Context())
+	if actor == "" {
+		Respond(w, http.StatusUnauthorized, MsgError(errs.ErrUnauthenticated))
+		return "", false
+	}
+
+	for _, party := range parties {
+		if party == actor {
+			return actor, true
+		}
+	}
+	Respond(w, http.StatusForbidden, MsgError(errs.ErrForbiddenActor))
+	return "", false
+}
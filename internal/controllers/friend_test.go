@@ -2,11 +2,15 @@ package controllers
 
 import (
 	"bytes"
+	"database/sql"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/auth"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/services"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -56,14 +60,36 @@ func TestControllers_GetUsers(t *testing.T) {
 
 func TestControllers_CreateFriends(t *testing.T) {
 	tcs := map[string]struct {
-		input     string
-		expResult string
-		expError  error
-		mockErr   error
+		input         string
+		actor         string
+		mockRequestor string
+		mockTarget    string
+		expResult     string
+		expError      error
+		mockErr       error
 	}{
 		"success with an input": {
-			input:     `{ "friends": ["andy@example.com","john@example.com"]}`,
-			expResult: `{"success":true}`,
+			input:         `{ "friends": ["andy@example.com","john@example.com"]}`,
+			actor:         "andy@example.com",
+			mockRequestor: "andy@example.com",
+			mockTarget:    "john@example.com",
+			expResult:     `{"message":"A friend request is sent to john@example.com, the friendship is created once it is accepted","pending":true,"success":false}`,
+		},
+		"success with the actor as the second friend": {
+			input:         `{ "friends": ["andy@example.com","john@example.com"]}`,
+			actor:         "john@example.com",
+			mockRequestor: "john@example.com",
+			mockTarget:    "andy@example.com",
+			expResult:     `{"message":"A friend request is sent to andy@example.com, the friendship is created once it is accepted","pending":true,"success":false}`,
+		},
+		"failed with an anonymous request": {
+			input:    `{ "friends": ["andy@example.com","john@example.com"]}`,
+			expError: errors.New(`{"message":"Access denied, a valid token is required","success":false}`),
+		},
+		"failed with an actor who is not one of the friends": {
+			input:    `{ "friends": ["andy@example.com","john@example.com"]}`,
+			actor:    "kate@example.com",
+			expError: errors.New(`{"message":"The authenticated user has to be one of the parties","success":false}`),
 		},
 		"failed with an unknow format input": {
 			input:    `{}`,
@@ -72,18 +98,26 @@ func TestControllers_CreateFriends(t *testing.T) {
 		},
 		"failed with an input validation failure (two emails are similar)": {
 			input:    `{ "friends": ["andy@example.com","andy@example.com"]}`,
-			expError: errors.New(`{"message":"Two email addresses must be different","success":false}`),
-			mockErr:  errors.New(`{"message":"Two email addresses must be different","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"friends","message":"Two email addresses must be different"}],"message":"Two email addresses must be different","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"friends","message":"Two email addresses must be different"}],"message":"Two email addresses must be different","success":false}`),
 		},
 		"failed with an input validation failure (email invalid format)": {
 			input:    `{ "friends": ["andy@examplecom","andy@example.com"]}`,
-			expError: errors.New(`{"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
-			mockErr:  errors.New(`{"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"friends[0]","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"friends[0]","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
 		},
 		"failed with an input validation failure (number of emails are wrong)": {
 			input:    `{ "friends": ["andy@examplecom"]}`,
-			expError: errors.New(`{"message":"Number of email addresses must be 2","success":false}`),
-			mockErr:  errors.New(`{"message":"Number of email addresses must be 2","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"friends","message":"Number of email addresses must be 2"},{"field":"friends[0]","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"Number of email addresses must be 2; andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"friends","message":"Number of email addresses must be 2"},{"field":"friends[0]","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"Number of email addresses must be 2; andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+		},
+		"failed with an input validation failure (email inside a text)": {
+			input:    `{ "friends": ["garbage andy@example.com garbage","john@example.com"]}`,
+			expError: errors.New(`{"fields":[{"field":"friends[0]","message":"garbage andy@example.com garbage invalid format (ex: \"andy@example.com\")"}],"message":"garbage andy@example.com garbage invalid format (ex: \"andy@example.com\")","success":false}`),
+		},
+		"failed with an input validation failure (every email invalid format)": {
+			input:    `{ "friends": ["andy@examplecom","john"]}`,
+			expError: errors.New(`{"fields":[{"field":"friends[0]","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"},{"field":"friends[1]","message":"john invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\"); john invalid format (ex: \"andy@example.com\")","success":false}`),
 		},
 	}
 
@@ -91,10 +125,11 @@ func TestControllers_CreateFriends(t *testing.T) {
 		t.Run(desc, func(t *testing.T) {
 			req, err := http.NewRequest("POST", "/v1/friends", bytes.NewBuffer([]byte(tc.input)))
 			require.NoError(t, err)
+			req = req.WithContext(auth.WithUser(req.Context(), tc.actor))
 
 			var mockService SpecService
 			mockService.ExpectedCalls = []*mock.Call{
-				mockService.On("CreateFriend", mock.Anything, mock.Anything, mock.Anything).Return(tc.mockErr),
+				mockService.On("SendFriendRequest", mock.Anything, tc.mockRequestor, tc.mockTarget).Return(tc.mockErr),
 			}
 			friendController := NewFriendController(mockService)
 			handler := http.HandlerFunc(friendController.CreateFriend)
@@ -105,6 +140,7 @@ func TestControllers_CreateFriends(t *testing.T) {
 				require.EqualError(t, tc.expError, rr.Body.String())
 			} else {
 				require.NoError(t, err)
+				require.Equal(t, http.StatusAccepted, rr.Code)
 				require.Equal(t, tc.expResult, rr.Body.String())
 			}
 		})
@@ -131,8 +167,8 @@ func TestControllers_GetFriends(t *testing.T) {
 		},
 		"failed with an input validation failure": {
 			input:    `{"Email":"andy@examplecom"}`,
-			expError: errors.New(`{"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
-			mockErr:  errors.New(`{"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"email","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"email","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
 		},
 	}
 
@@ -180,18 +216,18 @@ func TestControllers_GetCommonFriends(t *testing.T) {
 		},
 		"failed with an input validation failure (two emails are similar)": {
 			input:    `{ "friends": ["andy@example.com","andy@example.com"]}`,
-			expError: errors.New(`{"message":"Two email addresses must be different","success":false}`),
-			mockErr:  errors.New(`{"message":"Two email addresses must be different","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"friends","message":"Two email addresses must be different"}],"message":"Two email addresses must be different","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"friends","message":"Two email addresses must be different"}],"message":"Two email addresses must be different","success":false}`),
 		},
 		"failed with an input validation failure (email invalid format)": {
 			input:    `{ "friends": ["andy@examplecom","andy@example.com"]}`,
-			expError: errors.New(`{"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
-			mockErr:  errors.New(`{"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"friends[0]","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"friends[0]","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
 		},
 		"failed with an input validation failure (number of emails are wrong)": {
 			input:    `{ "friends": ["andy@examplecom"]}`,
-			expError: errors.New(`{"message":"Number of email addresses must be 2","success":false}`),
-			mockErr:  errors.New(`{"message":"Number of email addresses must be 2","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"friends","message":"Number of email addresses must be 2"},{"field":"friends[0]","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"Number of email addresses must be 2; andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"friends","message":"Number of email addresses must be 2"},{"field":"friends[0]","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"Number of email addresses must be 2; andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
 		},
 	}
 
@@ -223,14 +259,25 @@ func TestControllers_GetCommonFriends(t *testing.T) {
 func TestControllers_CreateSubcription(t *testing.T) {
 	tcs := map[string]struct {
 		input     string
+		actor     string
 		expResult string
 		expError  error
 		mockErr   error
 	}{
 		"success with an input": {
 			input:     `{"requestor": "andy@example.com","target": "lisa@example.com"}`,
+			actor:     "andy@example.com",
 			expResult: `{"success":true}`,
 		},
+		"failed with an anonymous request": {
+			input:    `{"requestor": "andy@example.com","target": "lisa@example.com"}`,
+			expError: errors.New(`{"message":"Access denied, a valid token is required","success":false}`),
+		},
+		"failed with an actor who is not the requestor": {
+			input:    `{"requestor": "andy@example.com","target": "lisa@example.com"}`,
+			actor:    "lisa@example.com",
+			expError: errors.New(`{"message":"The authenticated user has to be one of the parties","success":false}`),
+		},
 		"failed with an unknow format input": {
 			input:    `{}`,
 			expError: errors.New(`{"message":"Request body is empty","success":false}`),
@@ -238,23 +285,23 @@ func TestControllers_CreateSubcription(t *testing.T) {
 		},
 		"failed with an input validation failure (two emails are similar)": {
 			input:    `{"requestor": "andy@example.com","target": "andy@example.com"}`,
-			expError: errors.New(`{"message":"Two email addresses must be different","success":false}`),
-			mockErr:  errors.New(`{"message":"Two email addresses must be different","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"target","message":"Two email addresses must be different"}],"message":"Two email addresses must be different","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"target","message":"Two email addresses must be different"}],"message":"Two email addresses must be different","success":false}`),
 		},
 		"failed with an input validation failure (email invalid format)": {
 			input:    `{"requestor": "andy@examplecom","target": "andy@example.com"}`,
-			expError: errors.New(`{"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
-			mockErr:  errors.New(`{"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"requestor","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"requestor","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
 		},
 		"failed with an input validation failure (target invalid)": {
 			input:    `{"requestor": "andy@example.com"}`,
-			expError: errors.New(`{"message":"Target field invalid format","success":false}`),
-			mockErr:  errors.New(`{"message":"Target field invalid format","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"target","message":"Target field invalid format"}],"message":"Target field invalid format","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"target","message":"Target field invalid format"}],"message":"Target field invalid format","success":false}`),
 		},
 		"failed with an input validation failure (requestor invalid)": {
 			input:    `{"target": "andy@example.com"}`,
-			expError: errors.New(`{"message":"Requestor field invalid format","success":false}`),
-			mockErr:  errors.New(`{"message":"Requestor field invalid format","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"requestor","message":"Requestor field invalid format"}],"message":"Requestor field invalid format","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"requestor","message":"Requestor field invalid format"}],"message":"Requestor field invalid format","success":false}`),
 		},
 	}
 
@@ -262,6 +309,7 @@ func TestControllers_CreateSubcription(t *testing.T) {
 		t.Run(desc, func(t *testing.T) {
 			req, err := http.NewRequest("POST", "/v1/subscription", bytes.NewBuffer([]byte(tc.input)))
 			require.NoError(t, err)
+			req = req.WithContext(auth.WithUser(req.Context(), tc.actor))
 
 			var mockService SpecService
 			mockService.ExpectedCalls = []*mock.Call{
@@ -285,14 +333,25 @@ func TestControllers_CreateSubcription(t *testing.T) {
 func TestControllers_CreateUserBlocks(t *testing.T) {
 	tcs := map[string]struct {
 		input     string
+		actor     string
 		expResult string
 		expError  error
 		mockErr   error
 	}{
 		"success with an input": {
 			input:     `{"requestor": "andy@example.com","target": "lisa@example.com"}`,
+			actor:     "andy@example.com",
 			expResult: `{"success":true}`,
 		},
+		"failed with an anonymous request": {
+			input:    `{"requestor": "andy@example.com","target": "lisa@example.com"}`,
+			expError: errors.New(`{"message":"Access denied, a valid token is required","success":false}`),
+		},
+		"failed with an actor who is not the requestor": {
+			input:    `{"requestor": "andy@example.com","target": "lisa@example.com"}`,
+			actor:    "lisa@example.com",
+			expError: errors.New(`{"message":"The authenticated user has to be one of the parties","success":false}`),
+		},
 		"failed with an unknow format input": {
 			input:    `{}`,
 			expError: errors.New(`{"message":"Request body is empty","success":false}`),
@@ -300,23 +359,23 @@ func TestControllers_CreateUserBlocks(t *testing.T) {
 		},
 		"failed with an input validation failure (two emails are similar)": {
 			input:    `{"requestor": "andy@example.com","target": "andy@example.com"}`,
-			expError: errors.New(`{"message":"Two email addresses must be different","success":false}`),
-			mockErr:  errors.New(`{"message":"Two email addresses must be different","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"target","message":"Two email addresses must be different"}],"message":"Two email addresses must be different","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"target","message":"Two email addresses must be different"}],"message":"Two email addresses must be different","success":false}`),
 		},
 		"failed with an input validation failure (email invalid format)": {
 			input:    `{"requestor": "andy@examplecom","target": "andy@example.com"}`,
-			expError: errors.New(`{"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
-			mockErr:  errors.New(`{"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"requestor","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"requestor","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
 		},
 		"failed with an input validation failure (target invalid)": {
 			input:    `{"requestor": "andy@example.com"}`,
-			expError: errors.New(`{"message":"Target field invalid format","success":false}`),
-			mockErr:  errors.New(`{"message":"Target field invalid format","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"target","message":"Target field invalid format"}],"message":"Target field invalid format","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"target","message":"Target field invalid format"}],"message":"Target field invalid format","success":false}`),
 		},
 		"failed with an input validation failure (requestor invalid)": {
 			input:    `{"target": "andy@example.com"}`,
-			expError: errors.New(`{"message":"Requestor field invalid format","success":false}`),
-			mockErr:  errors.New(`{"message":"Requestor field invalid format","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"requestor","message":"Requestor field invalid format"}],"message":"Requestor field invalid format","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"requestor","message":"Requestor field invalid format"}],"message":"Requestor field invalid format","success":false}`),
 		},
 	}
 
@@ -324,6 +383,7 @@ func TestControllers_CreateUserBlocks(t *testing.T) {
 		t.Run(desc, func(t *testing.T) {
 			req, err := http.NewRequest("POST", "/v1/blocking", bytes.NewBuffer([]byte(tc.input)))
 			require.NoError(t, err)
+			req = req.WithContext(auth.WithUser(req.Context(), tc.actor))
 
 			var mockService SpecService
 			mockService.ExpectedCalls = []*mock.Call{
@@ -346,18 +406,30 @@ func TestControllers_CreateUserBlocks(t *testing.T) {
 
 func TestControllers_GetRecipientEmails(t *testing.T) {
 	tcs := map[string]struct {
-		input               string
-		text                string
-		expResult           string
-		expError            error
-		mockRecipientEmails []string
-		mockErr             error
+		input          string
+		text           string
+		expResult      string
+		expError       error
+		mockRecipients services.Recipients
+		mockErr        error
 	}{
 		"success with an input": {
-			input:               `{"sender": "andy@example.com","text": "Hello World! kate@example.com"}`,
-			text:                "Hello World! kate@example.com",
-			mockRecipientEmails: []string{"lisa@example.com", "kate@example.com"},
-			expResult:           `{"recipients":["lisa@example.com","kate@example.com"],"success":true}`,
+			input: `{"sender": "andy@example.com","text": "Hello World! kate@example.com"}`,
+			text:  "Hello World! kate@example.com",
+			mockRecipients: services.Recipients{
+				Emails:     []string{"lisa@example.com", "kate@example.com"},
+				Unresolved: []string{},
+			},
+			expResult: `{"recipients":["lisa@example.com","kate@example.com"],"success":true,"unresolved_mentions":[]}`,
+		},
+		"success with unresolved mentions": {
+			input: `{"sender": "andy@example.com","text": "Hello kate@example.com, bob@example.com!"}`,
+			text:  "Hello kate@example.com, bob@example.com!",
+			mockRecipients: services.Recipients{
+				Emails:     []string{"lisa@example.com", "kate@example.com"},
+				Unresolved: []string{"bob@example.com"},
+			},
+			expResult: `{"recipients":["lisa@example.com","kate@example.com"],"success":true,"unresolved_mentions":["bob@example.com"]}`,
 		},
 		"failed with an unknow format input": {
 			input:    `{}`,
@@ -366,19 +438,19 @@ func TestControllers_GetRecipientEmails(t *testing.T) {
 		},
 		"failed with an input validation failure (email invalid format)": {
 			input:    `{"sender": "andy@examplecom","text": "Hello World! kate@example.com"}`,
-			expError: errors.New(`{"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
-			mockErr:  errors.New(`{"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"sender","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"sender","message":"andy@examplecom invalid format (ex: \"andy@example.com\")"}],"message":"andy@examplecom invalid format (ex: \"andy@example.com\")","success":false}`),
 		},
 
 		"failed with an input validation failure (target invalid)": {
 			input:    `{"sender": "andy@example.com"}`,
-			expError: errors.New(`{"message":"Text field invalid format","success":false}`),
-			mockErr:  errors.New(`{"message":"Text field invalid format","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"text","message":"Text field invalid format"}],"message":"Text field invalid format","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"text","message":"Text field invalid format"}],"message":"Text field invalid format","success":false}`),
 		},
 		"failed with an input validation failure (requestor invalid)": {
 			input:    `{"text": "Hello World! kate@example.com"}`,
-			expError: errors.New(`{"message":"Sender field invalid format","success":false}`),
-			mockErr:  errors.New(`{"message":"Sender field invalid format","success":false}`),
+			expError: errors.New(`{"fields":[{"field":"sender","message":"Sender field invalid format"}],"message":"Sender field invalid format","success":false}`),
+			mockErr:  errors.New(`{"fields":[{"field":"sender","message":"Sender field invalid format"}],"message":"Sender field invalid format","success":false}`),
 		},
 	}
 
@@ -390,7 +462,7 @@ func TestControllers_GetRecipientEmails(t *testing.T) {
 			var mockService SpecService
 			mockService.ExpectedCalls = []*mock.Call{
 				mockService.On("GetRecipientEmails", mock.Anything, mock.Anything, mock.Anything).
-					Return(tc.mockRecipientEmails, tc.mockErr),
+					Return(tc.mockRecipients, tc.mockErr),
 			}
 			friendController := NewFriendController(mockService)
 			handler := http.HandlerFunc(friendController.GetRecipientEmails)
@@ -406,3 +478,55 @@ func TestControllers_GetRecipientEmails(t *testing.T) {
 		})
 	}
 }
+
+func TestControllers_RespondError(t *testing.T) {
+	tcs := map[string]struct {
+		err       error
+		expStatus int
+		expResult string
+	}{
+		"unknown user": {
+			err:       errs.NewUserLookupError("andy@example.com", sql.ErrNoRows),
+			expStatus: http.StatusNotFound,
+			expResult: `{"message":"andy@example.com is not exists","success":false}`,
+		},
+		"existing relationship": {
+			err:       errs.NewConflictError(errs.CodeAlreadyFriends, errs.MsgExistedFriendship),
+			expStatus: http.StatusConflict,
+			expResult: `{"message":"The friend relationship has been existed","success":false}`,
+		},
+		"forbidden change": {
+			err:       errs.NewForbiddenError(errs.CodeForbidden, errs.MsgForbiddenUnblock),
+			expStatus: http.StatusForbidden,
+			expResult: `{"message":"Only the requestor of the block can unblock the target","success":false}`,
+		},
+		"invalid fields": {
+			err: &errs.ValidationError{Fields: []*errs.FieldError{
+				errs.NewEmailFieldError("target", "andy"),
+				{Err: errs.ErrUpdateFieldEmpty},
+			}},
+			expStatus: http.StatusBadRequest,
+			expResult: `{"fields":[{"field":"target","message":"andy invalid format (ex: \"andy@example.com\")"},{"message":"Name or new email must be provided"}],"message":"andy invalid format (ex: \"andy@example.com\"); Name or new email must be provided","success":false}`,
+		},
+		"failed storage hides its cause": {
+			err:       errs.NewUserLookupError("andy@example.com", errors.New("sql: database is closed")),
+			expStatus: http.StatusServiceUnavailable,
+			expResult: `{"message":"The service is temporarily unavailable, please try again later","success":false}`,
+		},
+		"unknown error": {
+			err:       errors.New("unexpected EOF"),
+			expStatus: http.StatusInternalServerError,
+			expResult: `{"message":"unexpected EOF","success":false}`,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			RespondError(rr, tc.err)
+
+			require.Equal(t, tc.expStatus, rr.Code)
+			require.Equal(t, tc.expResult, rr.Body.String())
+		})
+	}
+}
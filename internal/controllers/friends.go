@@ -6,26 +6,9 @@ import (
 	"net/http"
 
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/validation"
 )
 
-type FriendRequest struct {
-	Emails []string `json:"friends"`
-}
-
-type UserRequest struct {
-	Email string `json:"email"`
-}
-
-type RequestorRequest struct {
-	Requestor string `json:"requestor"`
-	Target    string `json:"target"`
-}
-
-type RecipientsRequest struct {
-	Sender string `json:"sender"`
-	Text   string `json:"text"`
-}
-
 // Get all of users
 func (_self FriendController) GetUsers(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
@@ -37,21 +20,18 @@ func (_self FriendController) GetUsers(w http.ResponseWriter, r *http.Request) {
 
 	emails, err := _self.Service.GetUsers(ctx)
 	if err != nil {
-		if friendErr, ok := err.(*errs.FriendError); ok && friendErr != nil {
-			Respond(w, friendErr.Code, MsgError(friendErr))
-			return
-		}
-		Respond(w, http.StatusInternalServerError, MsgError(err))
+		RespondError(w, err)
 		return
 	}
 
 	Respond(w, http.StatusOK, MsgGetAllUsersOk(emails, len(emails)))
 }
 
-// Create a new friend relationship
+// Send a friend request from the authenticated user to the other friend, the friendship is created once it is accepted
+// so the answer is 202 Accepted instead of the success of the original spec
 func (_self FriendController) CreateFriend(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	friendReq := FriendRequest{}
+	friendReq := validation.FriendRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&friendReq); err != nil {
 		Respond(w, http.StatusBadRequest, MsgError(errs.ErrBodyRequestInvalid))
 		return
@@ -63,22 +43,27 @@ func (_self FriendController) CreateFriend(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if err := _self.Service.CreateFriend(ctx, friendReq.Emails[0], friendReq.Emails[1]); err != nil {
-		if friendErr, ok := err.(*errs.FriendError); ok && friendErr != nil {
-			Respond(w, friendErr.Code, MsgError(friendErr))
-			return
-		}
-		Respond(w, http.StatusInternalServerError, MsgError(err))
+	requestor, ok := requireParty(w, r, friendReq.Emails...)
+	if !ok {
 		return
 	}
+	target := friendReq.Emails[1]
+	if requestor == target {
+		target = friendReq.Emails[0]
+	}
 
-	Respond(w, http.StatusOK, MsgOK())
+	if err := _self.Service.SendFriendRequest(ctx, requestor, target); err != nil {
+		RespondError(w, err)
+		return
+	}
+
+	Respond(w, http.StatusAccepted, MsgFriendRequestSent(target))
 }
 
 // Get all of friends of a user without blocking relationship
 func (_self FriendController) GetFriends(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	userReq := UserRequest{}
+	userReq := validation.UserRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&userReq); err != nil {
 		Respond(w, http.StatusBadRequest, MsgError(errs.ErrBodyRequestInvalid))
 		return
@@ -92,11 +77,7 @@ func (_self FriendController) GetFriends(w http.ResponseWriter, r *http.Request)
 
 	friendEmails, err := _self.Service.GetFriends(ctx, userReq.Email)
 	if err != nil {
-		if friendErr, ok := err.(*errs.FriendError); ok && friendErr != nil {
-			Respond(w, friendErr.Code, MsgError(friendErr))
-			return
-		}
-		Respond(w, http.StatusInternalServerError, MsgError(err))
+		RespondError(w, err)
 		return
 	}
 
@@ -106,7 +87,7 @@ func (_self FriendController) GetFriends(w http.ResponseWriter, r *http.Request)
 // Get common friends of 2 users
 func (_self FriendController) GetCommonFriends(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	friendReq := FriendRequest{}
+	friendReq := validation.FriendRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&friendReq); err != nil {
 		Respond(w, http.StatusBadRequest, MsgError(errs.ErrBodyRequestInvalid))
 		return
@@ -121,11 +102,7 @@ func (_self FriendController) GetCommonFriends(w http.ResponseWriter, r *http.Re
 	// Get common friends
 	commonFriends, err := _self.Service.GetCommonFriends(ctx, friendReq.Emails[0], friendReq.Emails[1])
 	if err != nil {
-		if friendErr, ok := err.(*errs.FriendError); ok && friendErr != nil {
-			Respond(w, friendErr.Code, MsgError(friendErr))
-			return
-		}
-		Respond(w, http.StatusInternalServerError, MsgError(err))
+		RespondError(w, err)
 		return
 	}
 
@@ -135,7 +112,7 @@ func (_self FriendController) GetCommonFriends(w http.ResponseWriter, r *http.Re
 // Create a subscription relationship of users
 func (_self FriendController) CreateSubcription(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	requestorReq := RequestorRequest{}
+	requestorReq := validation.RequestorRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&requestorReq); err != nil {
 		Respond(w, http.StatusBadRequest, MsgError(errs.ErrBodyRequestInvalid))
 		return
@@ -147,12 +124,12 @@ func (_self FriendController) CreateSubcription(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	if _, ok := requireParty(w, r, requestorReq.Requestor); !ok {
+		return
+	}
+
 	if err := _self.Service.CreateSubscription(ctx, requestorReq.Requestor, requestorReq.Target); err != nil {
-		if friendErr, ok := err.(*errs.FriendError); ok && friendErr != nil {
-			Respond(w, friendErr.Code, MsgError(friendErr))
-			return
-		}
-		Respond(w, http.StatusInternalServerError, MsgError(err))
+		RespondError(w, err)
 		return
 	}
 
@@ -162,7 +139,7 @@ func (_self FriendController) CreateSubcription(w http.ResponseWriter, r *http.R
 // Create a blocking relationship of users
 func (_self FriendController) CreateUserBlock(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	requestorReq := RequestorRequest{}
+	requestorReq := validation.RequestorRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&requestorReq); err != nil {
 		Respond(w, http.StatusBadRequest, MsgError(errs.ErrBodyRequestInvalid))
 		return
@@ -174,12 +151,12 @@ func (_self FriendController) CreateUserBlock(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if _, ok := requireParty(w, r, requestorReq.Requestor); !ok {
+		return
+	}
+
 	if err := _self.Service.CreateUserBlock(ctx, requestorReq.Requestor, requestorReq.Target); err != nil {
-		if friendErr, ok := err.(*errs.FriendError); ok && friendErr != nil {
-			Respond(w, friendErr.Code, MsgError(friendErr))
-			return
-		}
-		Respond(w, http.StatusInternalServerError, MsgError(err))
+		RespondError(w, err)
 		return
 	}
 
@@ -189,7 +166,7 @@ func (_self FriendController) CreateUserBlock(w http.ResponseWriter, r *http.Req
 // Get all of recipients who are friend, subscriber, and mention user without blocking by user
 func (_self FriendController) GetRecipientEmails(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	recipient := RecipientsRequest{}
+	recipient := validation.RecipientsRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&recipient); err != nil {
 		Respond(w, http.StatusBadRequest, MsgError(err))
 		return
@@ -203,13 +180,9 @@ func (_self FriendController) GetRecipientEmails(w http.ResponseWriter, r *http.
 
 	recipients, err := _self.Service.GetRecipientEmails(ctx, recipient.Sender, recipient.Text)
 	if err != nil {
-		if friendErr, ok := err.(*errs.FriendError); ok && friendErr != nil {
-			Respond(w, friendErr.Code, MsgError(friendErr))
-			return
-		}
-		Respond(w, http.StatusInternalServerError, MsgError(err))
+		RespondError(w, err)
 		return
 	}
 
-	Respond(w, http.StatusOK, MsgGetEmailReceiversOk(recipients))
+	Respond(w, http.StatusOK, MsgGetEmailReceiversOk(recipients.Emails, recipients.Unresolved))
 }
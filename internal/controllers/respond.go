@@ -2,33 +2,56 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
 )
 
 func MsgOK() map[string]interface{} {
 	return map[string]interface{}{"success": true}
 }
 
+// MsgError is the description of an error, a validation error lists every invalid field in "fields"
 func MsgError(err error) map[string]interface{} {
-	return map[string]interface{}{"message": err.Error(), "success": false}
+	msg := map[string]interface{}{"message": err.Error(), "success": false}
+	var validationErr *errs.ValidationError
+	if errors.As(err, &validationErr) {
+		msg["fields"] = validationErr.Fields
+	}
+	return msg
 }
 
 func Message(status bool, msg string) map[string]interface{} {
 	return map[string]interface{}{"message": msg, "success": status}
 }
 
+// MsgFriendRequestSent tells that the friendship is pending, it is not a success for the clients expecting the friendship at once
+func MsgFriendRequestSent(target string) map[string]interface{} {
+	msg := Message(false, fmt.Sprintf("A friend request is sent to %s, the friendship is created once it is accepted", target))
+	msg["pending"] = true
+	return msg
+}
+
 func MsgGetFriendsOk(friends []string, count int) interface{} {
 	return map[string]interface{}{"count": count, "friends": friends, "success": true}
 }
 
-func MsgGetEmailReceiversOk(emails []string) interface{} {
-	return map[string]interface{}{"recipients": emails, "success": true}
+// MsgGetEmailReceiversOk lists the recipients of an update and the mentions which receive nothing
+func MsgGetEmailReceiversOk(emails []string, unresolvedMentions []string) interface{} {
+	return map[string]interface{}{"recipients": emails, "success": true, "unresolved_mentions": unresolvedMentions}
 }
 
 func MsgGetAllUsersOk(users []string, count int) interface{} {
 	return map[string]interface{}{"count": count, "users": users, "success": true}
 }
 
+// RespondError writes the description of an error with the HTTP status of its kind
+func RespondError(w http.ResponseWriter, err error) {
+	Respond(w, errs.HTTPStatus(err), MsgError(err))
+}
+
 func Respond(w http.ResponseWriter, statusCode int, payload interface{}) {
 	response, _ := json.Marshal(payload)
 	w.Header().Add("Content-Type", "application/json")
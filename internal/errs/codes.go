@@ -0,0 +1,153 @@
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Machine-readable codes of the errors, clients match on them instead of the descriptions
+const (
+	CodeValidationFailed      = "VALIDATION_FAILED"
+	CodeUnauthenticated       = "UNAUTHENTICATED"
+	CodeForbidden             = "FORBIDDEN"
+	CodeInvalidCredentials    = "INVALID_CREDENTIALS"
+	CodeUserNotFound          = "USER_NOT_FOUND"
+	CodeEmailTaken            = "EMAIL_TAKEN"
+	CodeAlreadyFriends        = "ALREADY_FRIENDS"
+	CodeAlreadySubscribed     = "ALREADY_SUBSCRIBED"
+	CodeBlocked               = "BLOCKED"
+	CodeFriendRequestExists   = "FRIEND_REQUEST_EXISTS"
+	CodeFriendshipNotFound    = "FRIENDSHIP_NOT_FOUND"
+	CodeSubscriptionNotFound  = "SUBSCRIPTION_NOT_FOUND"
+	CodeBlockNotFound         = "BLOCK_NOT_FOUND"
+	CodeFriendRequestNotFound = "FRIEND_REQUEST_NOT_FOUND"
+	CodeWebhookNotFound       = "WEBHOOK_NOT_FOUND"
+	CodeNotFound              = "NOT_FOUND"
+	CodeConflict              = "CONFLICT"
+	CodeUnavailable           = "SERVICE_UNAVAILABLE"
+	CodeInternal              = "INTERNAL_SERVER_ERROR"
+)
+
+// Codes of the sentinel errors of the access rules
+var sentinelCodes = map[error]string{
+	ErrUnauthenticated: CodeUnauthenticated,
+	ErrForbiddenActor:  CodeForbidden,
+	ErrForbiddenRole:   CodeForbidden,
+	ErrForbiddenOwner:  CodeForbidden,
+}
+
+// Sentinel errors of an invalid request
+var validationErrors = []error{
+	ErrBodyRequestInvalid, ErrBodyRequestEmpty, ErrNumberOfEmail, ErrDifferentEmail, ErrRequestorFieldInvalid,
+	ErrTargetFieldInvalid, ErrSenderFieldInvalid, ErrTextFieldInvalid, ErrNameFieldInvalid, ErrUpdateFieldEmpty,
+	ErrPasswordFieldInvalid, ErrPageSizeInvalid, ErrPageDirectionInvalid, ErrCursorInvalid, ErrWebhookURLInvalid,
+	ErrWebhookSecretInvalid, ErrWebhookEventInvalid,
+}
+
+// FieldError is an invalid field of a request, Field is the path of the field in the request like "friends[1]",
+// it is empty when the problem is about the whole request
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+func (e *FieldError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// MarshalJSON shows the path of the field and the description of the problem to the clients
+func (e *FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Field   string `json:"field,omitempty"`
+		Message string `json:"message"`
+	}{e.Field, e.Error()})
+}
+
+// NewEmailFieldError is the error of a field holding an invalid email
+func NewEmailFieldError(field string, email string) *FieldError {
+	return &FieldError{Field: field, Err: errors.New(email + " invalid format (ex: \"andy@example.com\")")}
+}
+
+// ValidationError holds every invalid field of a request, so the clients can fix them at once
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is tells whether the target is ErrValidation or the error of one of the fields
+func (e *ValidationError) Is(target error) bool {
+	if target == ErrValidation {
+		return true
+	}
+	for _, field := range e.Fields {
+		if errors.Is(field.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorCode is the code given to the error by its constructor, the errors built without a code
+// are coded by their kind
+func (e *FriendError) ErrorCode() string {
+	if e.ErrCode != "" {
+		return e.ErrCode
+	}
+	switch {
+	case errors.Is(e, ErrNotFound):
+		return CodeNotFound
+	case errors.Is(e, ErrConflict):
+		return CodeConflict
+	case errors.Is(e, ErrForbidden):
+		return CodeForbidden
+	case errors.Is(e, ErrUnavailable):
+		return CodeUnavailable
+	case e.Code == http.StatusBadRequest:
+		return CodeValidationFailed
+	}
+	return CodeInternal
+}
+
+// Code is the code of an error of this package, it is empty for the other errors
+func Code(err error) string {
+	var friendErr *FriendError
+	if errors.As(err, &friendErr) {
+		return friendErr.ErrorCode()
+	}
+	for sentinel, code := range sentinelCodes {
+		if errors.Is(err, sentinel) {
+			return code
+		}
+	}
+	if errors.Is(err, ErrValidation) || isValidationError(err) {
+		return CodeValidationFailed
+	}
+	return ""
+}
+
+// isValidationError tells whether the error is one of the sentinel errors of an invalid request
+func isValidationError(err error) bool {
+	for _, sentinel := range validationErrors {
+		if errors.Is(err, sentinel) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,8 +1,10 @@
 package errs
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
+	"net/http"
 )
 
 var (
@@ -14,18 +16,136 @@ var (
 	ErrTargetFieldInvalid    = errors.New("Target field invalid format")
 	ErrSenderFieldInvalid    = errors.New("Sender field invalid format")
 	ErrTextFieldInvalid      = errors.New("Text field invalid format")
+	ErrNameFieldInvalid      = errors.New("Name field invalid format")
+	ErrUpdateFieldEmpty      = errors.New("Name or new email must be provided")
+	ErrPasswordFieldInvalid  = errors.New("Password must have from 8 to 72 characters")
+	ErrUnauthenticated       = errors.New("Access denied, a valid token is required")
+	ErrForbiddenActor        = errors.New("The authenticated user has to be one of the parties")
+	ErrForbiddenRole         = errors.New("Access denied, the role of the authenticated user is not allowed")
+	ErrForbiddenOwner        = errors.New("Access denied, the field is only visible to its user")
+	ErrPageSizeInvalid       = errors.New("First and last must be from 0 to 100")
+	ErrPageDirectionInvalid  = errors.New("First and last cannot be used together")
+	ErrCursorInvalid         = errors.New("Cursor invalid format")
+	ErrWebhookURLInvalid     = errors.New("URL must be an absolute http or https URL")
+	ErrWebhookSecretInvalid  = errors.New("Secret must have from 16 to 256 characters")
+	ErrWebhookEventInvalid   = errors.New("Event invalid format")
 
-	MsgExistedFriendship   = "The friend relationship has been existed"
-	MsgExistedBlockedUser  = "The users have blocked each other"
-	MsgExistedSubscription = "The users have subscribed each other"
-	MsgCreatedFriendship   = "Users cannot be created a new friendship"
+	MsgExistedFriendship       = "The friend relationship has been existed"
+	MsgExistedBlockedUser      = "The users have blocked each other"
+	MsgExistedSubscription     = "The users have subscribed each other"
+	MsgNotExistedFriendship    = "The friend relationship does not exist"
+	MsgNotExistedSubscription  = "The subscription does not exist"
+	MsgNotExistedBlockedUser   = "The blocking relationship does not exist"
+	MsgForbiddenUnblock        = "Only the requestor of the block can unblock the target"
+	MsgExistedFriendRequest    = "The pending friend request has been existed"
+	MsgNotExistedFriendRequest = "The pending friend request does not exist"
+	MsgExistedEmail            = "has been used by another user"
+	MsgInvalidCredentials      = "Email or password is incorrect"
+	MsgNotExistedUser          = "is not exists"
+	MsgNotExistedWebhook       = "The webhook does not exist"
+	MsgUnavailable             = "The service is temporarily unavailable, please try again later"
+	MsgInternal                = "Internal server error"
 )
 
+// Kinds of the failures, a FriendError is errors.Is its kind and errors.As its cause
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrForbidden   = errors.New("forbidden")
+	ErrValidation  = errors.New("validation failed")
+	ErrUnavailable = errors.New("unavailable")
+	ErrInternal    = errors.New("internal error")
+)
+
+// FriendError is a failure of the service, Description is shown to the clients while the cause is only logged.
+// ErrCode is the machine-readable code of the failure, clients match on it instead of the description.
 type FriendError struct {
 	Code        int    `json:"-"`
+	ErrCode     string `json:"-"`
 	Description string `json:"error_description"`
+	Kind        error  `json:"-"`
+	Err         error  `json:"-"`
 }
 
 func (e *FriendError) Error() string {
 	return fmt.Sprintf("%s", e.Description)
 }
+
+func (e *FriendError) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+func (e *FriendError) Unwrap() error {
+	return e.Err
+}
+
+// NewNotFoundError is the error of a missing user or relationship
+func NewNotFoundError(code string, description string) *FriendError {
+	return &FriendError{Code: http.StatusNotFound, ErrCode: code, Description: description, Kind: ErrNotFound}
+}
+
+// NewConflictError is the error of a relationship or an email which already exists
+func NewConflictError(code string, description string) *FriendError {
+	return &FriendError{Code: http.StatusConflict, ErrCode: code, Description: description, Kind: ErrConflict}
+}
+
+// NewForbiddenError is the error of a change which is not allowed to the user
+func NewForbiddenError(code string, description string) *FriendError {
+	return &FriendError{Code: http.StatusForbidden, ErrCode: code, Description: description, Kind: ErrForbidden}
+}
+
+// NewUnavailableError is the error of a failed storage, the cause is hidden from the clients
+func NewUnavailableError(cause error) *FriendError {
+	return &FriendError{Code: http.StatusServiceUnavailable, ErrCode: CodeUnavailable, Description: MsgUnavailable,
+		Kind: ErrUnavailable, Err: cause}
+}
+
+// NewInternalError is the error of an unexpected failure, the cause is hidden from the clients
+func NewInternalError(cause error) *FriendError {
+	return &FriendError{Code: http.StatusInternalServerError, ErrCode: CodeInternal, Description: MsgInternal,
+		Kind: ErrInternal, Err: cause}
+}
+
+// NewUserLookupError is the error of a failed lookup of the user having email, sql.ErrNoRows means the user
+// does not exist and the other errors are failures of the storage
+func NewUserLookupError(email string, err error) *FriendError {
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound := NewNotFoundError(CodeUserNotFound, email+" "+MsgNotExistedUser)
+		notFound.Err = err
+		return notFound
+	}
+	return NewUnavailableError(err)
+}
+
+// NewEmailTakenError is the error of an email which is the email of another user
+func NewEmailTakenError(email string) *FriendError {
+	return NewConflictError(CodeEmailTaken, email+" "+MsgExistedEmail)
+}
+
+// NewInvalidCredentialsError is the error of a login with an unknown email or a wrong password
+func NewInvalidCredentialsError() *FriendError {
+	return &FriendError{Code: http.StatusUnauthorized, ErrCode: CodeInvalidCredentials, Description: MsgInvalidCredentials}
+}
+
+// HTTPStatus is the status of the kind of an error, the errors of an invalid request are 400
+// and the unknown errors 500
+func HTTPStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, ErrValidation), isValidationError(err):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrUnavailable):
+		return http.StatusServiceUnavailable
+	}
+
+	var friendErr *FriendError
+	if errors.As(err, &friendErr) && friendErr.Code != 0 {
+		return friendErr.Code
+	}
+	return http.StatusInternalServerError
+}
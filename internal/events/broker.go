@@ -0,0 +1,95 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Kind is the topic of an event, a subscription receives a single kind
+type Kind string
+
+const (
+	FriendAdded     Kind = "friendAdded"
+	FriendRemoved   Kind = "friendRemoved"
+	Blocked         Kind = "blocked"
+	UpdatePublished Kind = "updatePublished"
+)
+
+// BufferSize is the number of events a subscriber can fall behind, later events are dropped for it
+const BufferSize = 16
+
+// Event is a change of the relationships or the updates of a user, it is delivered to the subscribers of Email
+type Event struct {
+	Kind  Kind
+	Email string
+	// Other party of a friendship
+	Friend string
+	// Parties of a block, one of them is Email
+	Requestor string
+	Target    string
+	// Sender and text of an update
+	Sender string
+	Text   string
+
+	CreatedAt time.Time
+}
+
+// topic identifies the subscribers of an event
+type topic struct {
+	kind  Kind
+	email string
+}
+
+// Broker delivers the events published in this process to the subscribers of their kind and user.
+// Publishing never waits for a subscriber, one which is not keeping up misses events.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[topic]map[chan Event]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: map[topic]map[chan Event]struct{}{}}
+}
+
+// Publish delivers an event to the current subscribers of its kind and user
+func (_self *Broker) Publish(ctx context.Context, event Event) {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	_self.mu.RLock()
+	defer _self.mu.RUnlock()
+	for ch := range _self.subscribers[topic{event.Kind, event.Email}] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns the events of a kind for a user, the channel is closed once ctx is done
+func (_self *Broker) Subscribe(ctx context.Context, kind Kind, email string) <-chan Event {
+	key := topic{kind, email}
+	ch := make(chan Event, BufferSize)
+
+	_self.mu.Lock()
+	if _self.subscribers[key] == nil {
+		_self.subscribers[key] = map[chan Event]struct{}{}
+	}
+	_self.subscribers[key][ch] = struct{}{}
+	_self.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		_self.mu.Lock()
+		defer _self.mu.Unlock()
+		delete(_self.subscribers[key], ch)
+		if len(_self.subscribers[key]) == 0 {
+			delete(_self.subscribers, key)
+		}
+		close(ch)
+	}()
+	return ch
+}
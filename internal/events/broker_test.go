@@ -0,0 +1,75 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker_Publish(t *testing.T) {
+	tcs := map[string]struct {
+		kind      Kind
+		email     string
+		event     Event
+		expEvents []Event
+	}{
+		"event of the kind and user of the subscription": {
+			kind:      FriendAdded,
+			email:     "andy@example.com",
+			event:     Event{Kind: FriendAdded, Email: "andy@example.com", Friend: "john@example.com"},
+			expEvents: []Event{{Kind: FriendAdded, Email: "andy@example.com", Friend: "john@example.com"}},
+		},
+		"event of another user": {
+			kind:      FriendAdded,
+			email:     "andy@example.com",
+			event:     Event{Kind: FriendAdded, Email: "john@example.com", Friend: "andy@example.com"},
+			expEvents: []Event{},
+		},
+		"event of another kind": {
+			kind:      FriendAdded,
+			email:     "andy@example.com",
+			event:     Event{Kind: FriendRemoved, Email: "andy@example.com", Friend: "john@example.com"},
+			expEvents: []Event{},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			broker := NewBroker()
+			ctx, cancel := context.WithCancel(context.Background())
+			ch := broker.Subscribe(ctx, tc.kind, tc.email)
+
+			broker.Publish(context.Background(), tc.event)
+			cancel()
+
+			result := []Event{}
+			for event := range ch {
+				require.False(t, event.CreatedAt.IsZero())
+				event.CreatedAt = time.Time{}
+				result = append(result, event)
+			}
+			require.Equal(t, tc.expEvents, result)
+		})
+	}
+}
+
+func TestBroker_SlowSubscriber(t *testing.T) {
+	broker := NewBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := broker.Subscribe(ctx, Blocked, "andy@example.com")
+
+	// publishing does not wait for a full subscriber
+	for i := 0; i < BufferSize+5; i++ {
+		broker.Publish(context.Background(), Event{Kind: Blocked, Email: "andy@example.com"})
+	}
+	cancel()
+
+	count := 0
+	for range ch {
+		count++
+	}
+	require.Equal(t, BufferSize, count)
+	require.Empty(t, broker.subscribers)
+}
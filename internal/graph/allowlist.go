@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	gqlparser "github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Code of the error of an operation refused by Allowlist
+const ErrCodeOperationNotAllowed = "OPERATION_NOT_ALLOWED"
+
+// A refused operation is answered with status 422 like a query failing validation
+func init() {
+	errcode.RegisterErrorType(ErrCodeOperationNotAllowed, errcode.KindProtocol)
+}
+
+// Allowlist only runs the operations of Queries, which maps the sha256 hash (hex) of each query to the query.
+// A client sends either the query or only its hash in the "persistedQuery" extension like with automatic
+// persisted queries, the hashes cannot be registered by the clients though.
+// The queries are validated against the schema when the extension is added to the server.
+type Allowlist struct {
+	Queries map[string]string
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = Allowlist{}
+
+func (_self Allowlist) ExtensionName() string {
+	return "Allowlist"
+}
+
+func (_self Allowlist) Validate(schema graphql.ExecutableSchema) error {
+	hashes := make([]string, 0, len(_self.Queries))
+	for hash := range _self.Queries {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+
+	for _, hash := range hashes {
+		if _, errs := gqlparser.LoadQuery(schema.Schema(), _self.Queries[hash]); len(errs) > 0 {
+			return fmt.Errorf("allowlist query %s invalid: %w", hash, errs[0])
+		}
+	}
+	return nil
+}
+
+func (_self Allowlist) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	hash := persistedQueryHash(rawParams.Extensions)
+	if rawParams.Query != "" {
+		sum := sha256.Sum256([]byte(rawParams.Query))
+		if hash != "" && hash != hex.EncodeToString(sum[:]) {
+			return errOperationNotAllowed()
+		}
+		hash = hex.EncodeToString(sum[:])
+	}
+
+	query, ok := _self.Queries[hash]
+	if !ok {
+		return errOperationNotAllowed()
+	}
+	rawParams.Query = query
+	return nil
+}
+
+// persistedQueryHash is the hash of the "persistedQuery" extension of a request, it is empty without the extension
+func persistedQueryHash(extensions map[string]interface{}) string {
+	persistedQuery, _ := extensions["persistedQuery"].(map[string]interface{})
+	hash, _ := persistedQuery["sha256Hash"].(string)
+	return hash
+}
+
+func errOperationNotAllowed() *gqlerror.Error {
+	err := gqlerror.Errorf("operation is not in the allowlist")
+	errcode.Set(err, ErrCodeOperationNotAllowed)
+	return err
+}
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowlist(t *testing.T) {
+	// hashes of "{ __typename }" and "{ __schema { queryType { name } } }"
+	typenameHash := "7f56e67dd21ab3f30d1ff8b7bed08893f0a0db86449836189b361dd1e56ddb4b"
+	schemaHash := "3158fa8cd4c4b15c9b6bae16e2b19ee8ecde105ee3b48f444c48391d30c6132e"
+
+	tcs := map[string]struct {
+		body      string
+		expStatus int
+		expData   string
+		expError  string
+	}{
+		"success with a query of the allowlist": {
+			body:      `{"query":"{ __typename }"}`,
+			expStatus: http.StatusOK,
+			expData:   `{"__typename":"Query"}`,
+		},
+		"success with the hash of a query of the allowlist": {
+			body:      `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + typenameHash + `"}}}`,
+			expStatus: http.StatusOK,
+			expData:   `{"__typename":"Query"}`,
+		},
+		"success with a query of the allowlist and its hash": {
+			body:      `{"query":"{ __typename }","extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + typenameHash + `"}}}`,
+			expStatus: http.StatusOK,
+			expData:   `{"__typename":"Query"}`,
+		},
+		"failed with a query out of the allowlist": {
+			body:      `{"query":"{ __schema { queryType { name } } }"}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expError:  "operation is not in the allowlist",
+		},
+		"failed with an unknown hash": {
+			body:      `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + schemaHash + `"}}}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expError:  "operation is not in the allowlist",
+		},
+		"failed with a hash of the allowlist sent with another query": {
+			body:      `{"query":"{ __schema { queryType { name } } }","extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + typenameHash + `"}}}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expError:  "operation is not in the allowlist",
+		},
+		"failed without query": {
+			body:      `{}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expError:  "operation is not in the allowlist",
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			cfg := config.DefaultGraphQLConfig()
+			cfg.Allowlist = map[string]string{typenameHash: "{ __typename }"}
+			srv := NewServer(&Resolver{}, nil, cfg)
+
+			req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			srv.ServeHTTP(rr, req)
+			require.Equal(t, tc.expStatus, rr.Code)
+
+			resp := struct {
+				Data   json.RawMessage
+				Errors []struct {
+					Message    string
+					Extensions map[string]interface{}
+				}
+			}{}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tc.expError != "" {
+				require.Len(t, resp.Errors, 1)
+				require.Equal(t, tc.expError, resp.Errors[0].Message)
+				require.Equal(t, ErrCodeOperationNotAllowed, resp.Errors[0].Extensions["code"])
+			} else {
+				require.Empty(t, resp.Errors)
+				require.JSONEq(t, tc.expData, string(resp.Data))
+			}
+		})
+	}
+}
+
+func TestAllowlist_Validate(t *testing.T) {
+	cfg := config.DefaultGraphQLConfig()
+	cfg.Allowlist = map[string]string{"5d1b": "{ unknownField }"}
+
+	require.PanicsWithError(t, `allowlist query 5d1b invalid: input:1: Cannot query field "unknownField" on type "Query".`, func() {
+		NewServer(&Resolver{}, nil, cfg)
+	})
+}
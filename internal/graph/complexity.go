@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"math"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/generated"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/graphmodel"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+)
+
+// Complexity costs a list field as its selection repeated for each item of the list, a paginated list has
+// the number of items asked by "first" or "last" and the other lists are counted with the default page size.
+// The other fields cost 1 plus their selection.
+func Complexity() generated.ComplexityRoot {
+	var root generated.ComplexityRoot
+
+	root.Query.Users = func(childComplexity int, first *int, after *string, last *int, before *string) int {
+		return pageComplexity(childComplexity, first, last)
+	}
+	root.Query.FriendList = func(childComplexity int, input graphmodel.EmailInput, first *int, after *string, last *int, before *string) int {
+		return pageComplexity(childComplexity, first, last)
+	}
+	root.Query.RetrieveEmailReceiveUpdate = func(childComplexity int, input graphmodel.SendMail, first *int, after *string, last *int, before *string) int {
+		return pageComplexity(childComplexity, first, last)
+	}
+	root.Query.CommonFriends = func(childComplexity int, input graphmodel.Friends) int {
+		return listComplexity(childComplexity, pagination.DefaultSize)
+	}
+	root.Query.Webhooks = func(childComplexity int) int {
+		return listComplexity(childComplexity, pagination.DefaultSize)
+	}
+	root.Query.WebhookDeliveries = func(childComplexity int, webhookID string, first *int) int {
+		return pageComplexity(childComplexity, first, nil)
+	}
+
+	root.Mutation.FriendList = func(childComplexity int, input graphmodel.EmailInput) int {
+		return listComplexity(childComplexity, pagination.DefaultSize)
+	}
+	root.Mutation.CommonFriends = func(childComplexity int, input graphmodel.Friends) int {
+		return listComplexity(childComplexity, pagination.DefaultSize)
+	}
+	root.Mutation.RetrieveEmailReceiveUpdate = func(childComplexity int, input graphmodel.SendMail) int {
+		return listComplexity(childComplexity, pagination.DefaultSize)
+	}
+
+	root.User.Friends = func(childComplexity int, first *int, after *string, last *int, before *string) int {
+		return pageComplexity(childComplexity, first, last)
+	}
+	root.User.Subscribers = func(childComplexity int, first *int, after *string, last *int, before *string) int {
+		return pageComplexity(childComplexity, first, last)
+	}
+	root.User.Subscriptions = func(childComplexity int, first *int, after *string, last *int, before *string) int {
+		return pageComplexity(childComplexity, first, last)
+	}
+	root.User.BlockedUsers = func(childComplexity int, first *int, after *string, last *int, before *string) int {
+		return pageComplexity(childComplexity, first, last)
+	}
+	root.User.MutualFriends = func(childComplexity int, with string) int {
+		return listComplexity(childComplexity, pagination.DefaultSize)
+	}
+	return root
+}
+
+// pageComplexity is the cost of a paginated list, a size out of range is counted as the largest page
+// since the query is refused by its resolver anyway
+func pageComplexity(childComplexity int, first *int, last *int) int {
+	size := pagination.DefaultSize
+	if first != nil {
+		size = *first
+	}
+	if last != nil {
+		size = *last
+	}
+	if size < 0 || size > pagination.MaxSize {
+		size = pagination.MaxSize
+	}
+	return listComplexity(childComplexity, size)
+}
+
+// listComplexity is the cost of a list of size items, it stops growing at math.MaxInt
+func listComplexity(childComplexity int, size int) int {
+	if size > 0 && childComplexity > (math.MaxInt-1)/size {
+		return math.MaxInt
+	}
+	return 1 + size*childComplexity
+}
@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/graphmodel"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/services"
+)
+
+// Edges of the emails of a page, the cursor of an edge is the one of its email
+func newEdges(result pagination.Result) []*graphmodel.EmailEdge {
+	edges := make([]*graphmodel.EmailEdge, len(result.Emails))
+	for i, email := range result.Emails {
+		edges[i] = &graphmodel.EmailEdge{
+			Cursor: pagination.EncodeCursor(email),
+			Node:   email,
+		}
+	}
+	return edges
+}
+
+// Page info of a page, the cursors are null on an empty page
+func newPageInfo(result pagination.Result) *graphmodel.PageInfo {
+	pageInfo := &graphmodel.PageInfo{
+		HasNextPage:     result.HasNextPage,
+		HasPreviousPage: result.HasPreviousPage,
+	}
+	if len(result.Emails) > 0 {
+		startCursor := pagination.EncodeCursor(result.Emails[0])
+		endCursor := pagination.EncodeCursor(result.Emails[len(result.Emails)-1])
+		pageInfo.StartCursor = &startCursor
+		pageInfo.EndCursor = &endCursor
+	}
+	return pageInfo
+}
+
+// User of the schema from the profile of a user
+func newUser(user services.User) *graphmodel.User {
+	return &graphmodel.User{
+		ID:        strconv.Itoa(user.ID),
+		Name:      user.Name,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+// usersOf loads the users of a list of emails in its order, emails which are not the one of a user are left out
+func (r *Resolver) usersOf(ctx context.Context, emails []string) ([]*graphmodel.User, error) {
+	profiles, err := r.Service.GetUsersByEmails(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+
+	byEmail := make(map[string]services.User, len(profiles))
+	for _, profile := range profiles {
+		byEmail[profile.Email] = profile
+	}
+	users := make([]*graphmodel.User, 0, len(emails))
+	for _, email := range emails {
+		if profile, ok := byEmail[email]; ok {
+			users = append(users, newUser(profile))
+		}
+	}
+	return users, nil
+}
+
+// userConnection loads the users of a page, the page info is the one of the emails
+func (r *Resolver) userConnection(ctx context.Context, result pagination.Result) (*graphmodel.UserConnection, error) {
+	users, err := r.usersOf(ctx, result.Emails)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*graphmodel.UserEdge, len(users))
+	for i, user := range users {
+		edges[i] = &graphmodel.UserEdge{
+			Cursor: pagination.EncodeCursor(user.Email),
+			Node:   user,
+		}
+	}
+	return &graphmodel.UserConnection{
+		Edges:      edges,
+		PageInfo:   newPageInfo(result),
+		TotalCount: result.TotalCount,
+	}, nil
+}
@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/auth"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/generated"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/graphmodel"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/utils"
+)
+
+// Directives implements the access rules declared in the schema
+func (r *Resolver) Directives() generated.DirectiveRoot {
+	return generated.DirectiveRoot{
+		Auth:    r.authDirective,
+		HasRole: r.hasRoleDirective,
+		IsSelf:  r.isSelfDirective,
+		IsOwner: r.isOwnerDirective,
+	}
+}
+
+// @auth, the request has to be authenticated
+func (r *Resolver) authDirective(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+	if auth.ForContext(ctx) == "" {
+		return nil, errs.ErrUnauthenticated
+	}
+	return next(ctx)
+}
+
+// @hasRole, the authenticated user has to have the role
+func (r *Resolver) hasRoleDirective(ctx context.Context, obj interface{}, next graphql.Resolver, role graphmodel.Role) (interface{}, error) {
+	actor := auth.ForContext(ctx)
+	if actor == "" {
+		return nil, errs.ErrUnauthenticated
+	}
+
+	actorRole, err := r.Service.GetUserRole(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	if actorRole != role.String() {
+		return nil, errs.ErrForbiddenRole
+	}
+	return next(ctx)
+}
+
+// @isSelf, the authenticated user has to be the email of the argument, or one of the emails of a list argument
+func (r *Resolver) isSelfDirective(ctx context.Context, obj interface{}, next graphql.Resolver, arg string) (interface{}, error) {
+	actor := auth.ForContext(ctx)
+	if actor == "" {
+		return nil, errs.ErrUnauthenticated
+	}
+
+	value, _ := lookupArg(graphql.GetFieldContext(ctx).Args, arg)
+	var emails []string
+	switch value := value.(type) {
+	case string:
+		emails = []string{value}
+	case []string:
+		emails = value
+	}
+
+	invalid := false
+	for _, email := range emails {
+		canonical, err := utils.ParseEmail(email)
+		if err != nil {
+			invalid = true
+			continue
+		}
+		if canonical == actor {
+			return next(ctx)
+		}
+	}
+	// An invalid email cannot be the user, the resolver refuses it with the other invalid fields of its input
+	if invalid {
+		return next(ctx)
+	}
+	return nil, errs.ErrForbiddenActor
+}
+
+// @isOwner, the authenticated user has to be the user of the object holding the field
+func (r *Resolver) isOwnerDirective(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+	actor := auth.ForContext(ctx)
+	if actor == "" {
+		return nil, errs.ErrUnauthenticated
+	}
+
+	if user, ok := obj.(*graphmodel.User); ok && user.Email == actor {
+		return next(ctx)
+	}
+	return nil, errs.ErrForbiddenOwner
+}
+
+// lookupArg finds an argument of a field by name, it is either a top level argument
+// or a field of an input object argument such as "requestor" of RequestTarget
+func lookupArg(args map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := args[name]; ok {
+		return value, true
+	}
+
+	for _, arg := range args {
+		value := reflect.ValueOf(arg)
+		if value.Kind() == reflect.Ptr {
+			value = value.Elem()
+		}
+		if value.Kind() != reflect.Struct {
+			continue
+		}
+		for i := 0; i < value.NumField(); i++ {
+			tag := strings.Split(value.Type().Field(i).Tag.Get("json"), ",")[0]
+			if tag == name {
+				return value.Field(i).Interface(), true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/auth"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/generated"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/services"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newDirectiveClient serves the schema with its directives as the actor, an empty actor is an anonymous request
+func newDirectiveClient(mockService SpecService, actor string) *client.Client {
+	resolver := &Resolver{
+		Service: mockService,
+	}
+	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
+		Resolvers:  resolver,
+		Directives: resolver.Directives(),
+	}))
+
+	return client.New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor != "" {
+			r = r.WithContext(auth.WithUser(r.Context(), actor))
+		}
+		srv.ServeHTTP(w, r)
+	}))
+}
+
+func TestDirectives(t *testing.T) {
+	tcs := map[string]struct {
+		actor    string
+		query    string
+		mockRole string
+		mockErr  error
+		expError error
+	}{
+		"@auth success with an authenticated user": {
+			actor: "andy@example.com",
+			query: `{ users { success } }`,
+		},
+		"@auth failed with an anonymous request": {
+			query:    `{ users { success } }`,
+			expError: errors.New(`[{"message":"Access denied, a valid token is required","path":["users"]}]`),
+		},
+		"@isSelf success with the requestor": {
+			actor: "andy@example.com",
+			query: `mutation { subscribe(input: {requestor: "andy@example.com", target: "lisa@example.com"}) { success } }`,
+		},
+		"@isSelf success with the canonical email of the requestor": {
+			actor: "andy@example.com",
+			query: `mutation { subscribe(input: {requestor: " andy@Example.COM ", target: "lisa@example.com"}) { success } }`,
+		},
+		"@isSelf failed with the target": {
+			actor:    "lisa@example.com",
+			query:    `mutation { subscribe(input: {requestor: "andy@example.com", target: "lisa@example.com"}) { success } }`,
+			expError: errors.New(`[{"message":"The authenticated user has to be one of the parties","path":["subscribe"]}]`),
+		},
+		"@isSelf failed with an anonymous request": {
+			query:    `mutation { subscribe(input: {requestor: "andy@example.com", target: "lisa@example.com"}) { success } }`,
+			expError: errors.New(`[{"message":"Access denied, a valid token is required","path":["subscribe"]}]`),
+		},
+		"@isSelf success with one of a list of emails": {
+			actor: "john@example.com",
+			query: `mutation { unfriend(input: {friends: ["andy@example.com", "john@example.com"]}) { success } }`,
+		},
+		"@isSelf failed with none of a list of emails": {
+			actor:    "lisa@example.com",
+			query:    `mutation { unfriend(input: {friends: ["andy@example.com", "john@example.com"]}) { success } }`,
+			expError: errors.New(`[{"message":"The authenticated user has to be one of the parties","path":["unfriend"]}]`),
+		},
+		"@isSelf leaves an invalid email to the validation of the resolver": {
+			actor:    "lisa@example.com",
+			query:    `mutation { unfriend(input: {friends: ["andy", "john@example.com"]}) { success } }`,
+			expError: errors.New(`[{"message":"andy invalid format (ex: \"andy@example.com\")","path":["unfriend"]}]`),
+		},
+		"@isSelf success with the target accepting a friend request": {
+			actor: "kate@example.com",
+			query: `mutation { acceptFriendRequest(input: {requestor: "andy@example.com", target: "kate@example.com"}) { success } }`,
+		},
+		"@isSelf success with a query argument": {
+			actor: "andy@example.com",
+			query: `{ incomingFriendRequests(input: {email: "andy@example.com"}) { success } }`,
+		},
+		"@isOwner success with the user of the field": {
+			actor: "andy@example.com",
+			query: `{ me { blockedUsers { totalCount } } }`,
+		},
+		"@isOwner failed with another user": {
+			actor:    "lisa@example.com",
+			query:    `{ user(email: "andy@example.com") { blockedUsers { totalCount } } }`,
+			expError: errors.New(`[{"message":"Access denied, the field is only visible to its user","path":["user","blockedUsers"]}]`),
+		},
+		"@hasRole success with an admin": {
+			actor:    "common@example.com",
+			query:    `mutation { deleteUser(input: {email: "andy@example.com"}) { success } }`,
+			mockRole: "ADMIN",
+		},
+		"@hasRole failed with a user": {
+			actor:    "andy@example.com",
+			query:    `mutation { deleteUser(input: {email: "john@example.com"}) { success } }`,
+			mockRole: "USER",
+			expError: errors.New(`[{"message":"Access denied, the role of the authenticated user is not allowed","path":["deleteUser"]}]`),
+		},
+		"@hasRole failed with a deleted user": {
+			actor:    "test@example.com",
+			query:    `mutation { deleteUser(input: {email: "john@example.com"}) { success } }`,
+			mockErr:  errors.New("test@example.com is not exists"),
+			expError: errors.New(`[{"message":"test@example.com is not exists","path":["deleteUser"]}]`),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("GetUserRole", mock.Anything, tc.actor).Return(tc.mockRole, tc.mockErr),
+				mockService.On("GetUsersPage", mock.Anything, mock.Anything).Return(pagination.Result{Emails: []string{"andy@example.com"}}, nil),
+				mockService.On("CreateSubscription", mock.Anything, mock.Anything, mock.Anything).Return(nil),
+				mockService.On("RemoveFriend", mock.Anything, mock.Anything, mock.Anything).Return(nil),
+				mockService.On("AcceptFriendRequest", mock.Anything, mock.Anything, mock.Anything).Return(nil),
+				mockService.On("GetIncomingFriendRequests", mock.Anything, mock.Anything).Return([]string{}, nil),
+				mockService.On("DeleteUser", mock.Anything, mock.Anything).Return(nil),
+				mockService.On("GetUser", mock.Anything, "andy@example.com").Return(services.User{Email: "andy@example.com"}, nil),
+				mockService.On("GetBlockedUsersPage", mock.Anything, mock.Anything, mock.Anything).Return(pagination.Result{}, nil),
+				mockService.On("GetUsersByEmails", mock.Anything, mock.Anything).Return([]services.User{}, nil),
+			}
+			c := newDirectiveClient(mockService, tc.actor)
+
+			//When
+			var resp map[string]interface{}
+			err := c.Post(tc.query, &resp)
+
+			//Then
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				for _, field := range resp {
+					require.NotNil(t, field)
+				}
+			}
+		})
+	}
+}
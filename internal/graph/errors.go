@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/go-chi/chi/middleware"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Codes of the plain errors made by the generated code, they are shown to the clients
+var generatedErrorCodes = map[string]string{
+	"introspection disabled": "INTROSPECTION_DISABLED",
+}
+
+// ErrorPresenter adds the code of an error, the invalid fields of the request and the id of the request to the
+// extensions of the error. The errors without a known code are logged and replaced by a generic message,
+// the causes of the unavailable errors are logged too.
+// The errors made by gqlgen itself (parsing, validation, limits) keep their message and code.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	requestID := middleware.GetReqID(ctx)
+
+	if cause := gqlErr.Unwrap(); cause != nil {
+		code := errs.Code(cause)
+		if generatedCode, ok := generatedErrorCodes[cause.Error()]; ok {
+			code = generatedCode
+		}
+		if code == "" || code == errs.CodeInternal {
+			log.Printf("graphql error: request %q, path %v: %v", requestID, gqlErr.Path, cause)
+			gqlErr = &gqlerror.Error{
+				Message:   errs.MsgInternal,
+				Path:      gqlErr.Path,
+				Locations: gqlErr.Locations,
+			}
+			code = errs.CodeInternal
+		} else if errors.Is(cause, errs.ErrUnavailable) {
+			log.Printf("graphql error: request %q, path %v: %v", requestID, gqlErr.Path, errors.Unwrap(cause))
+		}
+		setExtension(gqlErr, "code", code)
+
+		var validationErr *errs.ValidationError
+		if errors.As(cause, &validationErr) {
+			setExtension(gqlErr, "fields", inputFields(ctx, validationErr, gqlErr.Path))
+		}
+	}
+
+	if requestID != "" {
+		setExtension(gqlErr, "requestId", requestID)
+	}
+	return gqlErr
+}
+
+func setExtension(err *gqlerror.Error, name string, value interface{}) {
+	if err.Extensions == nil {
+		err.Extensions = map[string]interface{}{}
+	}
+	err.Extensions[name] = value
+}
+
+// inputFields are the invalid fields of a validation error. The error of an invalid scalar has no field,
+// its field is the path of the error in the arguments, e.g. "friends[1]" for ["createFriend", "input", "friends", 1]
+func inputFields(ctx context.Context, err *errs.ValidationError, path ast.Path) []*errs.FieldError {
+	if fieldCtx := graphql.GetFieldContext(ctx); fieldCtx != nil && len(path) > len(fieldCtx.Path()) {
+		path = path[len(fieldCtx.Path()):]
+	} else {
+		return err.Fields
+	}
+
+	fields := make([]*errs.FieldError, len(err.Fields))
+	for i, field := range err.Fields {
+		fields[i] = field
+		if field.Field == "" {
+			fields[i] = &errs.FieldError{Field: argumentPath(path), Err: field.Err}
+		}
+	}
+	return fields
+}
+
+// argumentPath writes the path of an argument like the fields of the validation errors,
+// the fields of an input object are named without the argument
+func argumentPath(path ast.Path) string {
+	if len(path) > 1 {
+		path = path[1:]
+	}
+
+	var field strings.Builder
+	for _, element := range path {
+		switch element := element.(type) {
+		case ast.PathIndex:
+			fmt.Fprintf(&field, "[%d]", int(element))
+		case ast.PathName:
+			if field.Len() > 0 {
+				field.WriteByte('.')
+			}
+			field.WriteString(string(element))
+		}
+	}
+	return field.String()
+}
@@ -0,0 +1,200 @@
+package graph
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/auth"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/go-chi/chi/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorPresenter(t *testing.T) {
+	subscribe := `mutation { subscribe(input: {requestor: "andy@example.com", target: "john@example.com"}) { success } }`
+
+	tcs := map[string]struct {
+		actor         string
+		query         string
+		serviceError  error
+		expError      string
+		expExtensions map[string]interface{}
+	}{
+		"validation error with its field": {
+			actor:    "andy@example.com",
+			query:    `mutation { subscribe(input: {requestor: "andy@example.com", target: "john"}) { success } }`,
+			expError: `john invalid format (ex: "andy@example.com")`,
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeValidationFailed,
+				"fields": []interface{}{
+					map[string]interface{}{"field": "target", "message": `john invalid format (ex: "andy@example.com")`},
+				},
+				"requestId": "req-1",
+			},
+		},
+		"validation error with every invalid field": {
+			actor:    "andy@example.com",
+			query:    `mutation { createUser(input: {name: " ", email: "lisa@example.com", password: "secret"}) { success } }`,
+			expError: `Name field invalid format; Password must have from 8 to 72 characters`,
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeValidationFailed,
+				"fields": []interface{}{
+					map[string]interface{}{"field": "name", "message": "Name field invalid format"},
+					map[string]interface{}{"field": "password", "message": "Password must have from 8 to 72 characters"},
+				},
+				"requestId": "req-1",
+			},
+		},
+		"every invalid email of a list": {
+			actor:    "andy@example.com",
+			query:    `mutation { createFriend(input: {friends: ["andy", "garbage john@example.com"]}) { success } }`,
+			expError: `andy invalid format (ex: "andy@example.com"); garbage john@example.com invalid format (ex: "andy@example.com")`,
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeValidationFailed,
+				"fields": []interface{}{
+					map[string]interface{}{"field": "friends[0]", "message": `andy invalid format (ex: "andy@example.com")`},
+					map[string]interface{}{"field": "friends[1]", "message": `garbage john@example.com invalid format (ex: "andy@example.com")`},
+				},
+				"requestId": "req-1",
+			},
+		},
+		"invalid email with its path": {
+			actor:    "andy@example.com",
+			query:    `mutation { createFriend(input: {friends: ["andy@example.com", "garbage john@example.com"]}) { success } }`,
+			expError: `garbage john@example.com invalid format (ex: "andy@example.com")`,
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeValidationFailed,
+				"fields": []interface{}{
+					map[string]interface{}{"field": "friends[1]", "message": `garbage john@example.com invalid format (ex: "andy@example.com")`},
+				},
+				"requestId": "req-1",
+			},
+		},
+		"unknown user": {
+			actor:        "andy@example.com",
+			query:        subscribe,
+			serviceError: errs.NewUserLookupError("john@example.com", sql.ErrNoRows),
+			expError:     "john@example.com is not exists",
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeUserNotFound, "requestId": "req-1",
+			},
+		},
+		"existing subscription": {
+			actor:        "andy@example.com",
+			query:        subscribe,
+			serviceError: errs.NewConflictError(errs.CodeAlreadySubscribed, errs.MsgExistedSubscription),
+			expError:     errs.MsgExistedSubscription,
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeAlreadySubscribed, "requestId": "req-1",
+			},
+		},
+		"blocked users": {
+			actor:        "andy@example.com",
+			query:        subscribe,
+			serviceError: errs.NewConflictError(errs.CodeBlocked, errs.MsgExistedBlockedUser),
+			expError:     errs.MsgExistedBlockedUser,
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeBlocked, "requestId": "req-1",
+			},
+		},
+		"code kept when the description changes": {
+			actor:        "andy@example.com",
+			query:        subscribe,
+			serviceError: errs.NewConflictError(errs.CodeBlocked, "The users blocked each other"),
+			expError:     "The users blocked each other",
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeBlocked, "requestId": "req-1",
+			},
+		},
+		"error without a code is coded by its kind": {
+			actor:        "andy@example.com",
+			query:        subscribe,
+			serviceError: &errs.FriendError{Code: http.StatusNotFound, Description: errs.MsgNotExistedFriendship, Kind: errs.ErrNotFound},
+			expError:     errs.MsgNotExistedFriendship,
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeNotFound, "requestId": "req-1",
+			},
+		},
+		"access rule of a directive": {
+			actor:    "lisa@example.com",
+			query:    subscribe,
+			expError: errs.ErrForbiddenActor.Error(),
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeForbidden, "requestId": "req-1",
+			},
+		},
+		"unavailable storage": {
+			actor:        "andy@example.com",
+			query:        subscribe,
+			serviceError: errs.NewUnavailableError(errors.New("pq: connection refused")),
+			expError:     errs.MsgUnavailable,
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeUnavailable, "requestId": "req-1",
+			},
+		},
+		"internal error is hidden": {
+			actor:        "andy@example.com",
+			query:        subscribe,
+			serviceError: errs.NewInternalError(errors.New("bcrypt: hashedSecret too short")),
+			expError:     "Internal server error",
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeInternal, "requestId": "req-1",
+			},
+		},
+		"unexpected error is hidden": {
+			actor:        "andy@example.com",
+			query:        subscribe,
+			serviceError: errors.New("unexpected EOF"),
+			expError:     "Internal server error",
+			expExtensions: map[string]interface{}{
+				"code": errs.CodeInternal, "requestId": "req-1",
+			},
+		},
+		"query error of gqlgen keeps its code": {
+			actor:    "andy@example.com",
+			query:    `{ unknownField }`,
+			expError: `Cannot query field "unknownField" on type "Query".`,
+			expExtensions: map[string]interface{}{
+				"code": "GRAPHQL_VALIDATION_FAILED", "requestId": "req-1",
+			},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			var mockService SpecService
+			mockService.On("CreateSubscription", mock.Anything, "andy@example.com", "john@example.com").Return(tc.serviceError)
+			mockService.On("GetUserRole", mock.Anything, "andy@example.com").Return("ADMIN", nil)
+			srv := NewServer(&Resolver{Service: mockService}, nil, config.DefaultGraphQLConfig())
+			handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				srv.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), tc.actor)))
+			}))
+
+			body, err := json.Marshal(map[string]string{"query": tc.query})
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(string(body)))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(middleware.RequestIDHeader, "req-1")
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			resp := struct {
+				Errors []struct {
+					Message    string
+					Extensions map[string]interface{}
+				}
+			}{}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Len(t, resp.Errors, 1)
+			require.Equal(t, tc.expError, resp.Errors[0].Message)
+			require.Equal(t, tc.expExtensions, resp.Errors[0].Extensions)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/events"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/graphmodel"
+)
+
+// friendEvents streams the friendship events of a kind for a user until the subscription ends
+func (r *Resolver) friendEvents(ctx context.Context, kind events.Kind, email string) <-chan *graphmodel.FriendEvent {
+	in := r.Events.Subscribe(ctx, kind, email)
+	out := make(chan *graphmodel.FriendEvent)
+	go func() {
+		defer close(out)
+		for event := range in {
+			select {
+			case out <- &graphmodel.FriendEvent{Email: event.Email, Friend: event.Friend, CreatedAt: event.CreatedAt}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// blockEvents streams the blocks of a user until the subscription ends
+func (r *Resolver) blockEvents(ctx context.Context, email string) <-chan *graphmodel.BlockEvent {
+	in := r.Events.Subscribe(ctx, events.Blocked, email)
+	out := make(chan *graphmodel.BlockEvent)
+	go func() {
+		defer close(out)
+		for event := range in {
+			select {
+			case out <- &graphmodel.BlockEvent{Email: event.Email, Requestor: event.Requestor, Target: event.Target, CreatedAt: event.CreatedAt}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// updateEvents streams the updates received by a user until the subscription ends
+func (r *Resolver) updateEvents(ctx context.Context, recipient string) <-chan *graphmodel.UpdateEvent {
+	in := r.Events.Subscribe(ctx, events.UpdatePublished, recipient)
+	out := make(chan *graphmodel.UpdateEvent)
+	go func() {
+		defer close(out)
+		for event := range in {
+			select {
+			case out <- &graphmodel.UpdateEvent{Recipient: event.Email, Sender: event.Sender, Text: event.Text, CreatedAt: event.CreatedAt}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/auth"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/events"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/generated"
+	"github.com/stretchr/testify/require"
+)
+
+// newSubscriptionClient serves the subscriptions of the events of broker to the actor
+func newSubscriptionClient(broker *events.Broker, actor string) *client.Client {
+	resolver := &Resolver{
+		Events: broker,
+	}
+	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
+		Resolvers:  resolver,
+		Directives: resolver.Directives(),
+	}))
+
+	return client.New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), actor)))
+	}))
+}
+
+// publishUntil publishes an event until done, a subscription only gets the events published after it started
+func publishUntil(broker *events.Broker, event events.Event, done <-chan struct{}) {
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		broker.Publish(context.Background(), event)
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func TestSubscriptionResolver(t *testing.T) {
+	createdAt := time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	tcs := map[string]struct {
+		actor     string
+		query     string
+		field     string
+		event     events.Event
+		expResult map[string]interface{}
+		expError  error
+	}{
+		"friendAdded success with a new friend of the user": {
+			actor: "andy@example.com",
+			query: `subscription { friendAdded(email: "andy@example.com") { email friend createdAt } }`,
+			field: "friendAdded",
+			event: events.Event{Kind: events.FriendAdded, Email: "andy@example.com", Friend: "john@example.com", CreatedAt: createdAt},
+			expResult: map[string]interface{}{
+				"email": "andy@example.com", "friend": "john@example.com", "createdAt": "2022-01-02T09:00:00Z",
+			},
+		},
+		"friendRemoved success with a removed friend of the user": {
+			actor: "andy@example.com",
+			query: `subscription { friendRemoved(email: "andy@example.com") { email friend } }`,
+			field: "friendRemoved",
+			event: events.Event{Kind: events.FriendRemoved, Email: "andy@example.com", Friend: "john@example.com"},
+			expResult: map[string]interface{}{
+				"email": "andy@example.com", "friend": "john@example.com",
+			},
+		},
+		"blocked success with a block of the user": {
+			actor: "andy@example.com",
+			query: `subscription { blocked(email: "andy@example.com") { email requestor target } }`,
+			field: "blocked",
+			event: events.Event{Kind: events.Blocked, Email: "andy@example.com", Requestor: "lisa@example.com", Target: "andy@example.com"},
+			expResult: map[string]interface{}{
+				"email": "andy@example.com", "requestor": "lisa@example.com", "target": "andy@example.com",
+			},
+		},
+		"updatePublished success with an update received by the user": {
+			actor: "andy@example.com",
+			query: `subscription { updatePublished(recipient: "andy@example.com") { recipient sender text } }`,
+			field: "updatePublished",
+			event: events.Event{Kind: events.UpdatePublished, Email: "andy@example.com", Sender: "john@example.com", Text: "hello"},
+			expResult: map[string]interface{}{
+				"recipient": "andy@example.com", "sender": "john@example.com", "text": "hello",
+			},
+		},
+		"updatePublished success with the canonical form of the recipient": {
+			actor: "andy@example.com",
+			query: `subscription { updatePublished(recipient: " andy@EXAMPLE.com ") { recipient sender text } }`,
+			field: "updatePublished",
+			event: events.Event{Kind: events.UpdatePublished, Email: "andy@example.com", Sender: "john@example.com", Text: "hello"},
+			expResult: map[string]interface{}{
+				"recipient": "andy@example.com", "sender": "john@example.com", "text": "hello",
+			},
+		},
+		"failed with the events of another user": {
+			actor:    "lisa@example.com",
+			query:    `subscription { friendAdded(email: "andy@example.com") { email friend } }`,
+			event:    events.Event{Kind: events.FriendAdded, Email: "andy@example.com", Friend: "john@example.com"},
+			expError: errors.New(`[{"message":"The authenticated user has to be one of the parties","path":["friendAdded"]}]`),
+		},
+		"failed with an invalid email": {
+			actor:    "andy",
+			query:    `subscription { blocked(email: "andy") { email } }`,
+			event:    events.Event{Kind: events.Blocked, Email: "andy"},
+			expError: errors.New(`[{"message":"andy invalid format (ex: \"andy@example.com\")","path":["blocked"]}]`),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			broker := events.NewBroker()
+			c := newSubscriptionClient(broker, tc.actor)
+
+			sub := c.Websocket(tc.query)
+			defer sub.Close()
+
+			done := make(chan struct{})
+			go publishUntil(broker, tc.event, done)
+			var resp map[string]map[string]interface{}
+			err := sub.Next(&resp)
+			close(done)
+
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, resp[tc.field])
+			}
+		})
+	}
+}
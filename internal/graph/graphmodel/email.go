@@ -0,0 +1,22 @@
+package graphmodel
+
+import (
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// MarshalEmail writes the Email scalar, the emails are strings in Go
+func MarshalEmail(email string) graphql.Marshaler {
+	return graphql.MarshalString(email)
+}
+
+// UnmarshalEmail reads the Email scalar as it is sent, the resolvers parse it with the other fields
+// of their input so that every invalid email is reported at once
+func UnmarshalEmail(v interface{}) (string, error) {
+	value, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%T is not an email", v)
+	}
+	return value, nil
+}
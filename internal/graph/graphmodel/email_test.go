@@ -0,0 +1,50 @@
+package graphmodel
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailScalar(t *testing.T) {
+	tcs := map[string]struct {
+		input    interface{}
+		expEmail string
+		expError error
+	}{
+		"email": {
+			input:    "andy@example.com",
+			expEmail: "andy@example.com",
+		},
+		"email kept as it is sent": {
+			input:    " andy@Example.COM",
+			expEmail: " andy@Example.COM",
+		},
+		"invalid email left to the resolvers": {
+			input:    "garbage a@b.co garbage",
+			expEmail: "garbage a@b.co garbage",
+		},
+		"not a string": {
+			input:    42,
+			expError: errors.New("int is not an email"),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			email, err := UnmarshalEmail(tc.input)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expEmail, email)
+
+			var buf bytes.Buffer
+			MarshalEmail(email).MarshalGQL(&buf)
+			require.Equal(t, `"`+tc.expEmail+`"`, buf.String())
+		})
+	}
+}
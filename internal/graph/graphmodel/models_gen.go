@@ -2,13 +2,47 @@
 
 package graphmodel
 
-type Email struct {
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type BlockEvent struct {
+	Email     string    `json:"email"`
+	Requestor string    `json:"requestor"`
+	Target    string    `json:"target"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type EmailEdge struct {
+	Cursor string `json:"cursor"`
+	Node   string `json:"node"`
+	User   *User  `json:"user"`
+}
+
+type EmailInput struct {
 	Email string `json:"email"`
 }
 
+type FriendEvent struct {
+	Email     string    `json:"email"`
+	Friend    string    `json:"friend"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 type FriendList struct {
+	Success  bool         `json:"success"`
+	Friends  []string     `json:"friends"`
+	Count    int          `json:"count"`
+	Edges    []*EmailEdge `json:"edges"`
+	PageInfo *PageInfo    `json:"pageInfo"`
+}
+
+type FriendRequests struct {
 	Success bool     `json:"success"`
-	Friends []string `json:"friends"`
+	Emails  []string `json:"emails"`
 	Count   int      `json:"count"`
 }
 
@@ -20,9 +54,44 @@ type IsSuccess struct {
 	Success bool `json:"success"`
 }
 
+type Login struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type NewUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type NewWebhook struct {
+	URL    string         `json:"url"`
+	Secret string         `json:"secret"`
+	Events []WebhookEvent `json:"events"`
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+type PublishedUpdate struct {
+	Success            bool     `json:"success"`
+	ID                 string   `json:"id"`
+	RecipientCount     int      `json:"recipientCount"`
+	UnresolvedMentions []string `json:"unresolvedMentions"`
+}
+
 type Recipients struct {
-	Success    bool     `json:"success"`
-	Recipients []string `json:"recipients"`
+	Success            bool         `json:"success"`
+	Recipients         []string     `json:"recipients"`
+	Count              int          `json:"count"`
+	Edges              []*EmailEdge `json:"edges"`
+	PageInfo           *PageInfo    `json:"pageInfo"`
+	UnresolvedMentions []string     `json:"unresolvedMentions"`
 }
 
 type RequestTarget struct {
@@ -39,8 +108,198 @@ type Success struct {
 	Status string `json:"status"`
 }
 
+type Token struct {
+	Success bool   `json:"success"`
+	Token   string `json:"token"`
+}
+
+type UnblockResult struct {
+	Success              bool `json:"success"`
+	FriendshipRestored   bool `json:"friendshipRestored"`
+	SubscriptionRestored bool `json:"subscriptionRestored"`
+}
+
+type UpdateEvent struct {
+	Recipient string    `json:"recipient"`
+	Sender    string    `json:"sender"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type UpdateUser struct {
+	Email    string  `json:"email"`
+	Name     *string `json:"name"`
+	NewEmail *string `json:"newEmail"`
+}
+
+type UserConnection struct {
+	Edges      []*UserEdge `json:"edges"`
+	PageInfo   *PageInfo   `json:"pageInfo"`
+	TotalCount int         `json:"totalCount"`
+}
+
+type UserEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *User  `json:"node"`
+}
+
 type Users struct {
-	Success bool     `json:"success"`
-	Emails  []string `json:"emails"`
-	Count   int      `json:"count"`
+	Success  bool         `json:"success"`
+	Emails   []string     `json:"emails"`
+	Count    int          `json:"count"`
+	Edges    []*EmailEdge `json:"edges"`
+	PageInfo *PageInfo    `json:"pageInfo"`
+}
+
+type Webhook struct {
+	ID        string         `json:"id"`
+	URL       string         `json:"url"`
+	Events    []WebhookEvent `json:"events"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	Event          WebhookEvent          `json:"event"`
+	Payload        string                `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	ResponseStatus *int                  `json:"responseStatus"`
+	LastError      *string               `json:"lastError"`
+	NextAttemptAt  time.Time             `json:"nextAttemptAt"`
+	CreatedAt      time.Time             `json:"createdAt"`
+	UpdatedAt      time.Time             `json:"updatedAt"`
+}
+
+type Role string
+
+const (
+	RoleAdmin Role = "ADMIN"
+	RoleUser  Role = "USER"
+)
+
+var AllRole = []Role{
+	RoleAdmin,
+	RoleUser,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleAdmin, RoleUser:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryStatusDead      WebhookDeliveryStatus = "DEAD"
+)
+
+var AllWebhookDeliveryStatus = []WebhookDeliveryStatus{
+	WebhookDeliveryStatusPending,
+	WebhookDeliveryStatusDelivered,
+	WebhookDeliveryStatusDead,
+}
+
+func (e WebhookDeliveryStatus) IsValid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending, WebhookDeliveryStatusDelivered, WebhookDeliveryStatusDead:
+		return true
+	}
+	return false
+}
+
+func (e WebhookDeliveryStatus) String() string {
+	return string(e)
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookDeliveryStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookDeliveryStatus", str)
+	}
+	return nil
+}
+
+func (e WebhookDeliveryStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type WebhookEvent string
+
+const (
+	WebhookEventFriendAdded         WebhookEvent = "FRIEND_ADDED"
+	WebhookEventFriendRemoved       WebhookEvent = "FRIEND_REMOVED"
+	WebhookEventSubscriptionCreated WebhookEvent = "SUBSCRIPTION_CREATED"
+	WebhookEventSubscriptionRemoved WebhookEvent = "SUBSCRIPTION_REMOVED"
+	WebhookEventBlockCreated        WebhookEvent = "BLOCK_CREATED"
+	WebhookEventBlockRemoved        WebhookEvent = "BLOCK_REMOVED"
+)
+
+var AllWebhookEvent = []WebhookEvent{
+	WebhookEventFriendAdded,
+	WebhookEventFriendRemoved,
+	WebhookEventSubscriptionCreated,
+	WebhookEventSubscriptionRemoved,
+	WebhookEventBlockCreated,
+	WebhookEventBlockRemoved,
+}
+
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookEventFriendAdded, WebhookEventFriendRemoved, WebhookEventSubscriptionCreated, WebhookEventSubscriptionRemoved, WebhookEventBlockCreated, WebhookEventBlockRemoved:
+		return true
+	}
+	return false
+}
+
+func (e WebhookEvent) String() string {
+	return string(e)
+}
+
+func (e *WebhookEvent) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookEvent(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookEvent", str)
+	}
+	return nil
+}
+
+func (e WebhookEvent) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
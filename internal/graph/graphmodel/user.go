@@ -0,0 +1,12 @@
+package graphmodel
+
+import "time"
+
+// User of the schema, the relationship fields are resolved on demand from the email
+type User struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
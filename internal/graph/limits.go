@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Codes of the errors of the queries refused by QueryLimit
+const (
+	ErrCodeDepthLimit      = "DEPTH_LIMIT_EXCEEDED"
+	ErrCodeComplexityLimit = "COMPLEXITY_LIMIT_EXCEEDED"
+)
+
+// A refused operation is answered with status 422 like a query failing validation
+func init() {
+	errcode.RegisterErrorType(ErrCodeDepthLimit, errcode.KindProtocol)
+	errcode.RegisterErrorType(ErrCodeComplexityLimit, errcode.KindProtocol)
+}
+
+// QueryLimit refuses the operations nested deeper than MaxDepth fields or costing more than MaxComplexity,
+// a limit of 0 is not checked. The cost comes from the ComplexityRoot of the schema, see Complexity.
+// The error of a refused operation has the computed depth or cost and the limit in its extensions.
+type QueryLimit struct {
+	MaxDepth      int
+	MaxComplexity int
+
+	schema graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &QueryLimit{}
+
+func (_self QueryLimit) ExtensionName() string {
+	return "QueryLimit"
+}
+
+func (_self *QueryLimit) Validate(schema graphql.ExecutableSchema) error {
+	_self.schema = schema
+	return nil
+}
+
+func (_self QueryLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	op := rc.Doc.Operations.ForName(rc.OperationName)
+	if op == nil {
+		return nil
+	}
+
+	if _self.MaxDepth > 0 {
+		if depth := selectionDepth(op.SelectionSet); depth > _self.MaxDepth {
+			err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, _self.MaxDepth)
+			errcode.Set(err, ErrCodeDepthLimit)
+			err.Extensions["depth"] = depth
+			err.Extensions["maxDepth"] = _self.MaxDepth
+			return err
+		}
+	}
+
+	if _self.MaxComplexity > 0 {
+		if cost := complexity.Calculate(_self.schema, op, rc.Variables); cost > _self.MaxComplexity {
+			err := gqlerror.Errorf("operation has complexity %d, which exceeds the limit of %d", cost, _self.MaxComplexity)
+			errcode.Set(err, ErrCodeComplexityLimit)
+			err.Extensions["complexity"] = cost
+			err.Extensions["maxComplexity"] = _self.MaxComplexity
+			return err
+		}
+	}
+	return nil
+}
+
+// selectionDepth is the number of nested fields of the deepest path of a selection set, fragments are counted
+// as their fields. Introspection fields are left out since their depth is fixed by the introspection query.
+func selectionDepth(selectionSet ast.SelectionSet) int {
+	depth := 0
+	for _, selection := range selectionSet {
+		var childDepth int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			childDepth = 1 + selectionDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			childDepth = selectionDepth(s.Definition.SelectionSet)
+		case *ast.InlineFragment:
+			childDepth = selectionDepth(s.SelectionSet)
+		}
+		if childDepth > depth {
+			depth = childDepth
+		}
+	}
+	return depth
+}
@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryLimit(t *testing.T) {
+	nestedFriends := `{ user(email: "andy@example.com") { friends { edges { node { friends { edges { node { email } } } } } } } }`
+
+	tcs := map[string]struct {
+		maxDepth      int
+		maxComplexity int
+		body          string
+		expStatus     int
+		expError      string
+		expExtensions map[string]interface{}
+	}{
+		"failed with nested friends over the depth limit": {
+			maxDepth:  6,
+			body:      `{"query":` + quoteQuery(nestedFriends) + `}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expError:  "operation has depth 8, which exceeds the limit of 6",
+			expExtensions: map[string]interface{}{
+				"code": ErrCodeDepthLimit, "depth": float64(8), "maxDepth": float64(6),
+			},
+		},
+		"failed with the fields of a fragment over the depth limit": {
+			maxDepth:  4,
+			body:      `{"query":"query { user(email: \"andy@example.com\") { ...Friends } } fragment Friends on User { friends { edges { node { email } } } }"}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expError:  "operation has depth 5, which exceeds the limit of 4",
+			expExtensions: map[string]interface{}{
+				"code": ErrCodeDepthLimit, "depth": float64(5), "maxDepth": float64(4),
+			},
+		},
+		"success with introspection deeper than the depth limit": {
+			maxDepth:  2,
+			body:      `{"query":"{ __schema { queryType { fields { type { ofType { name } } } } } }"}`,
+			expStatus: http.StatusOK,
+		},
+		"failed with a default page over the complexity limit": {
+			maxComplexity: 100,
+			body:          `{"query":"{ user(email: \"andy@example.com\") { friends { edges { node { email } } } } }"}`,
+			expStatus:     http.StatusUnprocessableEntity,
+			expError:      "operation has complexity 152, which exceeds the limit of 100",
+			expExtensions: map[string]interface{}{
+				"code": ErrCodeComplexityLimit, "complexity": float64(152), "maxComplexity": float64(100),
+			},
+		},
+		"failed with the page size of a variable over the complexity limit": {
+			maxComplexity: 200,
+			body:          `{"query":"query($n: Int) { user(email: \"andy@example.com\") { friends(first: $n) { edges { node { email } } } } }","variables":{"n":100}}`,
+			expStatus:     http.StatusUnprocessableEntity,
+			expError:      "operation has complexity 302, which exceeds the limit of 200",
+			expExtensions: map[string]interface{}{
+				"code": ErrCodeComplexityLimit, "complexity": float64(302), "maxComplexity": float64(200),
+			},
+		},
+		"failed with nested pages multiplying their sizes": {
+			maxComplexity: 10000,
+			body:          `{"query":"{ users(first: 100) { edges { user { friends(last: 100) { totalCount } } } } }"}`,
+			expStatus:     http.StatusUnprocessableEntity,
+			expError:      "operation has complexity 10301, which exceeds the limit of 10000",
+			expExtensions: map[string]interface{}{
+				"code": ErrCodeComplexityLimit, "complexity": float64(10301), "maxComplexity": float64(10000),
+			},
+		},
+		"small page under the complexity limit is executed": {
+			maxComplexity: 100,
+			body:          `{"query":"{ user(email: \"andy@example.com\") { friends(first: 10) { edges { node { email } } } } }"}`,
+			expStatus:     http.StatusOK,
+			expError:      "Access denied, a valid token is required",
+		},
+		"limits turned off": {
+			body:      `{"query":` + quoteQuery(nestedFriends) + `}`,
+			expStatus: http.StatusOK,
+			expError:  "Access denied, a valid token is required",
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			cfg := config.DefaultGraphQLConfig()
+			cfg.MaxDepth = tc.maxDepth
+			cfg.MaxComplexity = tc.maxComplexity
+			srv := NewServer(&Resolver{}, nil, cfg)
+
+			req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			srv.ServeHTTP(rr, req)
+			require.Equal(t, tc.expStatus, rr.Code)
+
+			resp := struct {
+				Errors []struct {
+					Message    string
+					Extensions map[string]interface{}
+				}
+			}{}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tc.expError == "" {
+				require.Empty(t, resp.Errors)
+				return
+			}
+			require.Len(t, resp.Errors, 1)
+			require.Equal(t, tc.expError, resp.Errors[0].Message)
+			if tc.expExtensions != nil {
+				require.Equal(t, tc.expExtensions, resp.Errors[0].Extensions)
+			}
+		})
+	}
+}
+
+// quoteQuery is the JSON string of a query
+func quoteQuery(query string) string {
+	quoted, _ := json.Marshal(query)
+	return string(quoted)
+}
@@ -0,0 +1,430 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/auth"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/generated"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/loaders"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/services"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepo serves the lookups of a nested query from memory and counts the queries of each method
+type memoryRepo struct {
+	repository.SpecRepo
+	users   models.UserSlice
+	friends models.FriendSlice
+	blocks  models.UserBlockSlice
+
+	mu      sync.Mutex
+	queries map[string]int
+}
+
+func newMemoryRepo() *memoryRepo {
+	emails := []string{"john", "andy", "common", "lisa", "kate", "mary"}
+	users := make(models.UserSlice, len(emails))
+	for i, name := range emails {
+		users[i] = &models.User{ID: 100 + i, Name: name, Email: name + "@example.com"}
+	}
+	return &memoryRepo{
+		users: users,
+		friends: models.FriendSlice{
+			{UserID: 100, FriendID: 102},
+			{UserID: 101, FriendID: 102},
+			{UserID: 102, FriendID: 103},
+			{UserID: 105, FriendID: 102},
+		},
+		blocks: models.UserBlockSlice{
+			{RequestorID: 100, TargetID: 103},
+			{RequestorID: 100, TargetID: 104},
+		},
+		queries: map[string]int{},
+	}
+}
+
+func (r *memoryRepo) query(method string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries[method]++
+}
+
+func (r *memoryRepo) GetUsersPage(ctx context.Context, page pagination.Page) (models.UserSlice, error) {
+	r.query("GetUsersPage")
+	users := append(models.UserSlice{}, r.users...)
+	sort.Slice(users, func(i, j int) bool { return users[i].Email < users[j].Email })
+	if len(users) > page.Size+1 {
+		users = users[:page.Size+1]
+	}
+	return users, nil
+}
+
+func (r *memoryRepo) CountUsers(ctx context.Context) (int64, error) {
+	r.query("CountUsers")
+	return int64(len(r.users)), nil
+}
+
+func (r *memoryRepo) GetUsersByEmails(ctx context.Context, emails []string) (models.UserSlice, error) {
+	r.query("GetUsersByEmails")
+	users := models.UserSlice{}
+	for _, user := range r.users {
+		for _, email := range emails {
+			if user.Email == email {
+				users = append(users, user)
+			}
+		}
+	}
+	return users, nil
+}
+
+func (r *memoryRepo) GetUsersByIDs(ctx context.Context, userIds []int) (models.UserSlice, error) {
+	r.query("GetUsersByIDs")
+	users := models.UserSlice{}
+	for _, user := range r.users {
+		for _, userId := range userIds {
+			if user.ID == userId {
+				users = append(users, user)
+			}
+		}
+	}
+	return users, nil
+}
+
+func (r *memoryRepo) GetFriendsByIDs(ctx context.Context, userIds []int) (models.FriendSlice, error) {
+	r.query("GetFriendsByIDs")
+	friends := models.FriendSlice{}
+	for _, friend := range r.friends {
+		for _, userId := range userIds {
+			if friend.UserID == userId || friend.FriendID == userId {
+				friends = append(friends, friend)
+				break
+			}
+		}
+	}
+	return friends, nil
+}
+
+func (r *memoryRepo) GetUserBlocksByIDs(ctx context.Context, userIds []int) (models.UserBlockSlice, error) {
+	r.query("GetUserBlocksByIDs")
+	blocks := models.UserBlockSlice{}
+	for _, block := range r.blocks {
+		for _, userId := range userIds {
+			if block.RequestorID == userId || block.TargetID == userId {
+				blocks = append(blocks, block)
+				break
+			}
+		}
+	}
+	return blocks, nil
+}
+
+func (r *memoryRepo) GetUserIDByEmail(ctx context.Context, email string) (int, error) {
+	r.query("GetUserIDByEmail")
+	for _, user := range r.users {
+		if user.Email == email {
+			return user.ID, nil
+		}
+	}
+	return 0, sql.ErrNoRows
+}
+
+func (r *memoryRepo) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.query("GetUserByEmail")
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memoryRepo) GetFriendsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	r.query("GetFriendsPages")
+	pages := make(map[int]repository.RelationPage, len(userIds))
+	for _, userId := range userIds {
+		emails := []string{}
+		for _, friend := range r.friends {
+			friendId := friend.FriendID
+			if friend.FriendID == userId {
+				friendId = friend.UserID
+			} else if friend.UserID != userId {
+				continue
+			}
+			if !r.isBlocked(userId, friendId) {
+				emails = append(emails, r.emailOf(friendId))
+			}
+		}
+		pages[userId] = repository.RelationPage{Emails: pagination.Window(page, emails), TotalCount: int64(len(emails))}
+	}
+	return pages, nil
+}
+
+func (r *memoryRepo) isBlocked(userId int, otherId int) bool {
+	for _, block := range r.blocks {
+		if (block.RequestorID == userId && block.TargetID == otherId) || (block.RequestorID == otherId && block.TargetID == userId) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryRepo) emailOf(userId int) string {
+	for _, user := range r.users {
+		if user.ID == userId {
+			return user.Email
+		}
+	}
+	return ""
+}
+
+func (r *memoryRepo) GetFriendsByID(ctx context.Context, userId int) (models.FriendSlice, error) {
+	r.query("GetFriendsByID")
+	friends := models.FriendSlice{}
+	for _, friend := range r.friends {
+		if friend.UserID == userId || friend.FriendID == userId {
+			friends = append(friends, friend)
+		}
+	}
+	return friends, nil
+}
+
+func (r *memoryRepo) GetUserBlocksByID(ctx context.Context, userId int) (models.UserBlockSlice, error) {
+	r.query("GetUserBlocksByID")
+	blocks := models.UserBlockSlice{}
+	for _, block := range r.blocks {
+		if block.RequestorID == userId || block.TargetID == userId {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+func (r *memoryRepo) GetEmailsByUserIDs(ctx context.Context, userIDs []int) ([]string, error) {
+	r.query("GetEmailsByUserIDs")
+	emails := []string{}
+	for _, user := range r.users {
+		for _, userId := range userIDs {
+			if user.ID == userId {
+				emails = append(emails, user.Email)
+			}
+		}
+	}
+	return emails, nil
+}
+
+// newLoaderClient serves the schema on top of the real service, every request gets new loaders when withLoaders is set
+func newLoaderClient(repo *memoryRepo, withLoaders bool) *client.Client {
+	resolver := &Resolver{
+		Service: services.NewFriendService(loaders.NewRepo(repo), nil, nil),
+	}
+	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
+		Resolvers:  resolver,
+		Directives: resolver.Directives(),
+	}))
+
+	return client.New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := auth.WithUser(r.Context(), "andy@example.com")
+		if withLoaders {
+			// a long wait keeps the lookups of concurrent resolvers in one batch on a slow machine
+			ctx = loaders.WithLoaders(ctx, loaders.NewLoaders(repo, 20*time.Millisecond))
+		}
+		srv.ServeHTTP(w, r.WithContext(ctx))
+	}))
+}
+
+func TestLoaders_NestedQuery(t *testing.T) {
+	tcs := map[string]struct {
+		first       int
+		withLoaders bool
+		expQueries  map[string]int
+	}{
+		"a query per user without loaders": {
+			first: 3,
+			expQueries: map[string]int{
+				"GetUsersPage":       1,
+				"CountUsers":         1,
+				"GetUsersByEmails":   6,
+				"GetUserIDByEmail":   6,
+				"GetFriendsByID":     6,
+				"GetUserBlocksByID":  6,
+				"GetEmailsByUserIDs": 6,
+			},
+		},
+		"batched queries with loaders": {
+			first:       3,
+			withLoaders: true,
+			expQueries: map[string]int{
+				"GetUsersPage":       1,
+				"CountUsers":         1,
+				"GetUsersByEmails":   2,
+				"GetFriendsByIDs":    2,
+				"GetUserBlocksByIDs": 2,
+				"GetUsersByIDs":      1,
+			},
+		},
+		"batched queries do not grow with the page": {
+			first:       5,
+			withLoaders: true,
+			expQueries: map[string]int{
+				"GetUsersPage":       1,
+				"CountUsers":         1,
+				"GetUsersByEmails":   2,
+				"GetFriendsByIDs":    2,
+				"GetUserBlocksByIDs": 2,
+				"GetUsersByIDs":      1,
+			},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			repo := newMemoryRepo()
+			c := newLoaderClient(repo, tc.withLoaders)
+
+			var resp struct {
+				Users struct {
+					Edges []struct {
+						User struct {
+							Email         string
+							MutualFriends []struct{ Email string }
+						}
+					}
+				}
+			}
+			err := c.Post(`query($first: Int) {
+				users(first: $first) { edges { user { email mutualFriends(with: "mary@example.com") { email } } } }
+			}`, &resp, client.Var("first", tc.first))
+
+			require.NoError(t, err)
+			require.Len(t, resp.Users.Edges, tc.first)
+			require.Equal(t, "andy@example.com", resp.Users.Edges[0].User.Email)
+			require.Equal(t, []struct{ Email string }{{Email: "common@example.com"}}, resp.Users.Edges[0].User.MutualFriends)
+			require.Equal(t, tc.expQueries, repo.queries)
+		})
+	}
+}
+
+func TestLoaders_NestedConnections(t *testing.T) {
+	tcs := map[string]struct {
+		withLoaders bool
+		expQueries  map[string]int
+	}{
+		"page queries per user without loaders": {
+			expQueries: map[string]int{
+				"GetUserByEmail":   1,
+				"GetUserIDByEmail": 5,
+				"GetFriendsPages":  5,
+				"GetUsersByEmails": 5,
+			},
+		},
+		"a page query per level with loaders": {
+			withLoaders: true,
+			expQueries: map[string]int{
+				"GetUsersByEmails": 2,
+				"GetFriendsPages":  2,
+			},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			repo := newMemoryRepo()
+			c := newLoaderClient(repo, tc.withLoaders)
+
+			type friends struct {
+				TotalCount int
+				Edges      []struct {
+					Node struct{ Email string }
+				}
+			}
+			var resp struct {
+				User struct {
+					Friends struct {
+						Edges []struct {
+							Node struct {
+								Email   string
+								Friends friends
+							}
+						}
+					}
+				}
+			}
+			err := c.Post(`{
+				user(email: "common@example.com") {
+					friends { edges { node { email friends { totalCount edges { node { email } } } } } }
+				}
+			}`, &resp)
+
+			require.NoError(t, err)
+			emails := []string{}
+			for _, edge := range resp.User.Friends.Edges {
+				emails = append(emails, edge.Node.Email)
+				require.Equal(t, 1, edge.Node.Friends.TotalCount)
+				require.Len(t, edge.Node.Friends.Edges, 1)
+				require.Equal(t, "common@example.com", edge.Node.Friends.Edges[0].Node.Email)
+			}
+			require.Equal(t, []string{"andy@example.com", "john@example.com", "lisa@example.com", "mary@example.com"}, emails)
+			require.Equal(t, tc.expQueries, repo.queries)
+		})
+	}
+}
+
+func TestLoaders_WebsocketOperations(t *testing.T) {
+	repo := newMemoryRepo()
+	resolver := &Resolver{
+		Service: services.NewFriendService(loaders.NewRepo(repo), nil, nil),
+	}
+	srv := httptest.NewServer(loaders.Middleware(repo)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NewServer(resolver, nil, config.DefaultGraphQLConfig()).ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), "andy@example.com")))
+	})))
+	defer srv.Close()
+
+	// The operations of a connection share the context of its upgrade request
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), http.Header{
+		"Sec-Websocket-Protocol": {"graphql-ws"},
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"type": "connection_init", "payload": map[string]interface{}{}}))
+
+	mutualFriends := func(id string) string {
+		require.NoError(t, conn.WriteJSON(map[string]interface{}{
+			"id":      id,
+			"type":    "start",
+			"payload": map[string]interface{}{"query": `{ user(email: "andy@example.com") { mutualFriends(with: "john@example.com") { email } } }`},
+		}))
+		for {
+			var msg struct {
+				ID      string          `json:"id"`
+				Type    string          `json:"type"`
+				Payload json.RawMessage `json:"payload"`
+			}
+			require.NoError(t, conn.ReadJSON(&msg))
+			if msg.ID == id && msg.Type == "data" {
+				return string(msg.Payload)
+			}
+		}
+	}
+
+	require.JSONEq(t, `{"data":{"user":{"mutualFriends":[{"email":"common@example.com"}]}}}`, mutualFriends("1"))
+
+	// mary becomes a friend of both users between the two operations
+	repo.friends = append(repo.friends, &models.Friend{UserID: 100, FriendID: 105}, &models.Friend{UserID: 101, FriendID: 105})
+	require.JSONEq(t, `{"data":{"user":{"mutualFriends":[{"email":"common@example.com"},{"email":"mary@example.com"}]}}}`, mutualFriends("2"))
+}
@@ -1,6 +1,11 @@
 package graph
 
-import "github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/services"
+import (
+	"context"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/events"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/services"
+)
 
 // This file will not be regenerated automatically.
 //
@@ -8,4 +13,10 @@ import "github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/service
 
 type Resolver struct {
 	Service services.SpecService
+	Events  EventSource
+}
+
+// EventSource streams the events of a user to the subscriptions, the stream ends with ctx
+type EventSource interface {
+	Subscribe(ctx context.Context, kind events.Kind, email string) <-chan events.Event
 }
@@ -5,15 +5,518 @@ package graph
 
 import (
 	"context"
-	"fmt"
+	"strconv"
 
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/auth"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/events"
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/generated"
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/graphmodel"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/validation"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
 )
 
+func (r *emailEdgeResolver) User(ctx context.Context, obj *graphmodel.EmailEdge) (*graphmodel.User, error) {
+	users, err := r.usersOf(ctx, []string{obj.Node})
+	if err != nil || len(users) == 0 {
+		return nil, err
+	}
+
+	//Response
+	return users[0], nil
+}
+
+func (r *mutationResolver) CreateUser(ctx context.Context, input graphmodel.NewUser) (*graphmodel.IsSuccess, error) {
+	// Decode request body
+	newUserReq := validation.NewUserRequest{
+		Name:     input.Name,
+		Email:    input.Email,
+		Password: input.Password,
+	}
+
+	//Validation
+	if err := newUserReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Service.CreateUser(ctx, newUserReq.Name, newUserReq.Email, newUserReq.Password); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) UpdateUser(ctx context.Context, input graphmodel.UpdateUser) (*graphmodel.IsSuccess, error) {
+	// Decode request body
+	updateUserReq := validation.UpdateUserRequest{
+		Email: input.Email,
+	}
+	if input.Name != nil {
+		updateUserReq.Name = *input.Name
+	}
+	if input.NewEmail != nil {
+		updateUserReq.NewEmail = *input.NewEmail
+	}
+
+	//Validation
+	if err := updateUserReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Service.UpdateUser(ctx, updateUserReq.Email, updateUserReq.Name, updateUserReq.NewEmail); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) DeleteUser(ctx context.Context, input graphmodel.EmailInput) (*graphmodel.IsSuccess, error) {
+	// Decode request body
+	userReq := validation.UserRequest{
+		Email: input.Email,
+	}
+
+	//Validation
+	if err := userReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Service.DeleteUser(ctx, userReq.Email); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) Login(ctx context.Context, input graphmodel.Login) (*graphmodel.Token, error) {
+	// Decode request body
+	loginReq := validation.LoginRequest{
+		Email:    input.Email,
+		Password: input.Password,
+	}
+
+	//Validation
+	if err := loginReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	token, err := r.Service.Login(ctx, loginReq.Email, loginReq.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.Token{
+		Success: true,
+		Token:   token,
+	}, nil
+}
+
 func (r *mutationResolver) CreateFriend(ctx context.Context, input graphmodel.Friends) (*graphmodel.IsSuccess, error) {
 	// Decode request body
-	friendReq := FriendRequest{}
+	friendReq := validation.FriendRequest{}
+	for _, email := range input.Friends {
+		friendReq.Emails = append(friendReq.Emails, email)
+	}
+
+	//Validation
+	if err := friendReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	// The friend request is sent by the authenticated user, @isSelf makes it one of the friends
+	requestor, target := friendReq.Emails[0], friendReq.Emails[1]
+	if auth.ForContext(ctx) == target {
+		requestor, target = target, requestor
+	}
+	if err := r.Service.SendFriendRequest(ctx, requestor, target); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) Unfriend(ctx context.Context, input graphmodel.Friends) (*graphmodel.IsSuccess, error) {
+	// Decode request body
+	friendReq := validation.FriendRequest{}
+	for _, email := range input.Friends {
+		friendReq.Emails = append(friendReq.Emails, email)
+	}
+
+	//Validation
+	if err := friendReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Service.RemoveFriend(ctx, friendReq.Emails[0], friendReq.Emails[1]); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) Subscribe(ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
+	// Decode request body
+	requestorReq := validation.RequestorRequest{
+		Requestor: input.Requestor,
+		Target:    input.Target,
+	}
+
+	//Validation
+	if err := requestorReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Service.CreateSubscription(ctx, requestorReq.Requestor, requestorReq.Target); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) Unsubscribe(ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
+	// Decode request body
+	requestorReq := validation.RequestorRequest{
+		Requestor: input.Requestor,
+		Target:    input.Target,
+	}
+
+	//Validation
+	if err := requestorReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Service.RemoveSubscription(ctx, requestorReq.Requestor, requestorReq.Target); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) BlockUpdate(ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
+	// Decode request body
+	requestorReq := validation.RequestorRequest{
+		Requestor: input.Requestor,
+		Target:    input.Target,
+	}
+
+	//Validation
+	if err := requestorReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Service.CreateUserBlock(ctx, requestorReq.Requestor, requestorReq.Target); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) Unblock(ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.UnblockResult, error) {
+	// Decode request body
+	requestorReq := validation.RequestorRequest{
+		Requestor: input.Requestor,
+		Target:    input.Target,
+	}
+
+	//Validation
+	if err := requestorReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	result, err := r.Service.RemoveUserBlock(ctx, requestorReq.Requestor, requestorReq.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.UnblockResult{
+		Success:              true,
+		FriendshipRestored:   result.FriendshipRestored,
+		SubscriptionRestored: result.SubscriptionRestored,
+	}, nil
+}
+
+func (r *mutationResolver) SendFriendRequest(ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
+	// Decode request body
+	requestorReq := validation.RequestorRequest{
+		Requestor: input.Requestor,
+		Target:    input.Target,
+	}
+
+	//Validation
+	if err := requestorReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Service.SendFriendRequest(ctx, requestorReq.Requestor, requestorReq.Target); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) AcceptFriendRequest(ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
+	// Decode request body
+	requestorReq := validation.RequestorRequest{
+		Requestor: input.Requestor,
+		Target:    input.Target,
+	}
+
+	//Validation
+	if err := requestorReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Service.AcceptFriendRequest(ctx, requestorReq.Requestor, requestorReq.Target); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) DeclineFriendRequest(ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
+	// Decode request body
+	requestorReq := validation.RequestorRequest{
+		Requestor: input.Requestor,
+		Target:    input.Target,
+	}
+
+	//Validation
+	if err := requestorReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Service.DeclineFriendRequest(ctx, requestorReq.Requestor, requestorReq.Target); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) CancelFriendRequest(ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
+	// Decode request body
+	requestorReq := validation.RequestorRequest{
+		Requestor: input.Requestor,
+		Target:    input.Target,
+	}
+
+	//Validation
+	if err := requestorReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Service.CancelFriendRequest(ctx, requestorReq.Requestor, requestorReq.Target); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) PublishUpdate(ctx context.Context, input graphmodel.SendMail) (*graphmodel.PublishedUpdate, error) {
+	// Decode request body
+	recipientReq := validation.RecipientsRequest{
+		Sender: input.Sender,
+		Text:   input.Text,
+	}
+
+	//Validation
+	if err := recipientReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	result, err := r.Service.PublishUpdate(ctx, recipientReq.Sender, recipientReq.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.PublishedUpdate{
+		Success:            true,
+		ID:                 strconv.Itoa(result.ID),
+		RecipientCount:     result.RecipientCount,
+		UnresolvedMentions: result.Unresolved,
+	}, nil
+}
+
+func (r *mutationResolver) CreateWebhook(ctx context.Context, input graphmodel.NewWebhook) (*graphmodel.Webhook, error) {
+	// Decode request body
+	webhookReq := validation.WebhookRequest{
+		URL:    input.URL,
+		Secret: input.Secret,
+		Events: make([]webhooks.Event, len(input.Events)),
+	}
+	for i, event := range input.Events {
+		webhookReq.Events[i] = webhookEvents[event]
+	}
+
+	//Validation
+	if err := webhookReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	webhook, err := r.Service.CreateWebhook(ctx, webhookReq.URL, webhookReq.Secret, webhookReq.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return newWebhook(webhook), nil
+}
+
+func (r *mutationResolver) DeleteWebhook(ctx context.Context, id string) (*graphmodel.IsSuccess, error) {
+	//Validation
+	webhookId, err := parseWebhookID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.Service.DeleteWebhook(ctx, webhookId); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.IsSuccess{
+		Success: true,
+	}, nil
+}
+
+func (r *mutationResolver) FriendList(ctx context.Context, input graphmodel.EmailInput) (*graphmodel.FriendList, error) {
+	// Deprecated alias of Query.friendList
+	return r.Query().FriendList(ctx, input, nil, nil, nil, nil)
+}
+
+func (r *mutationResolver) CommonFriends(ctx context.Context, input graphmodel.Friends) (*graphmodel.FriendList, error) {
+	// Deprecated alias of Query.commonFriends
+	return r.Query().CommonFriends(ctx, input)
+}
+
+func (r *mutationResolver) RetrieveEmailReceiveUpdate(ctx context.Context, input graphmodel.SendMail) (*graphmodel.Recipients, error) {
+	// Deprecated alias of Query.retrieveEmailReceiveUpdate
+	return r.Query().RetrieveEmailReceiveUpdate(ctx, input, nil, nil, nil, nil)
+}
+
+func (r *queryResolver) Me(ctx context.Context) (*graphmodel.User, error) {
+	user, err := r.Service.GetUser(ctx, auth.ForContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return newUser(user), nil
+}
+
+func (r *queryResolver) User(ctx context.Context, email string) (*graphmodel.User, error) {
+	//Decode request body
+	userReq := validation.UserRequest{
+		Email: email,
+	}
+
+	//Validation
+	if err := userReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := r.Service.GetUser(ctx, userReq.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return newUser(user), nil
+}
+
+func (r *queryResolver) Users(ctx context.Context, first *int, after *string, last *int, before *string) (*graphmodel.Users, error) {
+	//Validation
+	page, err := pagination.New(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.Service.GetUsersPage(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.Users{
+		Success:  true,
+		Emails:   result.Emails,
+		Count:    result.TotalCount,
+		Edges:    newEdges(result),
+		PageInfo: newPageInfo(result),
+	}, nil
+}
+
+func (r *queryResolver) FriendList(ctx context.Context, input graphmodel.EmailInput, first *int, after *string, last *int, before *string) (*graphmodel.FriendList, error) {
+	//Decode request body
+	userReq := validation.UserRequest{
+		Email: input.Email,
+	}
+
+	//Validation
+	if err := userReq.Validate(); err != nil {
+		return nil, err
+	}
+	page, err := pagination.New(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.Service.GetFriendsPage(ctx, userReq.Email, page)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.FriendList{
+		Success:  true,
+		Friends:  result.Emails,
+		Count:    result.TotalCount,
+		Edges:    newEdges(result),
+		PageInfo: newPageInfo(result),
+	}, nil
+}
+
+func (r *queryResolver) CommonFriends(ctx context.Context, input graphmodel.Friends) (*graphmodel.FriendList, error) {
+	// Decode request body
+	friendReq := validation.FriendRequest{}
 	for _, email := range input.Friends {
 		friendReq.Emails = append(friendReq.Emails, email)
 	}
@@ -23,19 +526,81 @@ func (r *mutationResolver) CreateFriend(ctx context.Context, input graphmodel.Fr
 		return nil, err
 	}
 
-	if err := r.Service.CreateFriend(ctx, friendReq.Emails[0], friendReq.Emails[1]); err != nil {
+	commonFriends, err := r.Service.GetCommonFriends(ctx, friendReq.Emails[0], friendReq.Emails[1])
+	if err != nil {
 		return nil, err
 	}
 
 	//Response
-	return &graphmodel.IsSuccess{
+	result := pagination.Full(commonFriends)
+	return &graphmodel.FriendList{
+		Success:  true,
+		Friends:  result.Emails,
+		Count:    result.TotalCount,
+		Edges:    newEdges(result),
+		PageInfo: newPageInfo(result),
+	}, nil
+}
+
+func (r *queryResolver) RetrieveEmailReceiveUpdate(ctx context.Context, input graphmodel.SendMail, first *int, after *string, last *int, before *string) (*graphmodel.Recipients, error) {
+	// Decode request body
+	recipientReq := validation.RecipientsRequest{
+		Sender: input.Sender,
+		Text:   input.Text,
+	}
+
+	//Validation
+	if err := recipientReq.Validate(); err != nil {
+		return nil, err
+	}
+	page, err := pagination.New(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.Service.GetRecipientEmailsPage(ctx, recipientReq.Sender, recipientReq.Text, page)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.Recipients{
+		Success:            true,
+		Recipients:         result.Emails,
+		Count:              result.TotalCount,
+		Edges:              newEdges(result.Result),
+		PageInfo:           newPageInfo(result.Result),
+		UnresolvedMentions: result.Unresolved,
+	}, nil
+}
+
+func (r *queryResolver) IncomingFriendRequests(ctx context.Context, input graphmodel.EmailInput) (*graphmodel.FriendRequests, error) {
+	//Decode request body
+	userReq := validation.UserRequest{
+		Email: input.Email,
+	}
+
+	//Validation
+	if err := userReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	emails, err := r.Service.GetIncomingFriendRequests(ctx, userReq.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return &graphmodel.FriendRequests{
 		Success: true,
+		Emails:  emails,
+		Count:   len(emails),
 	}, nil
 }
 
-func (r *mutationResolver) FriendList(ctx context.Context, input graphmodel.Email) (*graphmodel.FriendList, error) {
+func (r *queryResolver) OutgoingFriendRequests(ctx context.Context, input graphmodel.EmailInput) (*graphmodel.FriendRequests, error) {
 	//Decode request body
-	userReq := UserRequest{
+	userReq := validation.UserRequest{
 		Email: input.Email,
 	}
 
@@ -44,75 +609,218 @@ func (r *mutationResolver) FriendList(ctx context.Context, input graphmodel.Emai
 		return nil, err
 	}
 
-	friendEmails, err := r.Service.GetFriends(ctx, userReq.Email)
+	emails, err := r.Service.GetOutgoingFriendRequests(ctx, userReq.Email)
 	if err != nil {
 		return nil, err
 	}
 
 	//Response
-	return &graphmodel.FriendList{
+	return &graphmodel.FriendRequests{
 		Success: true,
-		Friends: friendEmails,
-		Count:   len(friendEmails),
+		Emails:  emails,
+		Count:   len(emails),
 	}, nil
 }
 
-func (r *mutationResolver) CommonFriends(ctx context.Context, input graphmodel.Friends) (*graphmodel.FriendList, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) Webhooks(ctx context.Context) ([]*graphmodel.Webhook, error) {
+	result, err := r.Service.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	webhookList := make([]*graphmodel.Webhook, len(result))
+	for i, webhook := range result {
+		webhookList[i] = newWebhook(webhook)
+	}
+	return webhookList, nil
 }
 
-func (r *mutationResolver) Subscribe(ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, webhookID string, first *int) ([]*graphmodel.WebhookDelivery, error) {
+	//Validation
+	page, err := pagination.New(first, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	webhookId, err := parseWebhookID(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.Service.GetWebhookDeliveries(ctx, webhookId, page.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	deliveries := make([]*graphmodel.WebhookDelivery, len(result))
+	for i, delivery := range result {
+		deliveries[i] = newWebhookDelivery(delivery)
+	}
+	return deliveries, nil
 }
 
-func (r *mutationResolver) BlockUpdate(ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *subscriptionResolver) FriendAdded(ctx context.Context, email string) (<-chan *graphmodel.FriendEvent, error) {
+	// Decode request body
+	userReq := validation.UserRequest{
+		Email: email,
+	}
+
+	//Validation
+	if err := userReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return r.friendEvents(ctx, events.FriendAdded, userReq.Email), nil
 }
 
-func (r *mutationResolver) RetrieveEmailReceiveUpdate(ctx context.Context, input graphmodel.SendMail) (*graphmodel.Recipients, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *subscriptionResolver) FriendRemoved(ctx context.Context, email string) (<-chan *graphmodel.FriendEvent, error) {
+	// Decode request body
+	userReq := validation.UserRequest{
+		Email: email,
+	}
+
+	//Validation
+	if err := userReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return r.friendEvents(ctx, events.FriendRemoved, userReq.Email), nil
+}
+
+func (r *subscriptionResolver) Blocked(ctx context.Context, email string) (<-chan *graphmodel.BlockEvent, error) {
+	// Decode request body
+	userReq := validation.UserRequest{
+		Email: email,
+	}
+
+	//Validation
+	if err := userReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return r.blockEvents(ctx, userReq.Email), nil
+}
+
+func (r *subscriptionResolver) UpdatePublished(ctx context.Context, recipient string) (<-chan *graphmodel.UpdateEvent, error) {
+	// Decode request body
+	userReq := validation.UserRequest{
+		Email: recipient,
+	}
+
+	//Validation
+	if err := userReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	//Response
+	return r.updateEvents(ctx, userReq.Email), nil
+}
+
+func (r *userResolver) Friends(ctx context.Context, obj *graphmodel.User, first *int, after *string, last *int, before *string) (*graphmodel.UserConnection, error) {
+	//Validation
+	page, err := pagination.New(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.Service.GetFriendsPage(ctx, obj.Email, page)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return r.userConnection(ctx, result)
 }
 
-func (r *queryResolver) Users(ctx context.Context) (*graphmodel.Users, error) {
-	emails, err := r.Service.GetUsers(ctx)
+func (r *userResolver) Subscribers(ctx context.Context, obj *graphmodel.User, first *int, after *string, last *int, before *string) (*graphmodel.UserConnection, error) {
+	//Validation
+	page, err := pagination.New(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.Service.GetSubscribersPage(ctx, obj.Email, page)
 	if err != nil {
 		return nil, err
 	}
 
 	//Response
-	return &graphmodel.Users{
-		Success: true,
-		Emails:  emails,
-		Count:   len(emails),
-	}, nil
+	return r.userConnection(ctx, result)
+}
+
+func (r *userResolver) Subscriptions(ctx context.Context, obj *graphmodel.User, first *int, after *string, last *int, before *string) (*graphmodel.UserConnection, error) {
+	//Validation
+	page, err := pagination.New(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.Service.GetSubscriptionsPage(ctx, obj.Email, page)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return r.userConnection(ctx, result)
+}
+
+func (r *userResolver) BlockedUsers(ctx context.Context, obj *graphmodel.User, first *int, after *string, last *int, before *string) (*graphmodel.UserConnection, error) {
+	//Validation
+	page, err := pagination.New(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.Service.GetBlockedUsersPage(ctx, obj.Email, page)
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return r.userConnection(ctx, result)
+}
+
+func (r *userResolver) MutualFriends(ctx context.Context, obj *graphmodel.User, with string) ([]*graphmodel.User, error) {
+	// Decode request body
+	friendReq := validation.FriendRequest{
+		Emails: []string{obj.Email, with},
+	}
+
+	//Validation
+	if err := friendReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	commonFriends, err := r.Service.GetCommonFriends(ctx, friendReq.Emails[0], friendReq.Emails[1])
+	if err != nil {
+		return nil, err
+	}
+
+	//Response
+	return r.usersOf(ctx, commonFriends)
 }
 
+// EmailEdge returns generated.EmailEdgeResolver implementation.
+func (r *Resolver) EmailEdge() generated.EmailEdgeResolver { return &emailEdgeResolver{r} }
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
+// User returns generated.UserResolver implementation.
+func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
+
+type emailEdgeResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
-
-// !!! WARNING !!!
-// The code below was going to be deleted when updating resolvers. It has been copied here so you have
-// one last chance to move it out of harms way if you want. There are two reasons this happens:
-//  - When renaming or deleting a resolver the old code will be put in here. You can safely delete
-//    it when you're done.
-//  - You have helper methods in this file. Move them out to keep these resolver files clean.
-type FriendRequest struct {
-	Emails []string `json:"friends"`
-}
-type UserRequest struct {
-	Email string `json:"email"`
-}
-type RequestorRequest struct {
-	Requestor string `json:"requestor"`
-	Target    string `json:"target"`
-}
-type RecipientsRequest struct {
-	Sender string `json:"sender"`
-	Text   string `json:"text"`
-}
+type subscriptionResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
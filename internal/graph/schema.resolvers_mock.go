@@ -3,6 +3,9 @@ package graph
 import (
 	"context"
 
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/services"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -10,7 +13,7 @@ type SpecService struct {
 	mock.Mock
 }
 
-func (m SpecService) CreateFriend(ctx context.Context, userEmail string, friendEmail string) error {
+func (m SpecService) RemoveFriend(ctx context.Context, userEmail string, friendEmail string) error {
 	args := m.Called(ctx, userEmail, friendEmail)
 	var r error
 	if args.Get(0) != nil {
@@ -50,6 +53,15 @@ func (m SpecService) CreateSubscription(ctx context.Context, requestorEmail stri
 	return r
 }
 
+func (m SpecService) RemoveSubscription(ctx context.Context, requestorEmail string, targetEmail string) error {
+	args := m.Called(ctx, requestorEmail, targetEmail)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
 func (m SpecService) CreateUserBlock(ctx context.Context, requestorEmail string, targetEmail string) error {
 	args := m.Called(ctx, requestorEmail, targetEmail)
 	var r error
@@ -59,9 +71,20 @@ func (m SpecService) CreateUserBlock(ctx context.Context, requestorEmail string,
 	return r
 }
 
-func (m SpecService) GetRecipientEmails(ctx context.Context, senderEmail string, text string) ([]string, error) {
+func (m SpecService) RemoveUserBlock(ctx context.Context, requestorEmail string, targetEmail string) (services.UnblockResult, error) {
+	args := m.Called(ctx, requestorEmail, targetEmail)
+	r1 := args.Get(0).(services.UnblockResult)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) GetRecipientEmails(ctx context.Context, senderEmail string, text string) (services.Recipients, error) {
 	args := m.Called(ctx, senderEmail, text)
-	r1 := args.Get(0).([]string)
+	r1 := args.Get(0).(services.Recipients)
 
 	var r2 error
 	if args.Get(1) != nil {
@@ -80,3 +103,251 @@ func (m SpecService) GetUsers(ctx context.Context) ([]string, error) {
 	}
 	return r1, r2
 }
+
+func (m SpecService) SendFriendRequest(ctx context.Context, requestorEmail string, targetEmail string) error {
+	args := m.Called(ctx, requestorEmail, targetEmail)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (m SpecService) AcceptFriendRequest(ctx context.Context, requestorEmail string, targetEmail string) error {
+	args := m.Called(ctx, requestorEmail, targetEmail)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (m SpecService) DeclineFriendRequest(ctx context.Context, requestorEmail string, targetEmail string) error {
+	args := m.Called(ctx, requestorEmail, targetEmail)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (m SpecService) CancelFriendRequest(ctx context.Context, requestorEmail string, targetEmail string) error {
+	args := m.Called(ctx, requestorEmail, targetEmail)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (m SpecService) GetIncomingFriendRequests(ctx context.Context, userEmail string) ([]string, error) {
+	args := m.Called(ctx, userEmail)
+	r1 := args.Get(0).([]string)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) GetOutgoingFriendRequests(ctx context.Context, userEmail string) ([]string, error) {
+	args := m.Called(ctx, userEmail)
+	r1 := args.Get(0).([]string)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) CreateUser(ctx context.Context, name string, email string, password string) error {
+	args := m.Called(ctx, name, email, password)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (m SpecService) UpdateUser(ctx context.Context, email string, name string, newEmail string) error {
+	args := m.Called(ctx, email, name, newEmail)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (m SpecService) DeleteUser(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (m SpecService) Login(ctx context.Context, email string, password string) (string, error) {
+	args := m.Called(ctx, email, password)
+	r1 := args.Get(0).(string)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) GetUserRole(ctx context.Context, email string) (string, error) {
+	args := m.Called(ctx, email)
+	r1 := args.Get(0).(string)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) GetUsersPage(ctx context.Context, page pagination.Page) (pagination.Result, error) {
+	args := m.Called(ctx, page)
+	r1 := args.Get(0).(pagination.Result)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) GetFriendsPage(ctx context.Context, userEmail string, page pagination.Page) (pagination.Result, error) {
+	args := m.Called(ctx, userEmail, page)
+	r1 := args.Get(0).(pagination.Result)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) GetRecipientEmailsPage(ctx context.Context, senderEmail string, text string, page pagination.Page) (services.RecipientsPage, error) {
+	args := m.Called(ctx, senderEmail, text, page)
+	r1 := args.Get(0).(services.RecipientsPage)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) GetSubscribersPage(ctx context.Context, userEmail string, page pagination.Page) (pagination.Result, error) {
+	args := m.Called(ctx, userEmail, page)
+	r1 := args.Get(0).(pagination.Result)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) GetSubscriptionsPage(ctx context.Context, userEmail string, page pagination.Page) (pagination.Result, error) {
+	args := m.Called(ctx, userEmail, page)
+	r1 := args.Get(0).(pagination.Result)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) GetBlockedUsersPage(ctx context.Context, userEmail string, page pagination.Page) (pagination.Result, error) {
+	args := m.Called(ctx, userEmail, page)
+	r1 := args.Get(0).(pagination.Result)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) GetUser(ctx context.Context, email string) (services.User, error) {
+	args := m.Called(ctx, email)
+	r1 := args.Get(0).(services.User)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) GetUsersByEmails(ctx context.Context, emails []string) ([]services.User, error) {
+	args := m.Called(ctx, emails)
+	r1 := args.Get(0).([]services.User)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) PublishUpdate(ctx context.Context, senderEmail string, text string) (services.PublishedUpdate, error) {
+	args := m.Called(ctx, senderEmail, text)
+	r1 := args.Get(0).(services.PublishedUpdate)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) CreateWebhook(ctx context.Context, url string, secret string, events []webhooks.Event) (webhooks.Webhook, error) {
+	args := m.Called(ctx, url, secret, events)
+	r1 := args.Get(0).(webhooks.Webhook)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) GetWebhooks(ctx context.Context) ([]webhooks.Webhook, error) {
+	args := m.Called(ctx)
+	r1 := args.Get(0).([]webhooks.Webhook)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecService) DeleteWebhook(ctx context.Context, webhookId int) error {
+	args := m.Called(ctx, webhookId)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (m SpecService) GetWebhookDeliveries(ctx context.Context, webhookId int, limit int) ([]webhooks.DeliveryLog, error) {
+	args := m.Called(ctx, webhookId, limit)
+	r1 := args.Get(0).([]webhooks.DeliveryLog)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
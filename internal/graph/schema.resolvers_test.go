@@ -2,14 +2,90 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/auth"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/graphmodel"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/services"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func TestMutationResolver_CreateFriend(t *testing.T) {
+	tcs := map[string]struct {
+		input         graphmodel.Friends
+		actor         string
+		mockRequestor string
+		mockTarget    string
+		expResult     *graphmodel.IsSuccess
+		expError      error
+		mockErr       error
+	}{
+		"success with an input": {
+			input: graphmodel.Friends{
+				Friends: []string{"andy@example.com", "john@example.com"},
+			},
+			actor:         "andy@example.com",
+			mockRequestor: "andy@example.com",
+			mockTarget:    "john@example.com",
+			expResult: &graphmodel.IsSuccess{
+				Success: true,
+			},
+		},
+		"success with the actor as the second friend": {
+			input: graphmodel.Friends{
+				Friends: []string{"andy@example.com", "john@example.com"},
+			},
+			actor:         "john@example.com",
+			mockRequestor: "john@example.com",
+			mockTarget:    "andy@example.com",
+			expResult: &graphmodel.IsSuccess{
+				Success: true,
+			},
+		},
+		"failed with an existing friend request": {
+			input: graphmodel.Friends{
+				Friends: []string{"andy@example.com", "john@example.com"},
+			},
+			actor:         "andy@example.com",
+			mockRequestor: "andy@example.com",
+			mockTarget:    "john@example.com",
+			mockErr:       errs.NewConflictError(errs.CodeFriendRequestExists, errs.MsgExistedFriendRequest),
+			expError:      errors.New(errs.MsgExistedFriendRequest),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := auth.WithUser(context.Background(), testCase.actor)
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("SendFriendRequest", mock.Anything, testCase.mockRequestor, testCase.mockTarget).Return(testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+			mut := r.Mutation()
+
+			//When
+			result, err := mut.CreateFriend(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_Unfriend(t *testing.T) {
 	tcs := map[string]struct {
 		input     graphmodel.Friends
 		expResult *graphmodel.IsSuccess
@@ -24,6 +100,19 @@ func TestMutationResolver_CreateFriend(t *testing.T) {
 				Success: true,
 			},
 		},
+		"failed with an input validation failure (two emails are similar)": {
+			input: graphmodel.Friends{
+				Friends: []string{"andy@example.com", "andy@example.com"},
+			},
+			expError: errors.New("Two email addresses must be different"),
+		},
+		"failed with an friendship is not existing": {
+			input: graphmodel.Friends{
+				Friends: []string{"andy@example.com", "john@example.com"},
+			},
+			mockErr:  errors.New("The friend relationship does not exist"),
+			expError: errors.New("The friend relationship does not exist"),
+		},
 	}
 	for desc, testCase := range tcs {
 		t.Run(desc, func(t *testing.T) {
@@ -31,7 +120,7 @@ func TestMutationResolver_CreateFriend(t *testing.T) {
 			ctx := context.Background()
 			var mockService SpecService
 			mockService.ExpectedCalls = []*mock.Call{
-				mockService.On("CreateFriend", mock.Anything, mock.Anything, mock.Anything).Return(testCase.mockErr),
+				mockService.On("RemoveFriend", mock.Anything, mock.Anything, mock.Anything).Return(testCase.mockErr),
 			}
 
 			r := Resolver{
@@ -40,14 +129,1337 @@ func TestMutationResolver_CreateFriend(t *testing.T) {
 			mut := r.Mutation()
 
 			//When
-			result, err := mut.CreateFriend(ctx, testCase.input)
+			result, err := mut.Unfriend(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_Subscribe(t *testing.T) {
+	tcs := map[string]struct {
+		input     graphmodel.RequestTarget
+		expResult *graphmodel.IsSuccess
+		expError  error
+		mockErr   error
+	}{
+		"success with an input": {
+			input: graphmodel.RequestTarget{
+				Requestor: "andy@example.com",
+				Target:    "john@example.com",
+			},
+			expResult: &graphmodel.IsSuccess{
+				Success: true,
+			},
+		},
+		"failed with an input validation failure (two emails are similar)": {
+			input: graphmodel.RequestTarget{
+				Requestor: "andy@example.com",
+				Target:    "andy@example.com",
+			},
+			expError: errors.New("Two email addresses must be different"),
+		},
+		"failed with a service error": {
+			input: graphmodel.RequestTarget{
+				Requestor: "andy@example.com",
+				Target:    "john@example.com",
+			},
+			mockErr:  errors.New("The users have subscribed each other"),
+			expError: errors.New("The users have subscribed each other"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("CreateSubscription", mock.Anything, mock.Anything, mock.Anything).Return(testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+			mut := r.Mutation()
+
+			//When
+			result, err := mut.Subscribe(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_Unsubscribe(t *testing.T) {
+	tcs := map[string]struct {
+		input     graphmodel.RequestTarget
+		expResult *graphmodel.IsSuccess
+		expError  error
+		mockErr   error
+	}{
+		"success with an input": {
+			input: graphmodel.RequestTarget{
+				Requestor: "andy@example.com",
+				Target:    "lisa@example.com",
+			},
+			expResult: &graphmodel.IsSuccess{
+				Success: true,
+			},
+		},
+		"failed with an input validation failure (target invalid)": {
+			input: graphmodel.RequestTarget{
+				Requestor: "andy@example.com",
+			},
+			expError: errors.New("Target field invalid format"),
+		},
+		"failed with an subscription is not existing": {
+			input: graphmodel.RequestTarget{
+				Requestor: "andy@example.com",
+				Target:    "john@example.com",
+			},
+			mockErr:  errors.New("The subscription does not exist"),
+			expError: errors.New("The subscription does not exist"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("RemoveSubscription", mock.Anything, mock.Anything, mock.Anything).Return(testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+			mut := r.Mutation()
+
+			//When
+			result, err := mut.Unsubscribe(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_BlockUpdate(t *testing.T) {
+	tcs := map[string]struct {
+		input     graphmodel.RequestTarget
+		expResult *graphmodel.IsSuccess
+		expError  error
+		mockErr   error
+	}{
+		"success with an input": {
+			input: graphmodel.RequestTarget{
+				Requestor: "andy@example.com",
+				Target:    "john@example.com",
+			},
+			expResult: &graphmodel.IsSuccess{
+				Success: true,
+			},
+		},
+		"failed with an input validation failure (requestor invalid)": {
+			input: graphmodel.RequestTarget{
+				Target: "john@example.com",
+			},
+			expError: errors.New("Requestor field invalid format"),
+		},
+		"failed with a service error": {
+			input: graphmodel.RequestTarget{
+				Requestor: "andy@example.com",
+				Target:    "john@example.com",
+			},
+			mockErr:  errors.New("The users have blocked each other"),
+			expError: errors.New("The users have blocked each other"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("CreateUserBlock", mock.Anything, mock.Anything, mock.Anything).Return(testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+			mut := r.Mutation()
+
+			//When
+			result, err := mut.BlockUpdate(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_Unblock(t *testing.T) {
+	tcs := map[string]struct {
+		input      graphmodel.RequestTarget
+		expResult  *graphmodel.UnblockResult
+		expError   error
+		mockResult services.UnblockResult
+		mockErr    error
+	}{
+		"success with an input": {
+			input: graphmodel.RequestTarget{
+				Requestor: "john@example.com",
+				Target:    "lisa@example.com",
+			},
+			mockResult: services.UnblockResult{FriendshipRestored: true},
+			expResult: &graphmodel.UnblockResult{
+				Success:            true,
+				FriendshipRestored: true,
+			},
+		},
+		"failed with an input validation failure (two emails are similar)": {
+			input: graphmodel.RequestTarget{
+				Requestor: "john@example.com",
+				Target:    "john@example.com",
+			},
+			expError: errors.New("Two email addresses must be different"),
+		},
+		"failed with an blocking relationship is created by target": {
+			input: graphmodel.RequestTarget{
+				Requestor: "lisa@example.com",
+				Target:    "john@example.com",
+			},
+			mockErr:  errors.New("Only the requestor of the block can unblock the target"),
+			expError: errors.New("Only the requestor of the block can unblock the target"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("RemoveUserBlock", mock.Anything, mock.Anything, mock.Anything).Return(testCase.mockResult, testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+			mut := r.Mutation()
+
+			//When
+			result, err := mut.Unblock(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestQueryResolver_Users(t *testing.T) {
+	first := 2
+	last := 101
+	after := pagination.EncodeCursor("andy@example.com")
+	johnCursor := pagination.EncodeCursor("john@example.com")
+	kateCursor := pagination.EncodeCursor("kate@example.com")
+
+	tcs := map[string]struct {
+		first      *int
+		after      *string
+		last       *int
+		expPage    pagination.Page
+		expResult  *graphmodel.Users
+		expError   error
+		mockResult pagination.Result
+		mockErr    error
+	}{
+		"success with first and after": {
+			first:   &first,
+			after:   &after,
+			expPage: pagination.Page{Size: 2, After: "andy@example.com"},
+			mockResult: pagination.Result{
+				Emails:          []string{"john@example.com", "kate@example.com"},
+				HasNextPage:     true,
+				HasPreviousPage: true,
+				TotalCount:      5,
+			},
+			expResult: &graphmodel.Users{
+				Success: true,
+				Emails:  []string{"john@example.com", "kate@example.com"},
+				Count:   5,
+				Edges: []*graphmodel.EmailEdge{
+					{Cursor: johnCursor, Node: "john@example.com"},
+					{Cursor: kateCursor, Node: "kate@example.com"},
+				},
+				PageInfo: &graphmodel.PageInfo{
+					HasNextPage:     true,
+					HasPreviousPage: true,
+					StartCursor:     &johnCursor,
+					EndCursor:       &kateCursor,
+				},
+			},
+		},
+		"success with an empty page": {
+			expPage:    pagination.Page{Size: pagination.DefaultSize},
+			mockResult: pagination.Result{Emails: []string{}},
+			expResult: &graphmodel.Users{
+				Success:  true,
+				Emails:   []string{},
+				Edges:    []*graphmodel.EmailEdge{},
+				PageInfo: &graphmodel.PageInfo{},
+			},
+		},
+		"failed with first and last": {
+			first:    &first,
+			last:     &first,
+			expError: errors.New("First and last cannot be used together"),
+		},
+		"failed with a size over the maximum": {
+			last:     &last,
+			expError: errors.New("First and last must be from 0 to 100"),
+		},
+		"failed with a service error": {
+			after:    &kateCursor,
+			first:    &first,
+			expPage:  pagination.Page{Size: 2, After: "kate@example.com"},
+			mockErr:  errors.New("sql: database is closed"),
+			expError: errors.New("sql: database is closed"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("GetUsersPage", mock.Anything, testCase.expPage).Return(testCase.mockResult, testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := r.Query().Users(ctx, testCase.first, testCase.after, testCase.last, nil)
 
 			//Then
 			if testCase.expError != nil {
 				require.EqualError(t, err, testCase.expError.Error())
 			} else {
+				require.NoError(t, err)
 				require.Equal(t, testCase.expResult, result)
 			}
 		})
 	}
 }
+
+func TestQueryResolver_FriendList(t *testing.T) {
+	last := 1
+	before := pagination.EncodeCursor("kate@example.com")
+	invalidCursor := "kate@example.com"
+	johnCursor := pagination.EncodeCursor("john@example.com")
+
+	tcs := map[string]struct {
+		input       graphmodel.EmailInput
+		last        *int
+		before      *string
+		expPage     pagination.Page
+		expResult   *graphmodel.FriendList
+		expError    error
+		mockFriends pagination.Result
+		mockErr     error
+	}{
+		"success with an input": {
+			input:   graphmodel.EmailInput{Email: "andy@example.com"},
+			last:    &last,
+			before:  &before,
+			expPage: pagination.Page{Size: 1, Before: "kate@example.com", Backward: true},
+			mockFriends: pagination.Result{
+				Emails:          []string{"john@example.com"},
+				HasNextPage:     true,
+				HasPreviousPage: true,
+				TotalCount:      3,
+			},
+			expResult: &graphmodel.FriendList{
+				Success: true,
+				Friends: []string{"john@example.com"},
+				Count:   3,
+				Edges: []*graphmodel.EmailEdge{
+					{Cursor: johnCursor, Node: "john@example.com"},
+				},
+				PageInfo: &graphmodel.PageInfo{
+					HasNextPage:     true,
+					HasPreviousPage: true,
+					StartCursor:     &johnCursor,
+					EndCursor:       &johnCursor,
+				},
+			},
+		},
+		"failed with an input validation failure": {
+			input:    graphmodel.EmailInput{Email: "andy@examplecom"},
+			expError: errors.New(`andy@examplecom invalid format (ex: "andy@example.com")`),
+		},
+		"failed with an invalid cursor": {
+			input:    graphmodel.EmailInput{Email: "andy@example.com"},
+			before:   &invalidCursor,
+			expError: errors.New("Cursor invalid format"),
+		},
+		"failed with a service error": {
+			input:    graphmodel.EmailInput{Email: "test@example.com"},
+			last:     &last,
+			before:   &before,
+			expPage:  pagination.Page{Size: 1, Before: "kate@example.com", Backward: true},
+			mockErr:  errors.New("test@example.com is not exists"),
+			expError: errors.New("test@example.com is not exists"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("GetFriendsPage", mock.Anything, testCase.input.Email, testCase.expPage).Return(testCase.mockFriends, testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := r.Query().FriendList(ctx, testCase.input, nil, nil, testCase.last, testCase.before)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_FriendList(t *testing.T) {
+	//Given
+	ctx := context.Background()
+	var mockService SpecService
+	mockService.ExpectedCalls = []*mock.Call{
+		mockService.On("GetFriendsPage", mock.Anything, "andy@example.com", pagination.Page{Size: pagination.DefaultSize}).
+			Return(pagination.Result{Emails: []string{"john@example.com"}, TotalCount: 1}, nil),
+	}
+
+	r := Resolver{
+		Service: mockService,
+	}
+
+	//When
+	result, err := r.Mutation().FriendList(ctx, graphmodel.EmailInput{Email: "andy@example.com"})
+
+	//Then
+	require.NoError(t, err)
+	require.Equal(t, []string{"john@example.com"}, result.Friends)
+	require.Equal(t, 1, result.Count)
+}
+
+func TestQueryResolver_CommonFriends(t *testing.T) {
+	commonCursor := pagination.EncodeCursor("common@example.com")
+
+	tcs := map[string]struct {
+		input       graphmodel.Friends
+		expResult   *graphmodel.FriendList
+		expError    error
+		mockFriends []string
+		mockErr     error
+	}{
+		"success with an input": {
+			input: graphmodel.Friends{
+				Friends: []string{"andy@example.com", "john@example.com"},
+			},
+			mockFriends: []string{"common@example.com"},
+			expResult: &graphmodel.FriendList{
+				Success: true,
+				Friends: []string{"common@example.com"},
+				Count:   1,
+				Edges: []*graphmodel.EmailEdge{
+					{Cursor: commonCursor, Node: "common@example.com"},
+				},
+				PageInfo: &graphmodel.PageInfo{
+					StartCursor: &commonCursor,
+					EndCursor:   &commonCursor,
+				},
+			},
+		},
+		"failed with an input validation failure (number of emails are wrong)": {
+			input: graphmodel.Friends{
+				Friends: []string{"andy@example.com"},
+			},
+			expError: errors.New("Number of email addresses must be 2"),
+		},
+		"failed with a service error": {
+			input: graphmodel.Friends{
+				Friends: []string{"andy@example.com", "test@example.com"},
+			},
+			mockErr:  errors.New("test@example.com is not exists"),
+			expError: errors.New("test@example.com is not exists"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("GetCommonFriends", mock.Anything, mock.Anything, mock.Anything).Return(testCase.mockFriends, testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := r.Query().CommonFriends(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestQueryResolver_RetrieveEmailReceiveUpdate(t *testing.T) {
+	first := 2
+	commonCursor := pagination.EncodeCursor("common@example.com")
+	kateCursor := pagination.EncodeCursor("kate@example.com")
+
+	tcs := map[string]struct {
+		input          graphmodel.SendMail
+		first          *int
+		expPage        pagination.Page
+		expResult      *graphmodel.Recipients
+		expError       error
+		mockRecipients services.RecipientsPage
+		mockErr        error
+	}{
+		"success with an input": {
+			input: graphmodel.SendMail{
+				Sender: "lisa@example.com",
+				Text:   "Hello World! kate@example.com bob@example.com",
+			},
+			first:   &first,
+			expPage: pagination.Page{Size: 2},
+			mockRecipients: services.RecipientsPage{
+				Result: pagination.Result{
+					Emails:     []string{"common@example.com", "kate@example.com"},
+					TotalCount: 2,
+				},
+				Unresolved: []string{"bob@example.com"},
+			},
+			expResult: &graphmodel.Recipients{
+				Success:    true,
+				Recipients: []string{"common@example.com", "kate@example.com"},
+				Count:      2,
+				Edges: []*graphmodel.EmailEdge{
+					{Cursor: commonCursor, Node: "common@example.com"},
+					{Cursor: kateCursor, Node: "kate@example.com"},
+				},
+				PageInfo: &graphmodel.PageInfo{
+					StartCursor: &commonCursor,
+					EndCursor:   &kateCursor,
+				},
+				UnresolvedMentions: []string{"bob@example.com"},
+			},
+		},
+		"failed with an input validation failure (text invalid)": {
+			input: graphmodel.SendMail{
+				Sender: "lisa@example.com",
+			},
+			expError: errors.New("Text field invalid format"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("GetRecipientEmailsPage", mock.Anything, testCase.input.Sender, testCase.input.Text, testCase.expPage).
+					Return(testCase.mockRecipients, testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := r.Query().RetrieveEmailReceiveUpdate(ctx, testCase.input, testCase.first, nil, nil, nil)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_PublishUpdate(t *testing.T) {
+	tcs := map[string]struct {
+		input      graphmodel.SendMail
+		expResult  *graphmodel.PublishedUpdate
+		expError   error
+		mockResult services.PublishedUpdate
+		mockErr    error
+	}{
+		"success with an input": {
+			input: graphmodel.SendMail{
+				Sender: "lisa@example.com",
+				Text:   "Hello World! kate@example.com bob@example.com",
+			},
+			mockResult: services.PublishedUpdate{ID: 7, RecipientCount: 2, Unresolved: []string{"bob@example.com"}},
+			expResult: &graphmodel.PublishedUpdate{
+				Success:            true,
+				ID:                 "7",
+				RecipientCount:     2,
+				UnresolvedMentions: []string{"bob@example.com"},
+			},
+		},
+		"failed with an input validation failure (text invalid)": {
+			input: graphmodel.SendMail{
+				Sender: "lisa@example.com",
+			},
+			expError: errors.New("Text field invalid format"),
+		},
+		"failed with an unknown sender": {
+			input: graphmodel.SendMail{
+				Sender: "bob@example.com",
+				Text:   "Hello World!",
+			},
+			mockErr:  errors.New("bob@example.com is not exists"),
+			expError: errors.New("bob@example.com is not exists"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("PublishUpdate", mock.Anything, testCase.input.Sender, testCase.input.Text).
+					Return(testCase.mockResult, testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := r.Mutation().PublishUpdate(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_FriendRequests(t *testing.T) {
+	type resolverFunc func(r Resolver, ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error)
+
+	resolvers := map[string]resolverFunc{
+		"SendFriendRequest": func(r Resolver, ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
+			return r.Mutation().SendFriendRequest(ctx, input)
+		},
+		"AcceptFriendRequest": func(r Resolver, ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
+			return r.Mutation().AcceptFriendRequest(ctx, input)
+		},
+		"DeclineFriendRequest": func(r Resolver, ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
+			return r.Mutation().DeclineFriendRequest(ctx, input)
+		},
+		"CancelFriendRequest": func(r Resolver, ctx context.Context, input graphmodel.RequestTarget) (*graphmodel.IsSuccess, error) {
+			return r.Mutation().CancelFriendRequest(ctx, input)
+		},
+	}
+
+	tcs := map[string]struct {
+		input     graphmodel.RequestTarget
+		expResult *graphmodel.IsSuccess
+		expError  error
+		mockErr   error
+	}{
+		"success with an input": {
+			input: graphmodel.RequestTarget{
+				Requestor: "andy@example.com",
+				Target:    "kate@example.com",
+			},
+			expResult: &graphmodel.IsSuccess{
+				Success: true,
+			},
+		},
+		"failed with an input validation failure (same email)": {
+			input: graphmodel.RequestTarget{
+				Requestor: "andy@example.com",
+				Target:    "andy@example.com",
+			},
+			expError: errors.New("Two email addresses must be different"),
+		},
+		"failed with an pending request is not existing": {
+			input: graphmodel.RequestTarget{
+				Requestor: "john@example.com",
+				Target:    "kate@example.com",
+			},
+			mockErr:  errors.New("The pending friend request does not exist"),
+			expError: errors.New("The pending friend request does not exist"),
+		},
+	}
+	for name, resolve := range resolvers {
+		for desc, testCase := range tcs {
+			t.Run(name+" "+desc, func(t *testing.T) {
+				//Given
+				ctx := context.Background()
+				var mockService SpecService
+				mockService.ExpectedCalls = []*mock.Call{
+					mockService.On(name, mock.Anything, mock.Anything, mock.Anything).Return(testCase.mockErr),
+				}
+
+				r := Resolver{
+					Service: mockService,
+				}
+
+				//When
+				result, err := resolve(r, ctx, testCase.input)
+
+				//Then
+				if testCase.expError != nil {
+					require.EqualError(t, err, testCase.expError.Error())
+				} else {
+					require.Equal(t, testCase.expResult, result)
+				}
+			})
+		}
+	}
+}
+
+func TestQueryResolver_IncomingFriendRequests(t *testing.T) {
+	tcs := map[string]struct {
+		input      graphmodel.EmailInput
+		expResult  *graphmodel.FriendRequests
+		expError   error
+		mockEmails []string
+		mockErr    error
+	}{
+		"success with an input": {
+			input:      graphmodel.EmailInput{Email: "andy@example.com"},
+			mockEmails: []string{"lisa@example.com"},
+			expResult: &graphmodel.FriendRequests{
+				Success: true,
+				Emails:  []string{"lisa@example.com"},
+				Count:   1,
+			},
+		},
+		"failed with an input validation failure": {
+			input:    graphmodel.EmailInput{Email: "andy@examplecom"},
+			expError: errors.New(`andy@examplecom invalid format (ex: "andy@example.com")`),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("GetIncomingFriendRequests", mock.Anything, mock.Anything).Return(testCase.mockEmails, testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := r.Query().IncomingFriendRequests(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestQueryResolver_OutgoingFriendRequests(t *testing.T) {
+	tcs := map[string]struct {
+		input      graphmodel.EmailInput
+		expResult  *graphmodel.FriendRequests
+		expError   error
+		mockEmails []string
+		mockErr    error
+	}{
+		"success with an input": {
+			input:      graphmodel.EmailInput{Email: "andy@example.com"},
+			mockEmails: []string{"kate@example.com"},
+			expResult: &graphmodel.FriendRequests{
+				Success: true,
+				Emails:  []string{"kate@example.com"},
+				Count:   1,
+			},
+		},
+		"failed with an user is not existing": {
+			input:    graphmodel.EmailInput{Email: "test@example.com"},
+			mockErr:  errors.New("test@example.com is not exists"),
+			expError: errors.New("test@example.com is not exists"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("GetOutgoingFriendRequests", mock.Anything, mock.Anything).Return(testCase.mockEmails, testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := r.Query().OutgoingFriendRequests(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_CreateUser(t *testing.T) {
+	tcs := map[string]struct {
+		input     graphmodel.NewUser
+		expResult *graphmodel.IsSuccess
+		expError  error
+		mockErr   error
+	}{
+		"success with an input": {
+			input: graphmodel.NewUser{
+				Name:     "tom",
+				Email:    "tom@example.com",
+				Password: "password",
+			},
+			expResult: &graphmodel.IsSuccess{
+				Success: true,
+			},
+		},
+		"failed with an input validation failure (name invalid)": {
+			input: graphmodel.NewUser{
+				Name:     " ",
+				Email:    "tom@example.com",
+				Password: "password",
+			},
+			expError: errors.New("Name field invalid format"),
+		},
+		"failed with an input validation failure (email invalid)": {
+			input: graphmodel.NewUser{
+				Name:     "tom",
+				Email:    "tom@examplecom",
+				Password: "password",
+			},
+			expError: errors.New(`tom@examplecom invalid format (ex: "andy@example.com")`),
+		},
+		"failed with an input validation failure (password too short)": {
+			input: graphmodel.NewUser{
+				Name:     "tom",
+				Email:    "tom@example.com",
+				Password: "secret",
+			},
+			expError: errors.New("Password must have from 8 to 72 characters"),
+		},
+		"failed with an email is existing": {
+			input: graphmodel.NewUser{
+				Name:     "andy",
+				Email:    "andy@example.com",
+				Password: "password",
+			},
+			mockErr:  errors.New("andy@example.com has been used by another user"),
+			expError: errors.New("andy@example.com has been used by another user"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+			mut := r.Mutation()
+
+			//When
+			result, err := mut.CreateUser(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_UpdateUser(t *testing.T) {
+	name := "andrew"
+	newEmail := "andrew@example.com"
+	invalidEmail := "andrew@examplecom"
+
+	tcs := map[string]struct {
+		input     graphmodel.UpdateUser
+		expResult *graphmodel.IsSuccess
+		expError  error
+		mockErr   error
+	}{
+		"success with an input": {
+			input: graphmodel.UpdateUser{
+				Email:    "andy@example.com",
+				Name:     &name,
+				NewEmail: &newEmail,
+			},
+			expResult: &graphmodel.IsSuccess{
+				Success: true,
+			},
+		},
+		"failed with an input validation failure (nothing to update)": {
+			input: graphmodel.UpdateUser{
+				Email: "andy@example.com",
+			},
+			expError: errors.New("Name or new email must be provided"),
+		},
+		"failed with an input validation failure (new email invalid)": {
+			input: graphmodel.UpdateUser{
+				Email:    "andy@example.com",
+				NewEmail: &invalidEmail,
+			},
+			expError: errors.New(`andrew@examplecom invalid format (ex: "andy@example.com")`),
+		},
+		"failed with an user is not existing": {
+			input: graphmodel.UpdateUser{
+				Email: "test@example.com",
+				Name:  &name,
+			},
+			mockErr:  errors.New("test@example.com is not exists"),
+			expError: errors.New("test@example.com is not exists"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("UpdateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+			mut := r.Mutation()
+
+			//When
+			result, err := mut.UpdateUser(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_DeleteUser(t *testing.T) {
+	tcs := map[string]struct {
+		input     graphmodel.EmailInput
+		expResult *graphmodel.IsSuccess
+		expError  error
+		mockErr   error
+	}{
+		"success with an input": {
+			input: graphmodel.EmailInput{Email: "andy@example.com"},
+			expResult: &graphmodel.IsSuccess{
+				Success: true,
+			},
+		},
+		"failed with an input validation failure": {
+			input:    graphmodel.EmailInput{Email: "andy@examplecom"},
+			expError: errors.New(`andy@examplecom invalid format (ex: "andy@example.com")`),
+		},
+		"failed with an user is not existing": {
+			input:    graphmodel.EmailInput{Email: "test@example.com"},
+			mockErr:  errors.New("test@example.com is not exists"),
+			expError: errors.New("test@example.com is not exists"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("DeleteUser", mock.Anything, mock.Anything).Return(testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+			mut := r.Mutation()
+
+			//When
+			result, err := mut.DeleteUser(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_Login(t *testing.T) {
+	tcs := map[string]struct {
+		input     graphmodel.Login
+		expResult *graphmodel.Token
+		expError  error
+		mockToken string
+		mockErr   error
+	}{
+		"success with an input": {
+			input: graphmodel.Login{
+				Email:    "andy@example.com",
+				Password: "password",
+			},
+			mockToken: "token",
+			expResult: &graphmodel.Token{
+				Success: true,
+				Token:   "token",
+			},
+		},
+		"failed with an input validation failure (password empty)": {
+			input: graphmodel.Login{
+				Email: "andy@example.com",
+			},
+			expError: errors.New("Password must have from 8 to 72 characters"),
+		},
+		"failed with an incorrect password": {
+			input: graphmodel.Login{
+				Email:    "andy@example.com",
+				Password: "incorrect",
+			},
+			mockErr:  errors.New("Email or password is incorrect"),
+			expError: errors.New("Email or password is incorrect"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("Login", mock.Anything, mock.Anything, mock.Anything).Return(testCase.mockToken, testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+			mut := r.Mutation()
+
+			//When
+			result, err := mut.Login(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestQueryResolver_User(t *testing.T) {
+	createdAt := time.Date(2021, 11, 27, 10, 16, 58, 0, time.UTC)
+
+	tcs := map[string]struct {
+		email     string
+		expResult *graphmodel.User
+		expError  error
+		mockUser  services.User
+		mockErr   error
+	}{
+		"success with an input": {
+			email:    "andy@example.com",
+			mockUser: services.User{ID: 101, Name: "andy", Email: "andy@example.com", CreatedAt: createdAt, UpdatedAt: createdAt},
+			expResult: &graphmodel.User{
+				ID:        "101",
+				Name:      "andy",
+				Email:     "andy@example.com",
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+			},
+		},
+		"failed with an input validation failure": {
+			email:    "andy@examplecom",
+			expError: errors.New(`andy@examplecom invalid format (ex: "andy@example.com")`),
+		},
+		"failed with a service error": {
+			email:    "test@example.com",
+			mockErr:  errors.New("test@example.com is not exists"),
+			expError: errors.New("test@example.com is not exists"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("GetUser", mock.Anything, testCase.email).Return(testCase.mockUser, testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := r.Query().User(ctx, testCase.email)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestUserResolver_Relationships(t *testing.T) {
+	first := 1
+	kateCursor := pagination.EncodeCursor("kate@example.com")
+	user := &graphmodel.User{ID: "101", Name: "andy", Email: "andy@example.com"}
+
+	tcs := map[string]struct {
+		resolve func(r *Resolver) (*graphmodel.UserConnection, error)
+		method  string
+	}{
+		"friends": {
+			resolve: func(r *Resolver) (*graphmodel.UserConnection, error) {
+				return r.User().Friends(context.Background(), user, &first, nil, nil, nil)
+			},
+			method: "GetFriendsPage",
+		},
+		"subscribers": {
+			resolve: func(r *Resolver) (*graphmodel.UserConnection, error) {
+				return r.User().Subscribers(context.Background(), user, &first, nil, nil, nil)
+			},
+			method: "GetSubscribersPage",
+		},
+		"subscriptions": {
+			resolve: func(r *Resolver) (*graphmodel.UserConnection, error) {
+				return r.User().Subscriptions(context.Background(), user, &first, nil, nil, nil)
+			},
+			method: "GetSubscriptionsPage",
+		},
+		"blocked users": {
+			resolve: func(r *Resolver) (*graphmodel.UserConnection, error) {
+				return r.User().BlockedUsers(context.Background(), user, &first, nil, nil, nil)
+			},
+			method: "GetBlockedUsersPage",
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On(testCase.method, mock.Anything, "andy@example.com", pagination.Page{Size: 1}).
+					Return(pagination.Result{Emails: []string{"kate@example.com"}, HasNextPage: true, TotalCount: 2}, nil),
+				mockService.On("GetUsersByEmails", mock.Anything, []string{"kate@example.com"}).
+					Return([]services.User{{ID: 104, Name: "kate", Email: "kate@example.com"}}, nil),
+			}
+
+			r := &Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := testCase.resolve(r)
+
+			//Then
+			require.NoError(t, err)
+			require.Equal(t, &graphmodel.UserConnection{
+				Edges: []*graphmodel.UserEdge{
+					{Cursor: kateCursor, Node: &graphmodel.User{ID: "104", Name: "kate", Email: "kate@example.com"}},
+				},
+				PageInfo: &graphmodel.PageInfo{
+					HasNextPage: true,
+					StartCursor: &kateCursor,
+					EndCursor:   &kateCursor,
+				},
+				TotalCount: 2,
+			}, result)
+		})
+	}
+}
+
+func TestUserResolver_MutualFriends(t *testing.T) {
+	user := &graphmodel.User{ID: "101", Name: "andy", Email: "andy@example.com"}
+
+	tcs := map[string]struct {
+		with        string
+		expResult   []*graphmodel.User
+		expError    error
+		mockFriends []string
+		mockErr     error
+	}{
+		"success in the order of the common friends": {
+			with:        "john@example.com",
+			mockFriends: []string{"lisa@example.com", "common@example.com"},
+			expResult: []*graphmodel.User{
+				{ID: "103", Name: "lisa", Email: "lisa@example.com"},
+				{ID: "102", Name: "common", Email: "common@example.com"},
+			},
+		},
+		"failed with the email of the user": {
+			with:     "andy@example.com",
+			expError: errors.New("Two email addresses must be different"),
+		},
+		"failed with a service error": {
+			with:     "test@example.com",
+			mockErr:  errors.New("test@example.com is not exists"),
+			expError: errors.New("test@example.com is not exists"),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("GetCommonFriends", mock.Anything, "andy@example.com", testCase.with).
+					Return(testCase.mockFriends, testCase.mockErr),
+				mockService.On("GetUsersByEmails", mock.Anything, testCase.mockFriends).
+					Return([]services.User{
+						{ID: 102, Name: "common", Email: "common@example.com"},
+						{ID: 103, Name: "lisa", Email: "lisa@example.com"},
+					}, nil),
+			}
+
+			r := &Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := r.User().MutualFriends(context.Background(), user, testCase.with)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestQueryResolver_WalkUserGraph(t *testing.T) {
+	//Given
+	var mockService SpecService
+	mockService.ExpectedCalls = []*mock.Call{
+		mockService.On("GetUser", mock.Anything, "andy@example.com").
+			Return(services.User{ID: 101, Name: "andy", Email: "andy@example.com"}, nil),
+		mockService.On("GetFriendsPage", mock.Anything, "andy@example.com", pagination.Page{Size: 2}).
+			Return(pagination.Result{Emails: []string{"common@example.com"}, TotalCount: 1}, nil),
+		mockService.On("GetUsersByEmails", mock.Anything, []string{"common@example.com"}).
+			Return([]services.User{{ID: 102, Name: "common", Email: "common@example.com"}}, nil),
+		mockService.On("GetSubscribersPage", mock.Anything, "common@example.com", pagination.Page{Size: pagination.DefaultSize}).
+			Return(pagination.Result{Emails: []string{"lisa@example.com"}, TotalCount: 1}, nil),
+		mockService.On("GetUsersByEmails", mock.Anything, []string{"lisa@example.com"}).
+			Return([]services.User{{ID: 103, Name: "lisa", Email: "lisa@example.com"}}, nil),
+	}
+	c := newDirectiveClient(mockService, "andy@example.com")
+
+	//When
+	var resp struct {
+		Me struct {
+			ID      string
+			Name    string
+			Friends struct {
+				TotalCount int
+				Edges      []struct {
+					Node struct {
+						Email       string
+						Subscribers struct {
+							Edges []struct {
+								Node struct {
+									Name string
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	err := c.Post(`{ me { id name friends(first: 2) { totalCount edges { node { email subscribers { edges { node { name } } } } } } } }`, &resp)
+
+	//Then
+	require.NoError(t, err)
+	require.Equal(t, "101", resp.Me.ID)
+	require.Equal(t, "andy", resp.Me.Name)
+	require.Equal(t, 1, resp.Me.Friends.TotalCount)
+	require.Equal(t, "common@example.com", resp.Me.Friends.Edges[0].Node.Email)
+	require.Equal(t, "lisa", resp.Me.Friends.Edges[0].Node.Subscribers.Edges[0].Node.Name)
+}
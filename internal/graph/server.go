@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/auth"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/generated"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/loaders"
+)
+
+// NewServer serves the schema of resolver over websocket, GET, POST and multipart requests.
+// Websocket connections are authenticated by tokens, the other requests by the auth middleware.
+// The operations are refused beyond the depth and complexity limits of cfg, or outside of its allowlist.
+// Errors are presented with their codes by ErrorPresenter and every operation gets its own loaders.
+// It panics when a query of the allowlist does not match the schema.
+func NewServer(resolver *Resolver, tokens auth.TokenParser, cfg config.GraphQLConfig) *handler.Server {
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{
+		Resolvers:  resolver,
+		Directives: resolver.Directives(),
+		Complexity: Complexity(),
+	}))
+
+	srv.SetErrorPresenter(ErrorPresenter)
+	srv.Use(loaders.PerOperation{})
+
+	srv.AddTransport(transport.Websocket{
+		InitFunc:              auth.WebsocketInit(tokens),
+		KeepAlivePingInterval: cfg.WebsocketKeepAlive,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{
+		MaxUploadSize: cfg.UploadMaxSize,
+		MaxMemory:     cfg.UploadMaxMemory,
+	})
+
+	if cfg.QueryCacheSize > 0 {
+		srv.SetQueryCache(lru.New(cfg.QueryCacheSize))
+	}
+
+	if cfg.Introspection {
+		srv.Use(extension.Introspection{})
+	}
+	// Clients cannot register queries with the allowlist, the persisted queries are the ones of the allowlist
+	if cfg.Allowlist != nil {
+		srv.Use(Allowlist{Queries: cfg.Allowlist})
+	} else if cfg.APQ {
+		srv.Use(extension.AutomaticPersistedQuery{
+			Cache: lru.New(cfg.APQCacheSize),
+		})
+	}
+	if cfg.MaxDepth > 0 || cfg.MaxComplexity > 0 {
+		srv.Use(&QueryLimit{
+			MaxDepth:      cfg.MaxDepth,
+			MaxComplexity: cfg.MaxComplexity,
+		})
+	}
+	return srv
+}
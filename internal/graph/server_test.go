@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewServer(t *testing.T) {
+	// hash of "{ __typename }"
+	apqExtension := `{"persistedQuery":{"version":1,"sha256Hash":"7f56e67dd21ab3f30d1ff8b7bed08893f0a0db86449836189b361dd1e56ddb4b"}}`
+
+	tcs := map[string]struct {
+		configure func(cfg *config.GraphQLConfig)
+		method    string
+		params    url.Values
+		body      string
+		expData   string
+		expError  string
+	}{
+		"success with a POST request": {
+			method:  http.MethodPost,
+			body:    `{"query":"{ __typename }"}`,
+			expData: `{"__typename":"Query"}`,
+		},
+		"success with a GET request": {
+			method:  http.MethodGet,
+			params:  url.Values{"query": {"{ __typename }"}},
+			expData: `{"__typename":"Query"}`,
+		},
+		"success with introspection": {
+			method:  http.MethodPost,
+			body:    `{"query":"{ __schema { queryType { name } } }"}`,
+			expData: `{"__schema":{"queryType":{"name":"Query"}}}`,
+		},
+		"failed with introspection turned off": {
+			configure: func(cfg *config.GraphQLConfig) { cfg.Introspection = false },
+			method:    http.MethodPost,
+			body:      `{"query":"{ __schema { queryType { name } } }"}`,
+			expError:  "introspection disabled",
+		},
+		"unknown persisted query is asked for": {
+			method:   http.MethodGet,
+			params:   url.Values{"extensions": {apqExtension}},
+			expError: "PersistedQueryNotFound",
+		},
+		"persisted query is registered with its query": {
+			method:  http.MethodGet,
+			params:  url.Values{"query": {"{ __typename }"}, "extensions": {apqExtension}},
+			expData: `{"__typename":"Query"}`,
+		},
+		"failed with persisted queries turned off": {
+			configure: func(cfg *config.GraphQLConfig) { cfg.APQ = false },
+			method:    http.MethodGet,
+			params:    url.Values{"extensions": {apqExtension}},
+			expError:  "operation  not found",
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			cfg := config.DefaultGraphQLConfig()
+			if tc.configure != nil {
+				tc.configure(&cfg)
+			}
+			srv := NewServer(&Resolver{}, nil, cfg)
+
+			req := httptest.NewRequest(tc.method, "/query?"+tc.params.Encode(), strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			srv.ServeHTTP(rr, req)
+
+			resp := struct {
+				Data   json.RawMessage
+				Errors []struct{ Message string }
+			}{}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tc.expError != "" {
+				require.Len(t, resp.Errors, 1)
+				require.Equal(t, tc.expError, resp.Errors[0].Message)
+			} else {
+				require.Empty(t, resp.Errors)
+				require.JSONEq(t, tc.expData, string(resp.Data))
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"strconv"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/graphmodel"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+)
+
+// Events of the schema and their names in the payloads of the deliveries
+var webhookEvents = map[graphmodel.WebhookEvent]webhooks.Event{
+	graphmodel.WebhookEventFriendAdded:         webhooks.FriendAdded,
+	graphmodel.WebhookEventFriendRemoved:       webhooks.FriendRemoved,
+	graphmodel.WebhookEventSubscriptionCreated: webhooks.SubscriptionCreated,
+	graphmodel.WebhookEventSubscriptionRemoved: webhooks.SubscriptionRemoved,
+	graphmodel.WebhookEventBlockCreated:        webhooks.BlockCreated,
+	graphmodel.WebhookEventBlockRemoved:        webhooks.BlockRemoved,
+}
+
+// Statuses of the schema of the deliveries
+var webhookDeliveryStatuses = map[string]graphmodel.WebhookDeliveryStatus{
+	webhooks.StatusPending:   graphmodel.WebhookDeliveryStatusPending,
+	webhooks.StatusDelivered: graphmodel.WebhookDeliveryStatusDelivered,
+	webhooks.StatusDead:      graphmodel.WebhookDeliveryStatusDead,
+}
+
+// Event of the schema from the name of an event
+func newWebhookEvent(event webhooks.Event) graphmodel.WebhookEvent {
+	for schemaEvent, name := range webhookEvents {
+		if name == event {
+			return schemaEvent
+		}
+	}
+	return graphmodel.WebhookEvent(event)
+}
+
+// Webhook of the schema, the secret is left out
+func newWebhook(webhook webhooks.Webhook) *graphmodel.Webhook {
+	events := make([]graphmodel.WebhookEvent, len(webhook.Events))
+	for i, event := range webhook.Events {
+		events[i] = newWebhookEvent(event)
+	}
+	return &graphmodel.Webhook{
+		ID:        strconv.Itoa(webhook.ID),
+		URL:       webhook.URL,
+		Events:    events,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+// Delivery of the schema, the status and the error of the last attempt are null until they are known
+func newWebhookDelivery(delivery webhooks.DeliveryLog) *graphmodel.WebhookDelivery {
+	result := &graphmodel.WebhookDelivery{
+		ID:            strconv.Itoa(delivery.ID),
+		Event:         newWebhookEvent(delivery.Event),
+		Payload:       delivery.Payload,
+		Status:        webhookDeliveryStatuses[delivery.Status],
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: delivery.NextAttemptAt,
+		CreatedAt:     delivery.CreatedAt,
+		UpdatedAt:     delivery.UpdatedAt,
+	}
+	if delivery.ResponseStatus != 0 {
+		responseStatus := delivery.ResponseStatus
+		result.ResponseStatus = &responseStatus
+	}
+	if delivery.LastError != "" {
+		lastError := delivery.LastError
+		result.LastError = &lastError
+	}
+	return result
+}
+
+// Id of a webhook from its ID argument, an id which is not a number cannot be the one of a webhook
+func parseWebhookID(id string) (int, error) {
+	webhookId, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, errs.NewNotFoundError(errs.CodeWebhookNotFound, errs.MsgNotExistedWebhook)
+	}
+	return webhookId, nil
+}
@@ -0,0 +1,262 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/graphmodel"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutationResolver_CreateWebhook(t *testing.T) {
+	createdAt := time.Date(2022, 1, 23, 9, 0, 0, 0, time.UTC)
+	tcs := map[string]struct {
+		input      graphmodel.NewWebhook
+		mockEvents []webhooks.Event
+		mockResult webhooks.Webhook
+		mockErr    error
+		expResult  *graphmodel.Webhook
+		expError   error
+	}{
+		"success with an input": {
+			input: graphmodel.NewWebhook{
+				URL:    "https://crm.example.com/hooks",
+				Secret: "crm-secret-0123456789",
+				Events: []graphmodel.WebhookEvent{graphmodel.WebhookEventFriendAdded, graphmodel.WebhookEventBlockRemoved},
+			},
+			mockEvents: []webhooks.Event{webhooks.FriendAdded, webhooks.BlockRemoved},
+			mockResult: webhooks.Webhook{ID: 3, URL: "https://crm.example.com/hooks", Secret: "crm-secret-0123456789",
+				Events: []webhooks.Event{webhooks.FriendAdded, webhooks.BlockRemoved}, CreatedAt: createdAt},
+			expResult: &graphmodel.Webhook{
+				ID:        "3",
+				URL:       "https://crm.example.com/hooks",
+				Events:    []graphmodel.WebhookEvent{graphmodel.WebhookEventFriendAdded, graphmodel.WebhookEventBlockRemoved},
+				CreatedAt: createdAt,
+			},
+		},
+		"success without events": {
+			input: graphmodel.NewWebhook{
+				URL:    "http://analytics.internal:8080/events",
+				Secret: "analytics-secret-0123",
+			},
+			mockEvents: []webhooks.Event{},
+			mockResult: webhooks.Webhook{ID: 4, URL: "http://analytics.internal:8080/events", Events: []webhooks.Event{},
+				CreatedAt: createdAt},
+			expResult: &graphmodel.Webhook{
+				ID:        "4",
+				URL:       "http://analytics.internal:8080/events",
+				Events:    []graphmodel.WebhookEvent{},
+				CreatedAt: createdAt,
+			},
+		},
+		"failed with an input validation failure (url and secret invalid)": {
+			input: graphmodel.NewWebhook{
+				URL:    "crm.example.com/hooks",
+				Secret: "secret",
+			},
+			expError: errors.New("URL must be an absolute http or https URL; Secret must have from 16 to 256 characters"),
+		},
+		"failed with an unavailable storage": {
+			input: graphmodel.NewWebhook{
+				URL:    "https://crm.example.com/hooks",
+				Secret: "crm-secret-0123456789",
+			},
+			mockEvents: []webhooks.Event{},
+			mockErr:    errs.NewUnavailableError(errors.New("connection refused")),
+			expError:   errors.New(errs.MsgUnavailable),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("CreateWebhook", mock.Anything, testCase.input.URL, testCase.input.Secret, testCase.mockEvents).
+					Return(testCase.mockResult, testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := r.Mutation().CreateWebhook(ctx, testCase.input)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestMutationResolver_DeleteWebhook(t *testing.T) {
+	tcs := map[string]struct {
+		id        string
+		mockErr   error
+		expResult *graphmodel.IsSuccess
+		expError  error
+	}{
+		"success with an id": {
+			id:        "7",
+			expResult: &graphmodel.IsSuccess{Success: true},
+		},
+		"failed with an unknown webhook": {
+			id:       "7",
+			mockErr:  errs.NewNotFoundError(errs.CodeWebhookNotFound, errs.MsgNotExistedWebhook),
+			expError: errors.New(errs.MsgNotExistedWebhook),
+		},
+		"failed with an id which is not a number": {
+			id:       "crm",
+			expError: errors.New(errs.MsgNotExistedWebhook),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("DeleteWebhook", mock.Anything, 7).Return(testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := r.Mutation().DeleteWebhook(ctx, testCase.id)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
+
+func TestQueryResolver_Webhooks(t *testing.T) {
+	createdAt := time.Date(2022, 1, 23, 9, 0, 0, 0, time.UTC)
+	var mockService SpecService
+	mockService.ExpectedCalls = []*mock.Call{
+		mockService.On("GetWebhooks", mock.Anything).Return([]webhooks.Webhook{
+			{ID: 1, URL: "https://crm.example.com/hooks", Secret: "crm-secret-0123456789",
+				Events: []webhooks.Event{webhooks.SubscriptionCreated, webhooks.SubscriptionRemoved}, CreatedAt: createdAt},
+		}, nil),
+	}
+	r := Resolver{
+		Service: mockService,
+	}
+
+	result, err := r.Query().Webhooks(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []*graphmodel.Webhook{
+		{
+			ID:        "1",
+			URL:       "https://crm.example.com/hooks",
+			Events:    []graphmodel.WebhookEvent{graphmodel.WebhookEventSubscriptionCreated, graphmodel.WebhookEventSubscriptionRemoved},
+			CreatedAt: createdAt,
+		},
+	}, result)
+}
+
+func TestQueryResolver_WebhookDeliveries(t *testing.T) {
+	at := time.Date(2022, 1, 23, 9, 0, 0, 0, time.UTC)
+	responseStatus := 503
+	okStatus := 200
+	lastError := "webhook: endpoint answered 503 Service Unavailable"
+	refused := "connection refused"
+	first := 10
+	tooMany := 101
+
+	tcs := map[string]struct {
+		webhookID  string
+		first      *int
+		mockLimit  int
+		mockResult []webhooks.DeliveryLog
+		mockErr    error
+		expResult  []*graphmodel.WebhookDelivery
+		expError   error
+	}{
+		"success with the default size": {
+			webhookID: "7",
+			mockLimit: 50,
+			mockResult: []webhooks.DeliveryLog{
+				{ID: 2, WebhookID: 7, Event: webhooks.BlockCreated, Payload: `{"event":"block.created"}`, Status: webhooks.StatusPending,
+					Attempts: 1, ResponseStatus: 503, LastError: lastError, NextAttemptAt: at, CreatedAt: at, UpdatedAt: at},
+				{ID: 1, WebhookID: 7, Event: webhooks.FriendAdded, Payload: `{"event":"friend.added"}`, Status: webhooks.StatusDelivered,
+					Attempts: 1, ResponseStatus: 200, NextAttemptAt: at, CreatedAt: at, UpdatedAt: at},
+			},
+			expResult: []*graphmodel.WebhookDelivery{
+				{ID: "2", Event: graphmodel.WebhookEventBlockCreated, Payload: `{"event":"block.created"}`,
+					Status: graphmodel.WebhookDeliveryStatusPending, Attempts: 1, ResponseStatus: &responseStatus,
+					LastError: &lastError, NextAttemptAt: at, CreatedAt: at, UpdatedAt: at},
+				{ID: "1", Event: graphmodel.WebhookEventFriendAdded, Payload: `{"event":"friend.added"}`,
+					Status: graphmodel.WebhookDeliveryStatusDelivered, Attempts: 1, ResponseStatus: &okStatus,
+					NextAttemptAt: at, CreatedAt: at, UpdatedAt: at},
+			},
+		},
+		"success with a dead delivery which never got an answer": {
+			webhookID: "7",
+			first:     &first,
+			mockLimit: 10,
+			mockResult: []webhooks.DeliveryLog{
+				{ID: 3, WebhookID: 7, Event: webhooks.FriendRemoved, Payload: `{}`, Status: webhooks.StatusDead,
+					Attempts: 8, LastError: "connection refused", NextAttemptAt: at, CreatedAt: at, UpdatedAt: at},
+			},
+			expResult: []*graphmodel.WebhookDelivery{
+				{ID: "3", Event: graphmodel.WebhookEventFriendRemoved, Payload: `{}`, Status: graphmodel.WebhookDeliveryStatusDead,
+					Attempts: 8, LastError: &refused, NextAttemptAt: at, CreatedAt: at, UpdatedAt: at},
+			},
+		},
+		"failed with a size out of range": {
+			webhookID: "7",
+			first:     &tooMany,
+			expError:  errs.ErrPageSizeInvalid,
+		},
+		"failed with an unknown webhook": {
+			webhookID: "7",
+			mockLimit: 50,
+			mockErr:   errs.NewNotFoundError(errs.CodeWebhookNotFound, errs.MsgNotExistedWebhook),
+			expError:  errors.New(errs.MsgNotExistedWebhook),
+		},
+	}
+	for desc, testCase := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			//Given
+			ctx := context.Background()
+			var mockService SpecService
+			mockService.ExpectedCalls = []*mock.Call{
+				mockService.On("GetWebhookDeliveries", mock.Anything, 7, testCase.mockLimit).
+					Return(testCase.mockResult, testCase.mockErr),
+			}
+
+			r := Resolver{
+				Service: mockService,
+			}
+
+			//When
+			result, err := r.Query().WebhookDeliveries(ctx, testCase.webhookID, testCase.first)
+
+			//Then
+			if testCase.expError != nil {
+				require.EqualError(t, err, testCase.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expResult, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,380 @@
+package loaders
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
+	"github.com/graph-gophers/dataloader/v6"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// DefaultWait is how long a loader collects the keys of a batch before querying them
+const DefaultWait = 2 * time.Millisecond
+
+// MaxBatch is the largest number of keys of a single query, more keys are split in several batches
+const MaxBatch = 100
+
+// contextKey is the type of keys stored by this package, it prevents collisions with other packages
+type contextKey struct {
+	name string
+}
+
+var loadersCtxKey = &contextKey{"loaders"}
+
+// BatchRepo is the repository queries the loaders batch their keys into
+type BatchRepo interface {
+	GetUsersByEmails(ctx context.Context, emails []string) (models.UserSlice, error)
+	GetUsersByIDs(ctx context.Context, userIds []int) (models.UserSlice, error)
+	GetFriendsByIDs(ctx context.Context, userIds []int) (models.FriendSlice, error)
+	GetUserBlocksByIDs(ctx context.Context, userIds []int) (models.UserBlockSlice, error)
+	GetFriendsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error)
+	GetSubscribersPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error)
+	GetSubscriptionsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error)
+	GetBlockedUsersPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error)
+}
+
+// Loaders batch and cache the lookups of users and relationships within one request.
+// The lookups of a key are queried once, unknown users are cached as a nil user.
+type Loaders struct {
+	repo               BatchRepo
+	wait               time.Duration
+	usersByEmail       *dataloader.Loader
+	usersByID          *dataloader.Loader
+	friendsByUserID    *dataloader.Loader
+	userBlocksByUserID *dataloader.Loader
+	relationPages      *dataloader.Loader
+}
+
+// idKey is the key of a lookup by id
+type idKey int
+
+func (k idKey) String() string { return strconv.Itoa(int(k)) }
+
+func (k idKey) Raw() interface{} { return int(k) }
+
+// Relation names a relationship whose pages of related users are batched
+type Relation string
+
+const (
+	Friends       Relation = "friends"
+	Subscribers   Relation = "subscribers"
+	Subscriptions Relation = "subscriptions"
+	BlockedUsers  Relation = "blockedUsers"
+)
+
+// relationPageKey is the key of the page of a relationship of a user
+type relationPageKey struct {
+	relation Relation
+	userId   int
+	page     pagination.Page
+}
+
+func (k relationPageKey) String() string {
+	return fmt.Sprintf("%s:%d:%d:%t:%q:%q", k.relation, k.userId, k.page.Size, k.page.Backward, k.page.After, k.page.Before)
+}
+
+func (k relationPageKey) Raw() interface{} { return k }
+
+// NewLoaders creates the loaders of a request, a loader waits for wait after its first key to query a batch
+func NewLoaders(repo BatchRepo, wait time.Duration) *Loaders {
+	opts := []dataloader.Option{dataloader.WithWait(wait), dataloader.WithBatchCapacity(MaxBatch)}
+	return &Loaders{
+		repo:               repo,
+		wait:               wait,
+		usersByEmail:       dataloader.NewBatchedLoader(batchUsersByEmail(repo), opts...),
+		usersByID:          dataloader.NewBatchedLoader(batchUsersByID(repo), opts...),
+		friendsByUserID:    dataloader.NewBatchedLoader(batchFriendsByUserID(repo), opts...),
+		userBlocksByUserID: dataloader.NewBatchedLoader(batchUserBlocksByUserID(repo), opts...),
+		relationPages:      dataloader.NewBatchedLoader(batchRelationPages(repo), opts...),
+	}
+}
+
+// Middleware puts new loaders into the context of every request, so caches never outlive a request.
+// The GraphQL operations of a websocket connection share its request, PerOperation renews their loaders.
+func Middleware(repo BatchRepo) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := WithLoaders(r.Context(), NewLoaders(repo, DefaultWait))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// PerOperation is a GraphQL extension giving every query and mutation new loaders of the repository of the request.
+// Subscriptions run without loaders: their events are resolved long after they started, a cache would show
+// the relationships of that time.
+type PerOperation struct{}
+
+func (PerOperation) ExtensionName() string {
+	return "Loaders"
+}
+
+func (PerOperation) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (PerOperation) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	loaders := For(ctx)
+	if loaders == nil {
+		return next(ctx)
+	}
+
+	operation := graphql.GetOperationContext(ctx).Operation
+	if operation != nil && operation.Operation == ast.Subscription {
+		return next(WithLoaders(ctx, nil))
+	}
+	return next(WithLoaders(ctx, NewLoaders(loaders.repo, loaders.wait)))
+}
+
+// WithLoaders returns a copy of ctx carrying the loaders of a request
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersCtxKey, loaders)
+}
+
+// For finds the loaders of the request, it is nil outside of the middleware
+func For(ctx context.Context) *Loaders {
+	loaders, _ := ctx.Value(loadersCtxKey).(*Loaders)
+	return loaders
+}
+
+// UserByEmail loads a user by email, the user is nil when the email is unknown
+func (_self *Loaders) UserByEmail(ctx context.Context, email string) (*models.User, error) {
+	value, err := _self.usersByEmail.Load(ctx, dataloader.StringKey(email))()
+	if err != nil {
+		return nil, err
+	}
+	return value.(*models.User), nil
+}
+
+// UsersByEmails loads the users of a list of emails in its order, unknown emails are left out
+func (_self *Loaders) UsersByEmails(ctx context.Context, emails []string) (models.UserSlice, error) {
+	values, errors := _self.usersByEmail.LoadMany(ctx, dataloader.NewKeysFromStrings(emails))()
+	return usersOf(values, errors)
+}
+
+// UsersByIDs loads the users of a list of ids in its order, unknown ids are left out
+func (_self *Loaders) UsersByIDs(ctx context.Context, userIds []int) (models.UserSlice, error) {
+	keys := make(dataloader.Keys, len(userIds))
+	for i, userId := range userIds {
+		keys[i] = idKey(userId)
+	}
+	values, errors := _self.usersByID.LoadMany(ctx, keys)()
+	return usersOf(values, errors)
+}
+
+// FriendsByUserID loads the friendships of a user in both directions
+func (_self *Loaders) FriendsByUserID(ctx context.Context, userId int) (models.FriendSlice, error) {
+	value, err := _self.friendsByUserID.Load(ctx, idKey(userId))()
+	if err != nil {
+		return nil, err
+	}
+	return value.(models.FriendSlice), nil
+}
+
+// UserBlocksByUserID loads the blocks of a user in both directions
+func (_self *Loaders) UserBlocksByUserID(ctx context.Context, userId int) (models.UserBlockSlice, error) {
+	value, err := _self.userBlocksByUserID.Load(ctx, idKey(userId))()
+	if err != nil {
+		return nil, err
+	}
+	return value.(models.UserBlockSlice), nil
+}
+
+// RelationPages loads the pages of a relationship of a list of users, the pages of all the users
+// and relationships loaded together are queried once per relationship and page
+func (_self *Loaders) RelationPages(ctx context.Context, relation Relation, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	keys := make(dataloader.Keys, len(userIds))
+	for i, userId := range userIds {
+		keys[i] = relationPageKey{relation: relation, userId: userId, page: page}
+	}
+	values, errors := _self.relationPages.LoadMany(ctx, keys)()
+	for _, err := range errors {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pages := make(map[int]repository.RelationPage, len(userIds))
+	for i, value := range values {
+		pages[userIds[i]] = value.(repository.RelationPage)
+	}
+	return pages, nil
+}
+
+// ClearAll empties the caches, the next lookups query the repository again
+func (_self *Loaders) ClearAll() {
+	_self.usersByEmail.ClearAll()
+	_self.usersByID.ClearAll()
+	_self.friendsByUserID.ClearAll()
+	_self.userBlocksByUserID.ClearAll()
+	_self.relationPages.ClearAll()
+}
+
+// usersOf keeps the known users of loaded values, the first error fails the whole list
+func usersOf(values []interface{}, errors []error) (models.UserSlice, error) {
+	for _, err := range errors {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	users := make(models.UserSlice, 0, len(values))
+	for _, value := range values {
+		if user := value.(*models.User); user != nil {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// failedResults gives the error of a failed query to every key of its batch
+func failedResults(keys dataloader.Keys, err error) []*dataloader.Result {
+	results := make([]*dataloader.Result, len(keys))
+	for i := range keys {
+		results[i] = &dataloader.Result{Error: err}
+	}
+	return results
+}
+
+// idsOf returns the ids of the keys of a batch
+func idsOf(keys dataloader.Keys) []int {
+	ids := make([]int, len(keys))
+	for i, key := range keys {
+		ids[i] = key.Raw().(int)
+	}
+	return ids
+}
+
+func batchUsersByEmail(repo BatchRepo) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		users, err := repo.GetUsersByEmails(ctx, keys.Keys())
+		if err != nil {
+			return failedResults(keys, err)
+		}
+
+		byEmail := make(map[string]*models.User, len(users))
+		for _, user := range users {
+			byEmail[user.Email] = user
+		}
+		results := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result{Data: byEmail[key.String()]}
+		}
+		return results
+	}
+}
+
+func batchUsersByID(repo BatchRepo) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		users, err := repo.GetUsersByIDs(ctx, idsOf(keys))
+		if err != nil {
+			return failedResults(keys, err)
+		}
+
+		byID := make(map[int]*models.User, len(users))
+		for _, user := range users {
+			byID[user.ID] = user
+		}
+		results := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result{Data: byID[key.Raw().(int)]}
+		}
+		return results
+	}
+}
+
+func batchFriendsByUserID(repo BatchRepo) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		friends, err := repo.GetFriendsByIDs(ctx, idsOf(keys))
+		if err != nil {
+			return failedResults(keys, err)
+		}
+
+		byUserID := make(map[int]models.FriendSlice, len(keys))
+		for _, friend := range friends {
+			byUserID[friend.UserID] = append(byUserID[friend.UserID], friend)
+			if friend.FriendID != friend.UserID {
+				byUserID[friend.FriendID] = append(byUserID[friend.FriendID], friend)
+			}
+		}
+		results := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result{Data: byUserID[key.Raw().(int)]}
+		}
+		return results
+	}
+}
+
+func batchUserBlocksByUserID(repo BatchRepo) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		blocks, err := repo.GetUserBlocksByIDs(ctx, idsOf(keys))
+		if err != nil {
+			return failedResults(keys, err)
+		}
+
+		byUserID := make(map[int]models.UserBlockSlice, len(keys))
+		for _, block := range blocks {
+			byUserID[block.RequestorID] = append(byUserID[block.RequestorID], block)
+			if block.TargetID != block.RequestorID {
+				byUserID[block.TargetID] = append(byUserID[block.TargetID], block)
+			}
+		}
+		results := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result{Data: byUserID[key.Raw().(int)]}
+		}
+		return results
+	}
+}
+
+func batchRelationPages(repo BatchRepo) dataloader.BatchFunc {
+	getPages := map[Relation]func(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error){
+		Friends:       repo.GetFriendsPages,
+		Subscribers:   repo.GetSubscribersPages,
+		Subscriptions: repo.GetSubscriptionsPages,
+		BlockedUsers:  repo.GetBlockedUsersPages,
+	}
+
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		// Group the keys of a batch by relationship and page, each group is one query
+		type group struct {
+			relation Relation
+			page     pagination.Page
+		}
+		indexes := map[group][]int{}
+		var groups []group
+		for i, key := range keys {
+			k := key.Raw().(relationPageKey)
+			g := group{relation: k.relation, page: k.page}
+			if _, ok := indexes[g]; !ok {
+				groups = append(groups, g)
+			}
+			indexes[g] = append(indexes[g], i)
+		}
+
+		results := make([]*dataloader.Result, len(keys))
+		for _, g := range groups {
+			userIds := make([]int, len(indexes[g]))
+			for j, i := range indexes[g] {
+				userIds[j] = keys[i].Raw().(relationPageKey).userId
+			}
+
+			pages, err := getPages[g.relation](ctx, userIds, g.page)
+			for j, i := range indexes[g] {
+				if err != nil {
+					results[i] = &dataloader.Result{Error: err}
+				} else {
+					results[i] = &dataloader.Result{Data: pages[userIds[j]]}
+				}
+			}
+		}
+		return results
+	}
+}
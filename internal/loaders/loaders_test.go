@@ -0,0 +1,290 @@
+package loaders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// batchRepo records the keys of every query, the friendship 100-102 and the block 100-103 are the only relationships
+type batchRepo struct {
+	repository.SpecRepo
+	err error
+
+	mu      sync.Mutex
+	batches map[string][][]string
+}
+
+func newBatchRepo(err error) *batchRepo {
+	return &batchRepo{err: err, batches: map[string][][]string{}}
+}
+
+func (r *batchRepo) record(method string, keys []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sorted := append([]string{}, keys...)
+	sort.Strings(sorted)
+	r.batches[method] = append(r.batches[method], sorted)
+}
+
+func (r *batchRepo) GetUsersByEmails(ctx context.Context, emails []string) (models.UserSlice, error) {
+	r.record("GetUsersByEmails", emails)
+	users := models.UserSlice{}
+	for _, email := range emails {
+		switch email {
+		case "andy@example.com":
+			users = append(users, &models.User{ID: 101, Email: email})
+		case "john@example.com":
+			users = append(users, &models.User{ID: 100, Email: email})
+		}
+	}
+	return users, r.err
+}
+
+func (r *batchRepo) GetUsersByIDs(ctx context.Context, userIds []int) (models.UserSlice, error) {
+	keys := make([]string, len(userIds))
+	users := models.UserSlice{}
+	for i, userId := range userIds {
+		keys[i] = idKey(userId).String()
+		if userId == 100 {
+			users = append(users, &models.User{ID: userId, Email: "john@example.com"})
+		}
+	}
+	r.record("GetUsersByIDs", keys)
+	return users, r.err
+}
+
+func (r *batchRepo) GetFriendsByIDs(ctx context.Context, userIds []int) (models.FriendSlice, error) {
+	keys := make([]string, len(userIds))
+	for i, userId := range userIds {
+		keys[i] = idKey(userId).String()
+	}
+	r.record("GetFriendsByIDs", keys)
+	return models.FriendSlice{{UserID: 100, FriendID: 102}}, r.err
+}
+
+func (r *batchRepo) GetUserBlocksByIDs(ctx context.Context, userIds []int) (models.UserBlockSlice, error) {
+	keys := make([]string, len(userIds))
+	for i, userId := range userIds {
+		keys[i] = idKey(userId).String()
+	}
+	r.record("GetUserBlocksByIDs", keys)
+	return models.UserBlockSlice{{RequestorID: 100, TargetID: 103}}, r.err
+}
+
+// relationPages records the keys of a page query by the size of its page, every user has the page of john
+func (r *batchRepo) relationPages(method string, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	keys := make([]string, len(userIds))
+	pages := make(map[int]repository.RelationPage, len(userIds))
+	for i, userId := range userIds {
+		keys[i] = idKey(userId).String()
+		pages[userId] = repository.RelationPage{Emails: []string{"john@example.com"}, TotalCount: 1}
+	}
+	r.record(fmt.Sprintf("%s(%d)", method, page.Size), keys)
+	return pages, r.err
+}
+
+func (r *batchRepo) GetFriendsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	return r.relationPages("GetFriendsPages", userIds, page)
+}
+
+func (r *batchRepo) GetSubscribersPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	return r.relationPages("GetSubscribersPages", userIds, page)
+}
+
+func (r *batchRepo) CreateSubscription(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) error {
+	return nil
+}
+
+func (r *batchRepo) AcceptFriendRequest(ctx context.Context, requestorId int, targetId int, notifications []notify.Message, change webhooks.Payload) error {
+	return nil
+}
+
+// loadConcurrently runs the lookups at the same time like the resolvers of a list
+func loadConcurrently(lookups ...func()) {
+	var wg sync.WaitGroup
+	wg.Add(len(lookups))
+	for _, lookup := range lookups {
+		go func(lookup func()) {
+			defer wg.Done()
+			lookup()
+		}(lookup)
+	}
+	wg.Wait()
+}
+
+func TestLoaders_Batching(t *testing.T) {
+	repo := newBatchRepo(nil)
+	ctx := WithLoaders(context.Background(), NewLoaders(repo, 20*time.Millisecond))
+	r := NewRepo(repo)
+
+	var johnId, andyId int
+	var johnFriends models.FriendSlice
+	var lisaFriends models.FriendSlice
+	var lisaBlocks models.UserBlockSlice
+	var unknownErr error
+	loadConcurrently(
+		func() { johnId, _ = r.GetUserIDByEmail(ctx, "john@example.com") },
+		func() { andyId, _ = r.GetUserIDByEmail(ctx, "andy@example.com") },
+		func() { _, unknownErr = r.GetUserByEmail(ctx, "test@example.com") },
+		func() { johnFriends, _ = r.GetFriendsByID(ctx, 100) },
+		func() { lisaFriends, _ = r.GetFriendsByID(ctx, 103) },
+		func() { lisaBlocks, _ = r.GetUserBlocksByID(ctx, 103) },
+	)
+
+	require.Equal(t, 100, johnId)
+	require.Equal(t, 101, andyId)
+	require.Equal(t, sql.ErrNoRows, unknownErr)
+	require.Equal(t, models.FriendSlice{{UserID: 100, FriendID: 102}}, johnFriends)
+	require.Empty(t, lisaFriends)
+	require.Equal(t, models.UserBlockSlice{{RequestorID: 100, TargetID: 103}}, lisaBlocks)
+
+	// cached keys are not queried again, even with other keys of a later batch
+	users, err := r.GetUsersByEmails(ctx, []string{"andy@example.com", "kate@example.com", "john@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, []int{101, 100}, []int{users[0].ID, users[1].ID})
+	emails, err := r.GetEmailsByUserIDs(ctx, []int{100, 104})
+	require.NoError(t, err)
+	require.Equal(t, []string{"john@example.com"}, emails)
+
+	require.Equal(t, map[string][][]string{
+		"GetUsersByEmails": {
+			{"andy@example.com", "john@example.com", "test@example.com"},
+			{"kate@example.com"},
+		},
+		"GetFriendsByIDs":    {{"100", "103"}},
+		"GetUserBlocksByIDs": {{"103"}},
+		"GetUsersByIDs":      {{"100", "104"}},
+	}, repo.batches)
+}
+
+func TestLoaders_RelationPages(t *testing.T) {
+	repo := newBatchRepo(nil)
+	ctx := WithLoaders(context.Background(), NewLoaders(repo, 20*time.Millisecond))
+	r := NewRepo(repo)
+
+	var andyFriends, lisaFriends map[int]repository.RelationPage
+	loadConcurrently(
+		func() { andyFriends, _ = r.GetFriendsPages(ctx, []int{101}, pagination.Page{Size: 1}) },
+		func() { lisaFriends, _ = r.GetFriendsPages(ctx, []int{103}, pagination.Page{Size: 1}) },
+		func() { _, _ = r.GetFriendsPages(ctx, []int{101}, pagination.Page{Size: 2}) },
+		func() { _, _ = r.GetSubscribersPages(ctx, []int{101}, pagination.Page{Size: 1}) },
+	)
+
+	johnPage := repository.RelationPage{Emails: []string{"john@example.com"}, TotalCount: 1}
+	require.Equal(t, map[int]repository.RelationPage{101: johnPage}, andyFriends)
+	require.Equal(t, map[int]repository.RelationPage{103: johnPage}, lisaFriends)
+
+	// cached pages are not queried again
+	pages, err := r.GetFriendsPages(ctx, []int{103, 101}, pagination.Page{Size: 1})
+	require.NoError(t, err)
+	require.Equal(t, map[int]repository.RelationPage{101: johnPage, 103: johnPage}, pages)
+
+	// a query per relationship and page of a batch
+	require.Equal(t, map[string][][]string{
+		"GetFriendsPages(1)":     {{"101", "103"}},
+		"GetFriendsPages(2)":     {{"101"}},
+		"GetSubscribersPages(1)": {{"101"}},
+	}, repo.batches)
+}
+
+func TestLoaders_ClearOnWrite(t *testing.T) {
+	repo := newBatchRepo(nil)
+	ctx := WithLoaders(context.Background(), NewLoaders(repo, time.Millisecond))
+	r := NewRepo(repo)
+
+	_, err := r.GetFriendsByID(ctx, 100)
+	require.NoError(t, err)
+	require.NoError(t, r.AcceptFriendRequest(ctx, 100, 101, nil, webhooks.Payload{}))
+	_, err = r.GetFriendsByID(ctx, 100)
+	require.NoError(t, err)
+
+	_, err = r.GetSubscribersPages(ctx, []int{101}, pagination.Page{Size: 1})
+	require.NoError(t, err)
+	require.NoError(t, r.CreateSubscription(ctx, 100, 101, webhooks.Payload{}))
+	_, err = r.GetSubscribersPages(ctx, []int{101}, pagination.Page{Size: 1})
+	require.NoError(t, err)
+
+	require.Equal(t, map[string][][]string{
+		"GetFriendsByIDs":        {{"100"}, {"100"}},
+		"GetSubscribersPages(1)": {{"101"}, {"101"}},
+	}, repo.batches)
+}
+
+func TestLoaders_FailedBatch(t *testing.T) {
+	repo := newBatchRepo(errors.New("connection refused"))
+	loaders := NewLoaders(repo, time.Millisecond)
+	ctx := context.Background()
+
+	_, err := loaders.UsersByEmails(ctx, []string{"andy@example.com", "john@example.com"})
+	require.Equal(t, errors.New("connection refused"), err)
+	_, err = loaders.FriendsByUserID(ctx, 100)
+	require.Equal(t, errors.New("connection refused"), err)
+	_, err = loaders.RelationPages(ctx, Friends, []int{100, 101}, pagination.Page{Size: 1})
+	require.Equal(t, errors.New("connection refused"), err)
+}
+
+func TestLoaders_PerOperation(t *testing.T) {
+	tcs := map[string]struct {
+		operation   ast.Operation
+		withLoaders bool
+		expLoaders  bool
+	}{
+		"new loaders for a query": {
+			operation:   ast.Query,
+			withLoaders: true,
+			expLoaders:  true,
+		},
+		"new loaders for a mutation": {
+			operation:   ast.Mutation,
+			withLoaders: true,
+			expLoaders:  true,
+		},
+		"no loaders for a subscription": {
+			operation:   ast.Subscription,
+			withLoaders: true,
+		},
+		"no loaders outside of the middleware": {
+			operation: ast.Query,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			requestLoaders := NewLoaders(newBatchRepo(nil), time.Millisecond)
+			ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{
+				Operation: &ast.OperationDefinition{Operation: tc.operation},
+			})
+			if tc.withLoaders {
+				ctx = WithLoaders(ctx, requestLoaders)
+			}
+
+			var operationLoaders *Loaders
+			PerOperation{}.InterceptOperation(ctx, func(ctx context.Context) graphql.ResponseHandler {
+				operationLoaders = For(ctx)
+				return nil
+			})
+
+			if tc.expLoaders {
+				require.NotNil(t, operationLoaders)
+				require.NotSame(t, requestLoaders, operationLoaders)
+			} else {
+				require.Nil(t, operationLoaders)
+			}
+		})
+	}
+}
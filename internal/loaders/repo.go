@@ -0,0 +1,199 @@
+package loaders
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+)
+
+// Repo serves the lookups of users and relationships from the loaders of the request context,
+// other methods and requests without loaders go straight to the wrapped repository.
+// Writes changing users or relationships clear the loaders so later lookups see them.
+type Repo struct {
+	repository.SpecRepo
+}
+
+func NewRepo(repo repository.SpecRepo) Repo {
+	return Repo{SpecRepo: repo}
+}
+
+// Get a user id by email, an unknown email fails with sql.ErrNoRows like the repository
+func (_self Repo) GetUserIDByEmail(ctx context.Context, email string) (int, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return _self.SpecRepo.GetUserIDByEmail(ctx, email)
+	}
+
+	user, err := loaders.UserByEmail(ctx, email)
+	if err != nil {
+		return 0, err
+	}
+	if user == nil {
+		return 0, sql.ErrNoRows
+	}
+	return user.ID, nil
+}
+
+// Get a user by email, an unknown email fails with sql.ErrNoRows like the repository
+func (_self Repo) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return _self.SpecRepo.GetUserByEmail(ctx, email)
+	}
+
+	user, err := loaders.UserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+// Get users by emails, unknown emails are left out
+func (_self Repo) GetUsersByEmails(ctx context.Context, emails []string) (models.UserSlice, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return _self.SpecRepo.GetUsersByEmails(ctx, emails)
+	}
+	return loaders.UsersByEmails(ctx, emails)
+}
+
+// Get users by ids, unknown ids are left out
+func (_self Repo) GetUsersByIDs(ctx context.Context, userIds []int) (models.UserSlice, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return _self.SpecRepo.GetUsersByIDs(ctx, userIds)
+	}
+	return loaders.UsersByIDs(ctx, userIds)
+}
+
+// Get the emails of a list of user ids, unknown ids are left out
+func (_self Repo) GetEmailsByUserIDs(ctx context.Context, userIDs []int) ([]string, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return _self.SpecRepo.GetEmailsByUserIDs(ctx, userIDs)
+	}
+
+	users, err := loaders.UsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	emails := make([]string, len(users))
+	for i, user := range users {
+		emails[i] = user.Email
+	}
+	return emails, nil
+}
+
+// Get the friendships of a user in both directions
+func (_self Repo) GetFriendsByID(ctx context.Context, userId int) (models.FriendSlice, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return _self.SpecRepo.GetFriendsByID(ctx, userId)
+	}
+	return loaders.FriendsByUserID(ctx, userId)
+}
+
+// Get the blocks of a user in both directions
+func (_self Repo) GetUserBlocksByID(ctx context.Context, userId int) (models.UserBlockSlice, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return _self.SpecRepo.GetUserBlocksByID(ctx, userId)
+	}
+	return loaders.UserBlocksByUserID(ctx, userId)
+}
+
+// Get the pages of the friends of a list of users with their counts
+func (_self Repo) GetFriendsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return _self.SpecRepo.GetFriendsPages(ctx, userIds, page)
+	}
+	return loaders.RelationPages(ctx, Friends, userIds, page)
+}
+
+// Get the pages of the subscribers of a list of users with their counts
+func (_self Repo) GetSubscribersPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return _self.SpecRepo.GetSubscribersPages(ctx, userIds, page)
+	}
+	return loaders.RelationPages(ctx, Subscribers, userIds, page)
+}
+
+// Get the pages of the users a list of users subscribe to with their counts
+func (_self Repo) GetSubscriptionsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return _self.SpecRepo.GetSubscriptionsPages(ctx, userIds, page)
+	}
+	return loaders.RelationPages(ctx, Subscriptions, userIds, page)
+}
+
+// Get the pages of the users blocked by a list of users with their counts
+func (_self Repo) GetBlockedUsersPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return _self.SpecRepo.GetBlockedUsersPages(ctx, userIds, page)
+	}
+	return loaders.RelationPages(ctx, BlockedUsers, userIds, page)
+}
+
+func (_self Repo) DeleteFriend(ctx context.Context, userId int, friendId int, change webhooks.Payload) error {
+	defer clearLoaders(ctx)
+	return _self.SpecRepo.DeleteFriend(ctx, userId, friendId, change)
+}
+
+func (_self Repo) AcceptFriendRequest(ctx context.Context, requestorId int, targetId int, notifications []notify.Message, change webhooks.Payload) error {
+	defer clearLoaders(ctx)
+	return _self.SpecRepo.AcceptFriendRequest(ctx, requestorId, targetId, notifications, change)
+}
+
+func (_self Repo) CreateSubscription(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) error {
+	defer clearLoaders(ctx)
+	return _self.SpecRepo.CreateSubscription(ctx, requestorId, targetId, change)
+}
+
+func (_self Repo) DeleteSubscription(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) (int64, error) {
+	defer clearLoaders(ctx)
+	return _self.SpecRepo.DeleteSubscription(ctx, requestorId, targetId, change)
+}
+
+func (_self Repo) CreateUserBlock(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) error {
+	defer clearLoaders(ctx)
+	return _self.SpecRepo.CreateUserBlock(ctx, requestorId, targetId, change)
+}
+
+func (_self Repo) DeleteUserBlock(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) (int64, error) {
+	defer clearLoaders(ctx)
+	return _self.SpecRepo.DeleteUserBlock(ctx, requestorId, targetId, change)
+}
+
+func (_self Repo) CreateUser(ctx context.Context, name string, email string, password string) (int, error) {
+	defer clearLoaders(ctx)
+	return _self.SpecRepo.CreateUser(ctx, name, email, password)
+}
+
+func (_self Repo) UpdateUser(ctx context.Context, userId int, name string, email string) error {
+	defer clearLoaders(ctx)
+	return _self.SpecRepo.UpdateUser(ctx, userId, name, email)
+}
+
+func (_self Repo) DeleteUser(ctx context.Context, userId int) error {
+	defer clearLoaders(ctx)
+	return _self.SpecRepo.DeleteUser(ctx, userId)
+}
+
+// clearLoaders empties the caches of the request after a write
+func clearLoaders(ctx context.Context) {
+	if loaders := For(ctx); loaders != nil {
+		loaders.ClearAll()
+	}
+}
@@ -0,0 +1,34 @@
+package mentions
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/utils"
+)
+
+// Characters separating the words of a text besides the spaces, e.g. "andy@example.com,john@example.com"
+const separators = `,;"'()[]{}<>`
+
+// Punctuation around a mention which is not part of the email, e.g. "@andy@example.com!" or "john@example.com."
+const punctuation = `.:!?@`
+
+// Parse finds the emails mentioned in a text in their canonical form, in the order of the text and without duplicates.
+// A mention is a whole word of the text which is a single email address once the punctuation around it is trimmed.
+func Parse(text string) []string {
+	emails := make([]string, 0)
+	mentioned := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(text, isSeparator) {
+		email, err := utils.ParseEmail(strings.Trim(word, punctuation))
+		if err != nil || mentioned[email] {
+			continue
+		}
+		mentioned[email] = true
+		emails = append(emails, email)
+	}
+	return emails
+}
+
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(separators, r)
+}
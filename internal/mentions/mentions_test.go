@@ -0,0 +1,53 @@
+package mentions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentions_Parse(t *testing.T) {
+	tcs := map[string]struct {
+		text      string
+		expResult []string
+	}{
+		"text without mentions": {
+			text:      "hello world",
+			expResult: []string{},
+		},
+		"mentions in the order of the text": {
+			text:      "hello kate@example.com and andy@example.com",
+			expResult: []string{"kate@example.com", "andy@example.com"},
+		},
+		"trailing punctuation": {
+			text:      "hello kate@example.com. Are you there, andy@example.com?",
+			expResult: []string{"kate@example.com", "andy@example.com"},
+		},
+		"mentions in brackets and lists": {
+			text:      "cc: (kate@example.com), <andy@example.com>;john@example.com",
+			expResult: []string{"kate@example.com", "andy@example.com", "john@example.com"},
+		},
+		"at sign before a mention": {
+			text:      "hi @kate@example.com!",
+			expResult: []string{"kate@example.com"},
+		},
+		"duplicates in their canonical form": {
+			text:      "kate@example.com kate@Example.COM kate@example.com",
+			expResult: []string{"kate@example.com"},
+		},
+		"words which are not emails": {
+			text:      "kate@example mailto:andy@example.com a@b@example.com @ .",
+			expResult: []string{},
+		},
+		"internationalized domain": {
+			text:      "hi kate@bücher.de",
+			expResult: []string{"kate@xn--bcher-kva.de"},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			require.Equal(t, tc.expResult, Parse(tc.text))
+		})
+	}
+}
@@ -4,12 +4,14 @@
 package models
 
 var TableNames = struct {
+	FriendRequests   string
 	Friends          string
 	SchemaMigrations string
 	Subscriptions    string
 	UserBlocks       string
 	Users            string
 }{
+	FriendRequests:   "friend_requests",
 	Friends:          "friends",
 	SchemaMigrations: "schema_migrations",
 	Subscriptions:    "subscriptions",
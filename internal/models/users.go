@@ -28,6 +28,8 @@ type User struct {
 	Email     string    `boil:"email" json:"email" toml:"email" yaml:"email"`
 	CreatedAt time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
 	UpdatedAt time.Time `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	Password  string    `boil:"password" json:"password" toml:"password" yaml:"password"`
+	Role      string    `boil:"role" json:"role" toml:"role" yaml:"role"`
 
 	R *userR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L userL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -39,12 +41,16 @@ var UserColumns = struct {
 	Email     string
 	CreatedAt string
 	UpdatedAt string
+	Password  string
+	Role      string
 }{
 	ID:        "id",
 	Name:      "name",
 	Email:     "email",
 	CreatedAt: "created_at",
 	UpdatedAt: "updated_at",
+	Password:  "password",
+	Role:      "role",
 }
 
 var UserTableColumns = struct {
@@ -53,12 +59,16 @@ var UserTableColumns = struct {
 	Email     string
 	CreatedAt string
 	UpdatedAt string
+	Password  string
+	Role      string
 }{
 	ID:        "users.id",
 	Name:      "users.name",
 	Email:     "users.email",
 	CreatedAt: "users.created_at",
 	UpdatedAt: "users.updated_at",
+	Password:  "users.password",
+	Role:      "users.role",
 }
 
 // Generated where
@@ -113,16 +123,22 @@ var UserWhere = struct {
 	Email     whereHelperstring
 	CreatedAt whereHelpertime_Time
 	UpdatedAt whereHelpertime_Time
+	Password  whereHelperstring
+	Role      whereHelperstring
 }{
 	ID:        whereHelperint{field: "\"users\".\"id\""},
 	Name:      whereHelperstring{field: "\"users\".\"name\""},
 	Email:     whereHelperstring{field: "\"users\".\"email\""},
 	CreatedAt: whereHelpertime_Time{field: "\"users\".\"created_at\""},
 	UpdatedAt: whereHelpertime_Time{field: "\"users\".\"updated_at\""},
+	Password:  whereHelperstring{field: "\"users\".\"password\""},
+	Role:      whereHelperstring{field: "\"users\".\"role\""},
 }
 
 // UserRels is where relationship names are stored.
 var UserRels = struct {
+	RequestorFriendRequests            string
+	TargetFriendRequests               string
 	FriendFriends                      string
 	Friends                            string
 	SubscriptionRequestorSubscriptions string
@@ -130,6 +146,8 @@ var UserRels = struct {
 	RequestorUserBlocks                string
 	TargetUserBlocks                   string
 }{
+	RequestorFriendRequests:            "RequestorFriendRequests",
+	TargetFriendRequests:               "TargetFriendRequests",
 	FriendFriends:                      "FriendFriends",
 	Friends:                            "Friends",
 	SubscriptionRequestorSubscriptions: "SubscriptionRequestorSubscriptions",
@@ -140,12 +158,14 @@ var UserRels = struct {
 
 // userR is where relationships are stored.
 type userR struct {
-	FriendFriends                      FriendSlice       `boil:"FriendFriends" json:"FriendFriends" toml:"FriendFriends" yaml:"FriendFriends"`
-	Friends                            FriendSlice       `boil:"Friends" json:"Friends" toml:"Friends" yaml:"Friends"`
-	SubscriptionRequestorSubscriptions SubscriptionSlice `boil:"SubscriptionRequestorSubscriptions" json:"SubscriptionRequestorSubscriptions" toml:"SubscriptionRequestorSubscriptions" yaml:"SubscriptionRequestorSubscriptions"`
-	SubscriptionTargetSubscriptions    SubscriptionSlice `boil:"SubscriptionTargetSubscriptions" json:"SubscriptionTargetSubscriptions" toml:"SubscriptionTargetSubscriptions" yaml:"SubscriptionTargetSubscriptions"`
-	RequestorUserBlocks                UserBlockSlice    `boil:"RequestorUserBlocks" json:"RequestorUserBlocks" toml:"RequestorUserBlocks" yaml:"RequestorUserBlocks"`
-	TargetUserBlocks                   UserBlockSlice    `boil:"TargetUserBlocks" json:"TargetUserBlocks" toml:"TargetUserBlocks" yaml:"TargetUserBlocks"`
+	RequestorFriendRequests            FriendRequestSlice `boil:"RequestorFriendRequests" json:"RequestorFriendRequests" toml:"RequestorFriendRequests" yaml:"RequestorFriendRequests"`
+	TargetFriendRequests               FriendRequestSlice `boil:"TargetFriendRequests" json:"TargetFriendRequests" toml:"TargetFriendRequests" yaml:"TargetFriendRequests"`
+	FriendFriends                      FriendSlice        `boil:"FriendFriends" json:"FriendFriends" toml:"FriendFriends" yaml:"FriendFriends"`
+	Friends                            FriendSlice        `boil:"Friends" json:"Friends" toml:"Friends" yaml:"Friends"`
+	SubscriptionRequestorSubscriptions SubscriptionSlice  `boil:"SubscriptionRequestorSubscriptions" json:"SubscriptionRequestorSubscriptions" toml:"SubscriptionRequestorSubscriptions" yaml:"SubscriptionRequestorSubscriptions"`
+	SubscriptionTargetSubscriptions    SubscriptionSlice  `boil:"SubscriptionTargetSubscriptions" json:"SubscriptionTargetSubscriptions" toml:"SubscriptionTargetSubscriptions" yaml:"SubscriptionTargetSubscriptions"`
+	RequestorUserBlocks                UserBlockSlice     `boil:"RequestorUserBlocks" json:"RequestorUserBlocks" toml:"RequestorUserBlocks" yaml:"RequestorUserBlocks"`
+	TargetUserBlocks                   UserBlockSlice     `boil:"TargetUserBlocks" json:"TargetUserBlocks" toml:"TargetUserBlocks" yaml:"TargetUserBlocks"`
 }
 
 // NewStruct creates a new relationship struct
@@ -157,9 +177,9 @@ func (*userR) NewStruct() *userR {
 type userL struct{}
 
 var (
-	userAllColumns            = []string{"id", "name", "email", "created_at", "updated_at"}
+	userAllColumns            = []string{"id", "name", "email", "created_at", "updated_at", "password", "role"}
 	userColumnsWithoutDefault = []string{"name", "email", "created_at", "updated_at"}
-	userColumnsWithDefault    = []string{"id"}
+	userColumnsWithDefault    = []string{"id", "password", "role"}
 	userPrimaryKeyColumns     = []string{"id"}
 )
 
@@ -438,6 +458,48 @@ func (q userQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool,
 	return count > 0, nil
 }
 
+// RequestorFriendRequests retrieves all the friend_request's FriendRequests with an executor via requestor_id column.
+func (o *User) RequestorFriendRequests(mods ...qm.QueryMod) friendRequestQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"friend_requests\".\"requestor_id\"=?", o.ID),
+	)
+
+	query := FriendRequests(queryMods...)
+	queries.SetFrom(query.Query, "\"friend_requests\"")
+
+	if len(queries.GetSelect(query.Query)) == 0 {
+		queries.SetSelect(query.Query, []string{"\"friend_requests\".*"})
+	}
+
+	return query
+}
+
+// TargetFriendRequests retrieves all the friend_request's FriendRequests with an executor via target_id column.
+func (o *User) TargetFriendRequests(mods ...qm.QueryMod) friendRequestQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"friend_requests\".\"target_id\"=?", o.ID),
+	)
+
+	query := FriendRequests(queryMods...)
+	queries.SetFrom(query.Query, "\"friend_requests\"")
+
+	if len(queries.GetSelect(query.Query)) == 0 {
+		queries.SetSelect(query.Query, []string{"\"friend_requests\".*"})
+	}
+
+	return query
+}
+
 // FriendFriends retrieves all the friend's Friends with an executor via friend_id column.
 func (o *User) FriendFriends(mods ...qm.QueryMod) friendQuery {
 	var queryMods []qm.QueryMod
@@ -564,6 +626,202 @@ func (o *User) TargetUserBlocks(mods ...qm.QueryMod) userBlockQuery {
 	return query
 }
 
+// LoadRequestorFriendRequests allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (userL) LoadRequestorFriendRequests(ctx context.Context, e boil.ContextExecutor, singular bool, maybeUser interface{}, mods queries.Applicator) error {
+	var slice []*User
+	var object *User
+
+	if singular {
+		object = maybeUser.(*User)
+	} else {
+		slice = *maybeUser.(*[]*User)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &userR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &userR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`friend_requests`),
+		qm.WhereIn(`friend_requests.requestor_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load friend_requests")
+	}
+
+	var resultSlice []*FriendRequest
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice friend_requests")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on friend_requests")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for friend_requests")
+	}
+
+	if len(friendRequestAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.RequestorFriendRequests = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &friendRequestR{}
+			}
+			foreign.R.Requestor = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.RequestorID {
+				local.R.RequestorFriendRequests = append(local.R.RequestorFriendRequests, foreign)
+				if foreign.R == nil {
+					foreign.R = &friendRequestR{}
+				}
+				foreign.R.Requestor = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadTargetFriendRequests allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (userL) LoadTargetFriendRequests(ctx context.Context, e boil.ContextExecutor, singular bool, maybeUser interface{}, mods queries.Applicator) error {
+	var slice []*User
+	var object *User
+
+	if singular {
+		object = maybeUser.(*User)
+	} else {
+		slice = *maybeUser.(*[]*User)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &userR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &userR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`friend_requests`),
+		qm.WhereIn(`friend_requests.target_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load friend_requests")
+	}
+
+	var resultSlice []*FriendRequest
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice friend_requests")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on friend_requests")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for friend_requests")
+	}
+
+	if len(friendRequestAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.TargetFriendRequests = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &friendRequestR{}
+			}
+			foreign.R.Target = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.TargetID {
+				local.R.TargetFriendRequests = append(local.R.TargetFriendRequests, foreign)
+				if foreign.R == nil {
+					foreign.R = &friendRequestR{}
+				}
+				foreign.R.Target = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // LoadFriendFriends allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for a 1-M or N-M relationship.
 func (userL) LoadFriendFriends(ctx context.Context, e boil.ContextExecutor, singular bool, maybeUser interface{}, mods queries.Applicator) error {
@@ -1152,6 +1410,112 @@ func (userL) LoadTargetUserBlocks(ctx context.Context, e boil.ContextExecutor, s
 	return nil
 }
 
+// AddRequestorFriendRequests adds the given related objects to the existing relationships
+// of the user, optionally inserting them as new records.
+// Appends related to o.R.RequestorFriendRequests.
+// Sets related.R.Requestor appropriately.
+func (o *User) AddRequestorFriendRequests(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*FriendRequest) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.RequestorID = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"friend_requests\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"requestor_id"}),
+				strmangle.WhereClause("\"", "\"", 2, friendRequestPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.RequestorID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &userR{
+			RequestorFriendRequests: related,
+		}
+	} else {
+		o.R.RequestorFriendRequests = append(o.R.RequestorFriendRequests, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &friendRequestR{
+				Requestor: o,
+			}
+		} else {
+			rel.R.Requestor = o
+		}
+	}
+	return nil
+}
+
+// AddTargetFriendRequests adds the given related objects to the existing relationships
+// of the user, optionally inserting them as new records.
+// Appends related to o.R.TargetFriendRequests.
+// Sets related.R.Target appropriately.
+func (o *User) AddTargetFriendRequests(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*FriendRequest) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.TargetID = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"friend_requests\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"target_id"}),
+				strmangle.WhereClause("\"", "\"", 2, friendRequestPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.TargetID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &userR{
+			TargetFriendRequests: related,
+		}
+	} else {
+		o.R.TargetFriendRequests = append(o.R.TargetFriendRequests, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &friendRequestR{
+				Target: o,
+			}
+		} else {
+			rel.R.Target = o
+		}
+	}
+	return nil
+}
+
 // AddFriendFriends adds the given related objects to the existing relationships
 // of the user, optionally inserting them as new records.
 // Appends related to o.R.FriendFriends.
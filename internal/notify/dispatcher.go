@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Store is the outbox of the notifications, written in the transactions of the changes they are about
+type Store interface {
+	// ClaimNotifications returns up to limit pending notifications due at now and counts one more attempt for each,
+	// they are not claimed again before now + lease so a crashed dispatcher only delays them
+	ClaimNotifications(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Notification, error)
+	MarkNotificationSent(ctx context.Context, id int) error
+	// RetryNotification keeps a notification pending until nextAttemptAt
+	RetryNotification(ctx context.Context, id int, nextAttemptAt time.Time, lastError string) error
+	// DeadLetterNotification stops the attempts of a notification, it stays in the outbox with its last error
+	DeadLetterNotification(ctx context.Context, id int, lastError string) error
+}
+
+// Backoff is the delay before the next attempt of a notification, it doubles from Base after each failed attempt
+// and never exceeds Max
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay is the wait after the failed attempt number attempts, counted from 1
+func (_self Backoff) Delay(attempts int) time.Duration {
+	delay := _self.Base
+	for i := 1; i < attempts && delay < _self.Max; i++ {
+		delay *= 2
+	}
+	if delay > _self.Max {
+		return _self.Max
+	}
+	return delay
+}
+
+// Dispatcher delivers the notifications of the outbox through a notifier. A notification is delivered at least
+// once: it is sent again when the dispatcher stops between the delivery and its record.
+type Dispatcher struct {
+	Store       Store
+	Notifier    Notifier
+	Backoff     Backoff
+	MaxAttempts int
+	BatchSize   int
+	Interval    time.Duration
+	// Time allowed to a notifier for a notification, the claim of the notification lasts twice as long
+	Timeout time.Duration
+	// Now is the clock of the dispatcher, nil is time.Now
+	Now func() time.Time
+}
+
+func NewDispatcher(store Store, notifier Notifier, cfg Config) Dispatcher {
+	return Dispatcher{
+		Store:       store,
+		Notifier:    notifier,
+		Backoff:     Backoff{Base: cfg.BackoffBase, Max: cfg.BackoffMax},
+		MaxAttempts: cfg.MaxAttempts,
+		BatchSize:   cfg.BatchSize,
+		Interval:    cfg.PollInterval,
+		Timeout:     defaultSMTPTimeout,
+	}
+}
+
+// Run polls the outbox every interval until ctx is done
+func (_self Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(_self.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := _self.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("notify: dispatch failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch delivers the due notifications until none is left and returns the number of notifications sent.
+// A failed notification is retried after its backoff, or dead once it reached the maximum of attempts.
+func (_self Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		notifications, err := _self.Store.ClaimNotifications(ctx, _self.now(), 2*_self.Timeout, _self.BatchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, notification := range notifications {
+			ok, err := _self.deliver(ctx, notification)
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+		}
+		if len(notifications) == 0 || len(notifications) < _self.BatchSize {
+			return sent, nil
+		}
+	}
+}
+
+// deliver sends a notification and records the result, ok tells whether it was sent
+func (_self Dispatcher) deliver(ctx context.Context, notification Notification) (ok bool, err error) {
+	notifyCtx, cancel := context.WithTimeout(ctx, _self.Timeout)
+	notifyErr := _self.Notifier.Notify(notifyCtx, notification.Message)
+	cancel()
+
+	switch {
+	case notifyErr == nil:
+		return true, _self.Store.MarkNotificationSent(ctx, notification.ID)
+	case notification.Attempts >= _self.MaxAttempts:
+		log.Printf("notify: notification %d to %s is dead after %d attempts: %v",
+			notification.ID, notification.Recipient, notification.Attempts, notifyErr)
+		return false, _self.Store.DeadLetterNotification(ctx, notification.ID, notifyErr.Error())
+	default:
+		nextAttemptAt := _self.now().Add(_self.Backoff.Delay(notification.Attempts))
+		return false, _self.Store.RetryNotification(ctx, notification.ID, nextAttemptAt, notifyErr.Error())
+	}
+}
+
+func (_self Dispatcher) now() time.Time {
+	if _self.Now != nil {
+		return _self.Now()
+	}
+	return time.Now()
+}
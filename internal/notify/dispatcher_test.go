@@ -0,0 +1,175 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an outbox in memory, a notification is due when it is pending and its next attempt has come
+type memoryStore struct {
+	notifications map[int]*storedNotification
+	claimErr      error
+}
+
+type storedNotification struct {
+	Notification
+	status        string
+	nextAttemptAt time.Time
+	lastError     string
+}
+
+func newMemoryStore(messages ...Message) *memoryStore {
+	store := &memoryStore{notifications: map[int]*storedNotification{}}
+	for i, message := range messages {
+		store.notifications[i+1] = &storedNotification{
+			Notification: Notification{ID: i + 1, Message: message},
+			status:       "pending",
+		}
+	}
+	return store
+}
+
+func (s *memoryStore) ClaimNotifications(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Notification, error) {
+	if s.claimErr != nil {
+		return nil, s.claimErr
+	}
+	claimed := []Notification{}
+	for id := 1; id <= len(s.notifications) && len(claimed) < limit; id++ {
+		notification := s.notifications[id]
+		if notification.status != "pending" || notification.nextAttemptAt.After(now) {
+			continue
+		}
+		notification.Attempts++
+		notification.nextAttemptAt = now.Add(lease)
+		claimed = append(claimed, notification.Notification)
+	}
+	return claimed, nil
+}
+
+func (s *memoryStore) MarkNotificationSent(ctx context.Context, id int) error {
+	s.notifications[id].status = "sent"
+	return nil
+}
+
+func (s *memoryStore) RetryNotification(ctx context.Context, id int, nextAttemptAt time.Time, lastError string) error {
+	s.notifications[id].nextAttemptAt = nextAttemptAt
+	s.notifications[id].lastError = lastError
+	return nil
+}
+
+func (s *memoryStore) DeadLetterNotification(ctx context.Context, id int, lastError string) error {
+	s.notifications[id].status = "dead"
+	s.notifications[id].lastError = lastError
+	return nil
+}
+
+// failingNotifier fails the recipients of failures, the others are recorded as sent
+type failingNotifier struct {
+	failures map[string]error
+	sent     *[]string
+}
+
+func (n failingNotifier) Notify(ctx context.Context, message Message) error {
+	if err := n.failures[message.Recipient]; err != nil {
+		return err
+	}
+	*n.sent = append(*n.sent, message.Recipient)
+	return nil
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	backoff := Backoff{Base: 30 * time.Second, Max: 10 * time.Minute}
+
+	require.Equal(t, 30*time.Second, backoff.Delay(1))
+	require.Equal(t, time.Minute, backoff.Delay(2))
+	require.Equal(t, 8*time.Minute, backoff.Delay(5))
+	require.Equal(t, 10*time.Minute, backoff.Delay(6))
+	require.Equal(t, 10*time.Minute, backoff.Delay(100))
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	now := time.Date(2022, 1, 16, 9, 0, 0, 0, time.UTC)
+	store := newMemoryStore(
+		NewFriendAdded("john@example.com", "andy@example.com"),
+		NewFriendAdded("andy@example.com", "john@example.com"),
+		NewFriendAdded("kate@example.com", "lisa@example.com"),
+	)
+	sent := []string{}
+	notifier := failingNotifier{
+		failures: map[string]error{"andy@example.com": errors.New("421 service not available")},
+		sent:     &sent,
+	}
+	dispatcher := NewDispatcher(store, notifier, Config{
+		BatchSize:   2,
+		MaxAttempts: 3,
+		BackoffBase: time.Minute,
+		BackoffMax:  time.Hour,
+	})
+	dispatcher.Now = func() time.Time { return now }
+
+	// Every batch is dispatched, the failed notification waits for its backoff
+	count, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, []string{"john@example.com", "kate@example.com"}, sent)
+	require.Equal(t, "sent", store.notifications[1].status)
+	require.Equal(t, "pending", store.notifications[2].status)
+	require.Equal(t, now.Add(time.Minute), store.notifications[2].nextAttemptAt)
+	require.Equal(t, "421 service not available", store.notifications[2].lastError)
+
+	count, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+	require.Equal(t, 1, store.notifications[2].Attempts)
+
+	// The delay doubles after each attempt until the last one
+	now = now.Add(time.Minute)
+	_, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, store.notifications[2].Attempts)
+	require.Equal(t, now.Add(2*time.Minute), store.notifications[2].nextAttemptAt)
+
+	now = now.Add(2 * time.Minute)
+	_, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, store.notifications[2].Attempts)
+	require.Equal(t, "dead", store.notifications[2].status)
+	require.Equal(t, "421 service not available", store.notifications[2].lastError)
+
+	// A dead notification is never attempted again
+	now = now.Add(24 * time.Hour)
+	count, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+	require.Equal(t, 3, store.notifications[2].Attempts)
+}
+
+func TestDispatcher_DispatchFailedClaim(t *testing.T) {
+	store := newMemoryStore(NewFriendAdded("john@example.com", "andy@example.com"))
+	store.claimErr = errors.New("connection refused")
+	sent := []string{}
+	dispatcher := NewDispatcher(store, failingNotifier{sent: &sent}, DefaultConfig())
+
+	count, err := dispatcher.Dispatch(context.Background())
+	require.EqualError(t, err, "connection refused")
+	require.Equal(t, 0, count)
+	require.Empty(t, sent)
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	store := newMemoryStore(NewFriendAdded("john@example.com", "andy@example.com"))
+	sent := []string{}
+	cfg := DefaultConfig()
+	cfg.PollInterval = time.Millisecond
+	dispatcher := NewDispatcher(store, failingNotifier{sent: &sent}, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// The outbox is dispatched once before the end of ctx is noticed
+	dispatcher.Run(ctx)
+	require.Equal(t, []string{"john@example.com"}, sent)
+}
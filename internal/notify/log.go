@@ -0,0 +1,24 @@
+package notify
+
+import (
+	"context"
+	"log"
+)
+
+// LogNotifier writes the messages to a logger, it is meant for development
+type LogNotifier struct {
+	Logger *log.Logger
+}
+
+// NewLogNotifier writes to logger, nil uses the standard logger
+func NewLogNotifier(logger *log.Logger) LogNotifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return LogNotifier{Logger: logger}
+}
+
+func (_self LogNotifier) Notify(ctx context.Context, message Message) error {
+	_self.Logger.Printf("notification %s to %s: %s: %q", message.Kind, message.Recipient, message.Subject, message.Body)
+	return nil
+}
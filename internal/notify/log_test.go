@@ -0,0 +1,19 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogNotifier_Notify(t *testing.T) {
+	var output bytes.Buffer
+	notifier := NewLogNotifier(log.New(&output, "", 0))
+
+	require.NoError(t, notifier.Notify(context.Background(), NewFriendAdded("john@example.com", "andy@example.com")))
+	require.Equal(t, "notification friend_added to john@example.com: andy@example.com is now your friend: "+
+		"\"You and andy@example.com are now friends.\"\n", output.String())
+}
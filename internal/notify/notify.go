@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Kind tells what a notification is about
+type Kind string
+
+const (
+	UpdatePublished Kind = "update_published"
+	FriendAdded     Kind = "friend_added"
+)
+
+// Message is a notification for the user of Recipient, an email address
+type Message struct {
+	Kind      Kind
+	Recipient string
+	Subject   string
+	Body      string
+}
+
+// Notification is a message of the outbox, Attempts counts the deliveries tried so far including the current one
+type Notification struct {
+	ID       int
+	Attempts int
+	Message
+}
+
+// Notifier tells a user about a message, an error means the message has to be sent again later
+type Notifier interface {
+	Notify(ctx context.Context, message Message) error
+}
+
+// Config selects the notifier and the retries of the dispatcher
+type Config struct {
+	// Address of the SMTP server like "smtp.example.com:587", empty sends the notifications to the log
+	SMTPAddr     string
+	SMTPFrom     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// Interval between two polls of the outbox and number of notifications claimed by a poll
+	PollInterval time.Duration
+	BatchSize    int
+	// A notification failing MaxAttempts times is dead, the delay before the next attempt doubles from
+	// BackoffBase to BackoffMax
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+// DefaultConfig sends the notifications to the log, a notification is tried 8 times within about an hour
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: 5 * time.Second,
+		BatchSize:    50,
+		MaxAttempts:  8,
+		BackoffBase:  30 * time.Second,
+		BackoffMax:   30 * time.Minute,
+	}
+}
+
+// NewNotifier is the SMTP notifier of the config, or the log notifier without SMTP server
+func NewNotifier(cfg Config) Notifier {
+	if cfg.SMTPAddr == "" {
+		return NewLogNotifier(nil)
+	}
+	return NewSMTPNotifier(cfg.SMTPAddr, cfg.SMTPFrom, cfg.SMTPUsername, cfg.SMTPPassword)
+}
+
+// NewUpdatePublished is the message of an update of sender, the recipient is filled for each user receiving it
+func NewUpdatePublished(senderEmail string, text string) Message {
+	return Message{
+		Kind:    UpdatePublished,
+		Subject: fmt.Sprintf("New update from %s", senderEmail),
+		Body:    text,
+	}
+}
+
+// NewFriendAdded is the message telling a user about its new friend
+func NewFriendAdded(email string, friendEmail string) Message {
+	return Message{
+		Kind:      FriendAdded,
+		Recipient: email,
+		Subject:   fmt.Sprintf("%s is now your friend", friendEmail),
+		Body:      fmt.Sprintf("You and %s are now friends.", friendEmail),
+	}
+}
@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Time allowed to a whole SMTP session when the context has no deadline
+const defaultSMTPTimeout = 30 * time.Second
+
+// SMTPNotifier sends the messages as plain text emails. The connection is upgraded with STARTTLS when the server
+// offers it, the credentials are only sent over TLS or to a local server.
+type SMTPNotifier struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+func NewSMTPNotifier(addr string, from string, username string, password string) SMTPNotifier {
+	return SMTPNotifier{
+		Addr:     addr,
+		From:     from,
+		Username: username,
+		Password: password,
+	}
+}
+
+func (_self SMTPNotifier) Notify(ctx context.Context, message Message) error {
+	host, _, err := net.SplitHostPort(_self.Addr)
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", _self.Addr)
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultSMTPTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+	defer client.Close()
+
+	if err := _self.send(client, host, message); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return client.Quit()
+}
+
+func (_self SMTPNotifier) send(client *smtp.Client, host string, message Message) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if _self.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", _self.Username, _self.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(_self.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(message.Recipient); err != nil {
+		return err
+	}
+	data, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := data.Write(_self.content(message)); err != nil {
+		data.Close()
+		return err
+	}
+	return data.Close()
+}
+
+// content is the email of a message, the subject is encoded when it is not plain ASCII
+func (_self SMTPNotifier) content(message Message) []byte {
+	var content strings.Builder
+	fmt.Fprintf(&content, "From: %s\r\n", _self.From)
+	fmt.Fprintf(&content, "To: %s\r\n", message.Recipient)
+	fmt.Fprintf(&content, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&content, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	content.WriteString("MIME-Version: 1.0\r\n")
+	content.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	content.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	content.WriteString("\r\n")
+	content.WriteString(strings.ReplaceAll(message.Body, "\r\n", "\n"))
+	content.WriteString("\n")
+	return []byte(content.String())
+}
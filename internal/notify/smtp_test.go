@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer is a local SMTP server keeping the emails it receives, it answers rcptReply to RCPT
+type fakeSMTPServer struct {
+	listener  net.Listener
+	rcptReply string
+
+	mu     sync.Mutex
+	emails []fakeEmail
+}
+
+type fakeEmail struct {
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T, rcptReply string) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeSMTPServer{listener: listener, rcptReply: rcptReply}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeSMTPServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeSMTPServer) Emails() []fakeEmail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]fakeEmail(nil), s.emails...)
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.session(conn)
+	}
+}
+
+// session speaks just enough SMTP for net/smtp: no extension is offered
+func (s *fakeSMTPServer) session(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+
+	reply("220 localhost fake SMTP")
+	var email fakeEmail
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			email = fakeEmail{from: strings.Trim(strings.TrimSpace(line)[len("MAIL FROM:"):], "<>")}
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			if s.rcptReply != "" {
+				reply(s.rcptReply)
+				continue
+			}
+			email.to = append(email.to, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			email.data = data.String()
+			s.mu.Lock()
+			s.emails = append(s.emails, email)
+			s.mu.Unlock()
+			reply("250 OK")
+		case command == "RSET", command == "NOOP":
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	server := newFakeSMTPServer(t, "")
+	notifier := NewSMTPNotifier(server.Addr(), "noreply@example.com", "", "")
+	message := NewUpdatePublished("lisa@example.com", "Hello World!\n.hidden line")
+	message.Recipient = "kate@example.com"
+
+	require.NoError(t, notifier.Notify(context.Background(), message))
+
+	emails := server.Emails()
+	require.Len(t, emails, 1)
+	require.Equal(t, "noreply@example.com", emails[0].from)
+	require.Equal(t, []string{"kate@example.com"}, emails[0].to)
+	require.Contains(t, emails[0].data, "From: noreply@example.com\r\n")
+	require.Contains(t, emails[0].data, "To: kate@example.com\r\n")
+	require.Contains(t, emails[0].data, "Subject: New update from lisa@example.com\r\n")
+	require.Contains(t, emails[0].data, "Content-Type: text/plain; charset=UTF-8\r\n")
+	// The body keeps its lines, a leading dot is escaped on the wire
+	require.True(t, strings.HasSuffix(emails[0].data, "\r\n\r\nHello World!\r\n..hidden line\r\n"))
+}
+
+func TestSMTPNotifier_Errors(t *testing.T) {
+	tcs := map[string]struct {
+		addr     func(t *testing.T) string
+		expError string
+	}{
+		"recipient refused by the server": {
+			addr: func(t *testing.T) string {
+				return newFakeSMTPServer(t, "550 mailbox unavailable").Addr()
+			},
+			expError: "smtp: 550",
+		},
+		"server not listening": {
+			addr: func(t *testing.T) string {
+				listener, err := net.Listen("tcp", "127.0.0.1:0")
+				require.NoError(t, err)
+				listener.Close()
+				return listener.Addr().String()
+			},
+			expError: "connection refused",
+		},
+		"address without port": {
+			addr: func(t *testing.T) string {
+				return "localhost"
+			},
+			expError: "smtp: address localhost: missing port in address",
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			notifier := NewSMTPNotifier(tc.addr(t), "noreply@example.com", "", "")
+
+			err := notifier.Notify(ctx, NewFriendAdded("kate@example.com", "lisa@example.com"))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expError)
+		})
+	}
+}
+
+func TestSMTPNotifier_EncodedSubject(t *testing.T) {
+	notifier := NewSMTPNotifier("localhost:25", "noreply@example.com", "", "")
+	content := string(notifier.content(Message{
+		Recipient: "kate@example.com",
+		Subject:   "Bonjour José\r\nBcc: eve@example.com",
+		Body:      "hello",
+	}))
+
+	require.Contains(t, content, "Subject: =?utf-8?q?Bonjour_Jos=C3=A9=0D=0ABcc:_eve@example.com?=\r\n")
+	require.NotContains(t, content, "\r\nBcc:")
+}
@@ -0,0 +1,147 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"sort"
+	"strings"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+)
+
+// Size limits of a page
+const (
+	DefaultSize = 50
+	MaxSize     = 100
+)
+
+// Prefix of a decoded cursor, it keeps the cursors opaque to the clients
+const cursorPrefix = "email:"
+
+// Page selects a window of a list of emails ordered byte-wise.
+// After and Before are exclusive bounds, the window is taken from the end of the bounds when Backward is set.
+type Page struct {
+	Size     int
+	After    string
+	Before   string
+	Backward bool
+}
+
+// Result is a window of a list of emails, TotalCount is the length of the whole list
+type Result struct {
+	Emails          []string
+	HasNextPage     bool
+	HasPreviousPage bool
+	TotalCount      int
+}
+
+// New validates the arguments of a connection and decodes its cursors, a page without size has DefaultSize emails
+func New(first *int, after *string, last *int, before *string) (Page, error) {
+	if first != nil && last != nil {
+		return Page{}, errs.ErrPageDirectionInvalid
+	}
+
+	page := Page{Size: DefaultSize}
+	if first != nil {
+		page.Size = *first
+	}
+	if last != nil {
+		page.Size = *last
+		page.Backward = true
+	}
+	if page.Size < 0 || page.Size > MaxSize {
+		return Page{}, errs.ErrPageSizeInvalid
+	}
+
+	var err error
+	if after != nil {
+		if page.After, err = DecodeCursor(*after); err != nil {
+			return Page{}, err
+		}
+	}
+	if before != nil {
+		if page.Before, err = DecodeCursor(*before); err != nil {
+			return Page{}, err
+		}
+	}
+	return page, nil
+}
+
+// EncodeCursor returns the cursor of an email
+func EncodeCursor(email string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + email))
+}
+
+// DecodeCursor returns the email of a cursor
+func DecodeCursor(cursor string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return "", errs.ErrCursorInvalid
+	}
+	email := strings.TrimPrefix(string(decoded), cursorPrefix)
+	if email == "" {
+		return "", errs.ErrCursorInvalid
+	}
+	return email, nil
+}
+
+// Window selects the emails of the page from an unordered list the way the repository does it:
+// deduplicated, within the bounds, in the order of the page and at most Size+1 of them
+func Window(page Page, emails []string) []string {
+	seen := make(map[string]bool, len(emails))
+	window := make([]string, 0, len(emails))
+	for _, email := range emails {
+		if seen[email] || (page.After != "" && email <= page.After) || (page.Before != "" && email >= page.Before) {
+			continue
+		}
+		seen[email] = true
+		window = append(window, email)
+	}
+
+	sort.Slice(window, func(i, j int) bool {
+		if page.Backward {
+			return window[i] > window[j]
+		}
+		return window[i] < window[j]
+	})
+	if len(window) > page.Size+1 {
+		window = window[:page.Size+1]
+	}
+	return window
+}
+
+// NewResult builds the result of a page from the window fetched for it.
+// The window is in the order of the page and has one extra email when the list goes on.
+func NewResult(page Page, window []string, totalCount int) Result {
+	hasMore := len(window) > page.Size
+	emails := make([]string, 0, page.Size)
+	for i := 0; i < len(window) && i < page.Size; i++ {
+		emails = append(emails, window[i])
+	}
+
+	if !page.Backward {
+		return Result{
+			Emails:          emails,
+			HasNextPage:     hasMore,
+			HasPreviousPage: page.After != "",
+			TotalCount:      totalCount,
+		}
+	}
+
+	for i, j := 0, len(emails)-1; i < j; i, j = i+1, j-1 {
+		emails[i], emails[j] = emails[j], emails[i]
+	}
+	return Result{
+		Emails:          emails,
+		HasNextPage:     page.Before != "",
+		HasPreviousPage: hasMore,
+		TotalCount:      totalCount,
+	}
+}
+
+// Full returns a whole list as a single page
+func Full(emails []string) Result {
+	return Result{
+		Emails:     emails,
+		TotalCount: len(emails),
+	}
+}
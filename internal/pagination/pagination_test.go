@@ -0,0 +1,140 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestPagination_New(t *testing.T) {
+	tcs := map[string]struct {
+		first    *int
+		after    *string
+		last     *int
+		before   *string
+		expPage  Page
+		expError error
+	}{
+		"success without arguments": {
+			expPage: Page{Size: DefaultSize},
+		},
+		"success with first and after": {
+			first:   intPtr(2),
+			after:   stringPtr(EncodeCursor("andy@example.com")),
+			expPage: Page{Size: 2, After: "andy@example.com"},
+		},
+		"success with last and before": {
+			last:    intPtr(3),
+			before:  stringPtr(EncodeCursor("kate@example.com")),
+			expPage: Page{Size: 3, Before: "kate@example.com", Backward: true},
+		},
+		"failed with first and last": {
+			first:    intPtr(2),
+			last:     intPtr(2),
+			expError: errs.ErrPageDirectionInvalid,
+		},
+		"failed with a negative size": {
+			first:    intPtr(-1),
+			expError: errs.ErrPageSizeInvalid,
+		},
+		"failed with a size over the maximum": {
+			last:     intPtr(MaxSize + 1),
+			expError: errs.ErrPageSizeInvalid,
+		},
+		"failed with a cursor which is not base64": {
+			after:    stringPtr("andy@example.com"),
+			expError: errs.ErrCursorInvalid,
+		},
+		"failed with a cursor without prefix": {
+			before:   stringPtr("YW5keUBleGFtcGxlLmNvbQ"),
+			expError: errs.ErrCursorInvalid,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			page, err := New(tc.first, tc.after, tc.last, tc.before)
+			if tc.expError != nil {
+				require.Equal(t, tc.expError, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expPage, page)
+			}
+		})
+	}
+}
+
+func TestPagination_WindowAndNewResult(t *testing.T) {
+	emails := []string{"kate@example.com", "andy@example.com", "lisa@example.com", "common@example.com", "john@example.com", "andy@example.com"}
+
+	tcs := map[string]struct {
+		page      Page
+		expResult Result
+	}{
+		"first page": {
+			page: Page{Size: 2},
+			expResult: Result{
+				Emails:      []string{"andy@example.com", "common@example.com"},
+				HasNextPage: true,
+				TotalCount:  5,
+			},
+		},
+		"page after a cursor": {
+			page: Page{Size: 2, After: "common@example.com"},
+			expResult: Result{
+				Emails:          []string{"john@example.com", "kate@example.com"},
+				HasNextPage:     true,
+				HasPreviousPage: true,
+				TotalCount:      5,
+			},
+		},
+		"last page after a cursor": {
+			page: Page{Size: 2, After: "john@example.com"},
+			expResult: Result{
+				Emails:          []string{"kate@example.com", "lisa@example.com"},
+				HasPreviousPage: true,
+				TotalCount:      5,
+			},
+		},
+		"last emails": {
+			page: Page{Size: 2, Backward: true},
+			expResult: Result{
+				Emails:          []string{"kate@example.com", "lisa@example.com"},
+				HasPreviousPage: true,
+				TotalCount:      5,
+			},
+		},
+		"last emails before a cursor": {
+			page: Page{Size: 3, Before: "kate@example.com", Backward: true},
+			expResult: Result{
+				Emails:      []string{"andy@example.com", "common@example.com", "john@example.com"},
+				HasNextPage: true,
+				TotalCount:  5,
+			},
+		},
+		"empty page": {
+			page: Page{Size: 0},
+			expResult: Result{
+				Emails:      []string{},
+				HasNextPage: true,
+				TotalCount:  5,
+			},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			result := NewResult(tc.page, Window(tc.page, emails), 5)
+			require.Equal(t, tc.expResult, result)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+// Status values of a record in friend_requests table
+const (
+	FriendRequestPending   = "pending"
+	FriendRequestAccepted  = "accepted"
+	FriendRequestDeclined  = "declined"
+	FriendRequestCancelled = "cancelled"
+)
+
+// Insert a new pending record into friend_requests table
+func (_self DBRepo) CreateFriendRequest(ctx context.Context, requestorId int, targetId int) error {
+	friendRequest := models.FriendRequest{
+		RequestorID: requestorId,
+		TargetID:    targetId,
+		Status:      FriendRequestPending,
+	}
+	return friendRequest.Insert(ctx, _self.Db, boil.Infer())
+}
+
+// Verify a pending friend request from requestor to target
+func (_self DBRepo) IsPendingFriendRequest(ctx context.Context, requestorId int, targetId int) (bool, error) {
+	return models.FriendRequests(
+		models.FriendRequestWhere.RequestorID.EQ(requestorId),
+		models.FriendRequestWhere.TargetID.EQ(targetId),
+		models.FriendRequestWhere.Status.EQ(FriendRequestPending)).
+		Exists(ctx, _self.Db)
+}
+
+// Change the status of a pending friend request from requestor to target, return the number of updated rows
+func (_self DBRepo) UpdateFriendRequestStatus(ctx context.Context, requestorId int, targetId int, status string) (int64, error) {
+	return models.FriendRequests(
+		models.FriendRequestWhere.RequestorID.EQ(requestorId),
+		models.FriendRequestWhere.TargetID.EQ(targetId),
+		models.FriendRequestWhere.Status.EQ(FriendRequestPending)).
+		UpdateAll(ctx, _self.Db, models.M{
+			models.FriendRequestColumns.Status:    status,
+			models.FriendRequestColumns.UpdatedAt: time.Now(),
+		})
+}
+
+// Accept a pending friend request, insert the friendship, its notifications and its webhook deliveries in one transaction
+func (_self DBRepo) AcceptFriendRequest(ctx context.Context, requestorId int, targetId int, notifications []notify.Message, change webhooks.Payload) error {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	updated, err := models.FriendRequests(
+		models.FriendRequestWhere.RequestorID.EQ(requestorId),
+		models.FriendRequestWhere.TargetID.EQ(targetId),
+		models.FriendRequestWhere.Status.EQ(FriendRequestPending)).
+		UpdateAll(ctx, tx, models.M{
+			models.FriendRequestColumns.Status:    FriendRequestAccepted,
+			models.FriendRequestColumns.UpdatedAt: time.Now(),
+		})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
+
+	friend := models.Friend{
+		UserID:   requestorId,
+		FriendID: targetId,
+	}
+	if err := friend.Insert(ctx, tx, boil.Infer()); err != nil {
+		return err
+	}
+	if err := insertNotifications(ctx, tx, notifications); err != nil {
+		return err
+	}
+	if err := insertWebhookDeliveries(ctx, tx, change); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Get pending friend requests sent to a user from friend_requests table
+func (_self DBRepo) GetIncomingFriendRequests(ctx context.Context, targetId int) (models.FriendRequestSlice, error) {
+	return models.FriendRequests(
+		models.FriendRequestWhere.TargetID.EQ(targetId),
+		models.FriendRequestWhere.Status.EQ(FriendRequestPending),
+		qm.OrderBy(models.FriendRequestColumns.CreatedAt),
+	).All(ctx, _self.Db)
+}
+
+// Get pending friend requests sent by a user from friend_requests table
+func (_self DBRepo) GetOutgoingFriendRequests(ctx context.Context, requestorId int) (models.FriendRequestSlice, error) {
+	return models.FriendRequests(
+		models.FriendRequestWhere.RequestorID.EQ(requestorId),
+		models.FriendRequestWhere.Status.EQ(FriendRequestPending),
+		qm.OrderBy(models.FriendRequestColumns.CreatedAt),
+	).All(ctx, _self.Db)
+}
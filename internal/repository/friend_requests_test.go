@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_CreateFriendRequest(t *testing.T) {
+	tcs := map[string]struct {
+		requestorId int
+		targetId    int
+		expError    error
+	}{
+		"success with adding input of userIds": {
+			requestorId: 100,
+			targetId:    101,
+		},
+		"success with adding input of a declined request": {
+			requestorId: 104,
+			targetId:    100,
+		},
+		"query by an existing pending request": {
+			requestorId: 101,
+			targetId:    104,
+			expError:    errors.New("models: unable to insert into friend_requests: pq: duplicate key value violates unique constraint \"pending_on_friend_requests\""),
+		},
+		"query by an unknown input userIds": {
+			requestorId: 100,
+			targetId:    99,
+			expError:    errors.New("models: unable to insert into friend_requests: pq: insert or update on table \"friend_requests\" violates foreign key constraint \"friend_requests_target_id_fkey\""),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			err = repo.CreateFriendRequest(ctx, tc.requestorId, tc.targetId)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRepository_IsPendingFriendRequest(t *testing.T) {
+	tcs := map[string]struct {
+		requestorId int
+		targetId    int
+		expResult   bool
+		expError    error
+	}{
+		"success with input of a pending request": {
+			requestorId: 101,
+			targetId:    104,
+			expResult:   true,
+		},
+		"query by input of the reverse direction": {
+			requestorId: 104,
+			targetId:    101,
+			expResult:   false,
+		},
+		"query by input of a declined request": {
+			requestorId: 104,
+			targetId:    100,
+			expResult:   false,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			result, err := repo.IsPendingFriendRequest(ctx, tc.requestorId, tc.targetId)
+
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
+
+func TestRepository_UpdateFriendRequestStatus(t *testing.T) {
+	tcs := map[string]struct {
+		requestorId int
+		targetId    int
+		status      string
+		expResult   int64
+		expError    error
+	}{
+		"success with declining a pending request": {
+			requestorId: 101,
+			targetId:    104,
+			status:      FriendRequestDeclined,
+			expResult:   1,
+		},
+		"success with cancelling a pending request": {
+			requestorId: 103,
+			targetId:    101,
+			status:      FriendRequestCancelled,
+			expResult:   1,
+		},
+		"query by input of a declined request": {
+			requestorId: 104,
+			targetId:    100,
+			status:      FriendRequestCancelled,
+			expResult:   0,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			result, err := repo.UpdateFriendRequestStatus(ctx, tc.requestorId, tc.targetId, tc.status)
+
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
+
+func TestRepository_AcceptFriendRequest(t *testing.T) {
+	tcs := map[string]struct {
+		requestorId int
+		targetId    int
+		expError    error
+	}{
+		"success with accepting a pending request": {
+			requestorId: 101,
+			targetId:    104,
+		},
+		"query by input of a declined request": {
+			requestorId: 104,
+			targetId:    100,
+			expError:    sql.ErrNoRows,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			notifications := []notify.Message{
+				notify.NewFriendAdded("andy@example.com", "kate@example.com"),
+				notify.NewFriendAdded("kate@example.com", "andy@example.com"),
+			}
+			err = repo.AcceptFriendRequest(ctx, tc.requestorId, tc.targetId, notifications, testChange(webhooks.FriendAdded))
+
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+				require.Equal(t, 0, countNotifications(t, db))
+			} else {
+				require.Equal(t, 2, countNotifications(t, db))
+				require.NoError(t, err)
+				isExisted, err := repo.IsExistedFriend(ctx, tc.requestorId, tc.targetId)
+				require.NoError(t, err)
+				require.True(t, isExisted)
+				isPending, err := repo.IsPendingFriendRequest(ctx, tc.requestorId, tc.targetId)
+				require.NoError(t, err)
+				require.False(t, isPending)
+			}
+		})
+	}
+}
+
+func TestRepository_GetIncomingFriendRequests(t *testing.T) {
+	tcs := map[string]struct {
+		targetId  int
+		expResult []int
+		expError  error
+	}{
+		"success with input of a target having a pending request": {
+			targetId:  101,
+			expResult: []int{103},
+		},
+		"query by input of a target having a declined request": {
+			targetId:  100,
+			expResult: []int{},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			result, err := repo.GetIncomingFriendRequests(ctx, tc.targetId)
+
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				requestorIds := make([]int, 0)
+				for _, friendRequest := range result {
+					requestorIds = append(requestorIds, friendRequest.RequestorID)
+				}
+				require.Equal(t, tc.expResult, requestorIds)
+			}
+		})
+	}
+}
+
+func TestRepository_GetOutgoingFriendRequests(t *testing.T) {
+	tcs := map[string]struct {
+		requestorId int
+		expResult   []int
+		expError    error
+	}{
+		"success with input of a requestor having a pending request": {
+			requestorId: 101,
+			expResult:   []int{104},
+		},
+		"query by input of a requestor having a declined request": {
+			requestorId: 104,
+			expResult:   []int{},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			result, err := repo.GetOutgoingFriendRequests(ctx, tc.requestorId)
+
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				targetIds := make([]int, 0)
+				for _, friendRequest := range result {
+					targetIds = append(targetIds, friendRequest.TargetID)
+				}
+				require.Equal(t, tc.expResult, targetIds)
+			}
+		})
+	}
+}
@@ -4,18 +4,35 @@ import (
 	"context"
 
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 )
 
-// Insert a new record into friends table
-func (_self DBRepo) CreateFriend(ctx context.Context, userId int, friendId int) error {
-	friend := models.Friend{
-		UserID:   userId,
-		FriendID: friendId,
+// Delete a friendship from friends table regardless of the direction it was stored in, the webhook deliveries
+// of the change are inserted in the same transaction
+func (_self DBRepo) DeleteFriend(ctx context.Context, userId int, friendId int, change webhooks.Payload) error {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	deleted, err := models.Friends(
+		qm.Where("user_id = ? AND friend_id = ?", userId, friendId),
+		qm.Or("user_id = ? AND friend_id = ?", friendId, userId)).
+		DeleteAll(ctx, tx)
+	if err != nil {
+		return err
 	}
-	return friend.Insert(ctx, _self.Db, boil.Infer())
+	if deleted > 0 {
+		if err := insertWebhookDeliveries(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 // Get friendship slice from friends table by user id
@@ -34,18 +51,81 @@ func (_self DBRepo) GetUserBlocksByID(ctx context.Context, userId int) (models.U
 	).All(ctx, _self.Db)
 }
 
-// Insert a new record into subscriptions table
-func (_self DBRepo) CreateSubscription(ctx context.Context, requestorId int, targetId int) error {
+// Get friendship slice from friends table of a list of users, in both directions
+func (_self DBRepo) GetFriendsByIDs(ctx context.Context, userIds []int) (models.FriendSlice, error) {
+	if len(userIds) == 0 {
+		return models.FriendSlice{}, nil
+	}
+	return models.Friends(
+		qm.Select(models.FriendColumns.UserID, models.FriendColumns.FriendID),
+		models.FriendWhere.UserID.IN(userIds), qm.Or2(models.FriendWhere.FriendID.IN(userIds)),
+	).All(ctx, _self.Db)
+}
+
+// Get blocked user relationship slice from user_blocks table of a list of users, in both directions
+func (_self DBRepo) GetUserBlocksByIDs(ctx context.Context, userIds []int) (models.UserBlockSlice, error) {
+	if len(userIds) == 0 {
+		return models.UserBlockSlice{}, nil
+	}
+	return models.UserBlocks(
+		qm.Select(models.UserBlockColumns.RequestorID, models.UserBlockColumns.TargetID),
+		models.UserBlockWhere.RequestorID.IN(userIds), qm.Or2(models.UserBlockWhere.TargetID.IN(userIds)),
+	).All(ctx, _self.Db)
+}
+
+// Insert a new record into subscriptions table with the webhook deliveries of the change in one transaction
+func (_self DBRepo) CreateSubscription(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) error {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	subscription := models.Subscription{
 		SubscriptionRequestorID: requestorId,
 		SubscriptionTargetID:    targetId,
 	}
-	return subscription.Insert(ctx, _self.Db, boil.Infer())
+	if err := subscription.Insert(ctx, tx, boil.Infer()); err != nil {
+		return err
+	}
+	if err := insertWebhookDeliveries(ctx, tx, change); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// Get users slice (who are not blocked by sender) by user id
-func (_self DBRepo) GetRecipientEmails(ctx context.Context, senderId int) (models.UserSlice, error) {
-	query := `SELECT DISTINCT val.email FROM (
+// Delete a subscription of requestor to target from subscriptions table with the webhook deliveries of the change
+// in one transaction, return the number of deleted rows
+func (_self DBRepo) DeleteSubscription(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) (int64, error) {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	deleted, err := models.Subscriptions(
+		models.SubscriptionWhere.SubscriptionRequestorID.EQ(requestorId),
+		models.SubscriptionWhere.SubscriptionTargetID.EQ(targetId)).
+		DeleteAll(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		if err := insertWebhookDeliveries(ctx, tx, change); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// recipientsQuery selects the id and email of the users receiving the updates of the sender $1:
+// its friends and its subscribers, without the users having a blocking relationship with the sender
+const recipientsQuery = `SELECT val.id, val.email FROM (
 	        SELECT u.id, u.email
 	        FROM users u JOIN friends f ON (u.id = f.user_id OR u.id = f.friend_id)
 	        WHERE u.id <> $1 AND (f.user_id = $1 OR f.friend_id = $1)
@@ -59,6 +139,10 @@ func (_self DBRepo) GetRecipientEmails(ctx context.Context, senderId int) (model
 	        WHERE (b.requestor_id = val.id AND b.target_id = $1) OR (b.target_id = val.id AND b.requestor_id = $1)
 	)`
 
+// Get users slice (who are not blocked by sender) by user id
+func (_self DBRepo) GetRecipientEmails(ctx context.Context, senderId int) (models.UserSlice, error) {
+	query := `SELECT DISTINCT val.email FROM (` + recipientsQuery + `) AS val`
+
 	nonBlockUsers := models.UserSlice{} //make([]models.User, 0)
 	err := queries.Raw(query, senderId).Bind(ctx, _self.Db, &nonBlockUsers)
 	if err != nil {
@@ -68,13 +152,70 @@ func (_self DBRepo) GetRecipientEmails(ctx context.Context, senderId int) (model
 	return nonBlockUsers, nil
 }
 
-// Insert a blocking relationship of users into user_blocks table
-func (_self DBRepo) CreateUserBlock(ctx context.Context, requestorId int, targetId int) error {
+// Get the users of a list of emails who can be mentioned by a sender, the sender and the users having
+// a blocking relationship with the sender are left out
+func (_self DBRepo) GetMentionableUsers(ctx context.Context, senderId int, emails []string) (models.UserSlice, error) {
+	if len(emails) == 0 {
+		return models.UserSlice{}, nil
+	}
+
+	return models.Users(
+		qm.Select(models.UserColumns.Email),
+		qm.Where(`id <> ?`, senderId),
+		qm.Where(notBlockedCondition, senderId, senderId),
+		models.UserWhere.Email.IN(emails),
+	).All(ctx, _self.Db)
+}
+
+// Insert a blocking relationship of users into user_blocks table with the webhook deliveries of the change
+// in one transaction
+func (_self DBRepo) CreateUserBlock(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) error {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	userBlock := models.UserBlock{
 		RequestorID: requestorId,
 		TargetID:    targetId,
 	}
-	return userBlock.Insert(ctx, _self.Db, boil.Infer())
+	if err := userBlock.Insert(ctx, tx, boil.Infer()); err != nil {
+		return err
+	}
+	if err := insertWebhookDeliveries(ctx, tx, change); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete a blocking relationship created by requestor from user_blocks table with the webhook deliveries
+// of the change in one transaction, return the number of deleted rows
+func (_self DBRepo) DeleteUserBlock(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) (int64, error) {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	deleted, err := models.UserBlocks(
+		models.UserBlockWhere.RequestorID.EQ(requestorId),
+		models.UserBlockWhere.TargetID.EQ(targetId)).
+		DeleteAll(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		if err := insertWebhookDeliveries(ctx, tx, change); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return deleted, nil
 }
 
 // Verify a existing friendship
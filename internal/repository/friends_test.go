@@ -9,6 +9,7 @@ import (
 
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,20 +23,22 @@ func loadSqlTestFile(t *testing.T, db *sql.DB, sqlfile string) {
 	require.NoError(t, err)
 }
 
-func TestRepository_CreateFriend(t *testing.T) {
+func TestRepository_IsExistedFriend(t *testing.T) {
 	tcs := map[string]struct {
-		userId   int
-		friendId int
-		expError error
+		userId    int
+		friendId  int
+		expResult bool
+		expError  error
 	}{
 		"success with adding input of userIds": {
-			userId:   100,
-			friendId: 104,
+			userId:    100,
+			friendId:  102,
+			expResult: true,
 		},
 		"query by an unknown input userIds": {
-			userId:   100,
-			friendId: 99,
-			expError: errors.New("models: unable to insert into friends: pq: insert or update on table \"friends\" violates foreign key constraint \"friends_friend_id_fkey\""),
+			userId:    100,
+			friendId:  99,
+			expResult: false,
 		},
 	}
 
@@ -48,32 +51,35 @@ func TestRepository_CreateFriend(t *testing.T) {
 
 			// load testdata
 			loadSqlTestFile(t, db, "testdata/friends.sql")
-			err = repo.CreateFriend(ctx, tc.userId, tc.friendId)
+			result, err := repo.IsExistedFriend(ctx, tc.userId, tc.friendId)
+
 			if tc.expError != nil {
 				require.EqualError(t, err, tc.expError.Error())
 			} else {
 				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
 			}
 		})
 	}
 }
 
-func TestRepository_IsExistedFriend(t *testing.T) {
+func TestRepository_DeleteFriend(t *testing.T) {
 	tcs := map[string]struct {
-		userId    int
-		friendId  int
-		expResult bool
-		expError  error
+		userId   int
+		friendId int
+		expError error
 	}{
-		"success with adding input of userIds": {
-			userId:    100,
-			friendId:  102,
-			expResult: true,
+		"success with input of userIds in the stored direction": {
+			userId:   102,
+			friendId: 103,
+		},
+		"success with input of userIds in the reverse direction": {
+			userId:   103,
+			friendId: 102,
 		},
 		"query by an unknown input userIds": {
-			userId:    100,
-			friendId:  99,
-			expResult: false,
+			userId:   100,
+			friendId: 99,
 		},
 	}
 
@@ -86,13 +92,15 @@ func TestRepository_IsExistedFriend(t *testing.T) {
 
 			// load testdata
 			loadSqlTestFile(t, db, "testdata/friends.sql")
-			result, err := repo.IsExistedFriend(ctx, tc.userId, tc.friendId)
+			err = repo.DeleteFriend(ctx, tc.userId, tc.friendId, testChange(webhooks.FriendRemoved))
 
 			if tc.expError != nil {
 				require.EqualError(t, err, tc.expError.Error())
 			} else {
 				require.NoError(t, err)
-				require.Equal(t, tc.expResult, result)
+				isExisted, err := repo.IsExistedFriend(ctx, tc.userId, tc.friendId)
+				require.NoError(t, err)
+				require.False(t, isExisted)
 			}
 		})
 	}
@@ -215,6 +223,76 @@ func TestRepository_GetUserBlocksByID(t *testing.T) {
 	}
 }
 
+func TestRepository_GetFriendsByIDs(t *testing.T) {
+	tcs := map[string]struct {
+		userIds   []int
+		expResult models.FriendSlice
+	}{
+		"friendships of users in both directions": {
+			userIds: []int{100, 103},
+			expResult: models.FriendSlice{
+				&models.Friend{UserID: 100, FriendID: 102},
+				&models.Friend{UserID: 102, FriendID: 103},
+			},
+		},
+		"no user ids": {
+			userIds:   []int{},
+			expResult: models.FriendSlice{},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			result, err := repo.GetFriendsByIDs(ctx, tc.userIds)
+
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.expResult, result)
+		})
+	}
+}
+
+func TestRepository_GetUserBlocksByIDs(t *testing.T) {
+	tcs := map[string]struct {
+		userIds   []int
+		expResult models.UserBlockSlice
+	}{
+		"blocks of users in both directions": {
+			userIds: []int{103, 104},
+			expResult: models.UserBlockSlice{
+				&models.UserBlock{RequestorID: 100, TargetID: 103},
+				&models.UserBlock{RequestorID: 100, TargetID: 104},
+			},
+		},
+		"no user ids": {
+			userIds:   []int{},
+			expResult: models.UserBlockSlice{},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			result, err := repo.GetUserBlocksByIDs(ctx, tc.userIds)
+
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.expResult, result)
+		})
+	}
+}
+
 func TestRepository_CreateSubscription(t *testing.T) {
 	tcs := map[string]struct {
 		requestorId int
@@ -241,7 +319,7 @@ func TestRepository_CreateSubscription(t *testing.T) {
 
 			// load testdata
 			loadSqlTestFile(t, db, "testdata/friends.sql")
-			err = repo.CreateSubscription(ctx, tc.requestorId, tc.targetId)
+			err = repo.CreateSubscription(ctx, tc.requestorId, tc.targetId, testChange(webhooks.SubscriptionCreated))
 
 			if tc.expError != nil {
 				require.EqualError(t, err, tc.expError.Error())
@@ -252,6 +330,51 @@ func TestRepository_CreateSubscription(t *testing.T) {
 	}
 }
 
+func TestRepository_DeleteSubscription(t *testing.T) {
+	tcs := map[string]struct {
+		requestorId int
+		targetId    int
+		expResult   int64
+		expError    error
+	}{
+		"success with adding input of userIds": {
+			requestorId: 101,
+			targetId:    103,
+			expResult:   1,
+		},
+		"query by a reversed input userIds": {
+			requestorId: 103,
+			targetId:    101,
+			expResult:   0,
+		},
+		"query by an unknown input userIds": {
+			requestorId: 99,
+			targetId:    100,
+			expResult:   0,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			result, err := repo.DeleteSubscription(ctx, tc.requestorId, tc.targetId, testChange(webhooks.SubscriptionRemoved))
+
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
+
 func TestRepository_GetRecipientEmails(t *testing.T) {
 	tcs := map[string]struct {
 		senderId  int
@@ -289,6 +412,49 @@ func TestRepository_GetRecipientEmails(t *testing.T) {
 	}
 }
 
+func TestRepository_GetMentionableUsers(t *testing.T) {
+	tcs := map[string]struct {
+		senderId  int
+		emails    []string
+		expResult []string
+	}{
+		"success with blocked users, the sender and unknown emails left out": {
+			senderId:  100,
+			emails:    []string{"andy@example.com", "lisa@example.com", "kate@example.com", "john@example.com", "bob@example.com"},
+			expResult: []string{"andy@example.com"},
+		},
+		"success with users who are not recipients": {
+			senderId:  101,
+			emails:    []string{"common@example.com", "kate@example.com"},
+			expResult: []string{"common@example.com", "kate@example.com"},
+		},
+		"query by an empty input emails": {
+			senderId:  100,
+			expResult: []string{},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			result, err := repo.GetMentionableUsers(ctx, tc.senderId, tc.emails)
+
+			require.NoError(t, err)
+			emails := make([]string, len(result))
+			for i, user := range result {
+				emails[i] = user.Email
+			}
+			require.ElementsMatch(t, tc.expResult, emails)
+		})
+	}
+}
+
 func TestRepository_CreateUserBlock(t *testing.T) {
 	tcs := map[string]struct {
 		requestorId int
@@ -315,12 +481,57 @@ func TestRepository_CreateUserBlock(t *testing.T) {
 
 			// load testdata
 			loadSqlTestFile(t, db, "testdata/friends.sql")
-			err = repo.CreateUserBlock(ctx, tc.requestorId, tc.targetId)
+			err = repo.CreateUserBlock(ctx, tc.requestorId, tc.targetId, testChange(webhooks.BlockCreated))
+
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRepository_DeleteUserBlock(t *testing.T) {
+	tcs := map[string]struct {
+		requestorId int
+		targetId    int
+		expResult   int64
+		expError    error
+	}{
+		"success with adding input of userIds": {
+			requestorId: 100,
+			targetId:    103,
+			expResult:   1,
+		},
+		"query by a reversed input userIds": {
+			requestorId: 103,
+			targetId:    100,
+			expResult:   0,
+		},
+		"query by an unknown input userIds": {
+			requestorId: 99,
+			targetId:    100,
+			expResult:   0,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			result, err := repo.DeleteUserBlock(ctx, tc.requestorId, tc.targetId, testChange(webhooks.BlockRemoved))
 
 			if tc.expError != nil {
 				require.EqualError(t, err, tc.expError.Error())
 			} else {
 				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
 			}
 		})
 	}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+// Status values of a record in outbox table
+const (
+	OutboxPending = "pending"
+	OutboxSent    = "sent"
+	OutboxDead    = "dead"
+)
+
+// Insert pending notifications into outbox table, they are due at once. The executor is the transaction
+// of the change the notifications are about.
+func insertNotifications(ctx context.Context, exec boil.ContextExecutor, messages []notify.Message) error {
+	now := time.Now()
+	for _, message := range messages {
+		if _, err := exec.ExecContext(ctx,
+			`INSERT INTO outbox (kind, recipient, subject, body, status, next_attempt_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $6, $6)`,
+			message.Kind, message.Recipient, message.Subject, message.Body, OutboxPending, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Claim the pending notifications of outbox table which are due at now, the oldest first. Each claimed notification
+// gets one more attempt and is not due again before now + lease. Concurrent dispatchers claim different notifications.
+func (_self DBRepo) ClaimNotifications(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]notify.Notification, error) {
+	query := `WITH claimed AS (
+	        UPDATE outbox SET attempts = attempts + 1, next_attempt_at = $2, updated_at = $1
+	        WHERE id IN (
+	            SELECT id FROM outbox
+	            WHERE status = $3 AND next_attempt_at <= $1
+	            ORDER BY id
+	            LIMIT $4
+	            FOR UPDATE SKIP LOCKED
+	        )
+	        RETURNING id, attempts, kind, recipient, subject, body
+	    )
+	    SELECT id, attempts, kind, recipient, subject, body FROM claimed ORDER BY id`
+
+	rows, err := _self.Db.QueryContext(ctx, query, now, now.Add(lease), OutboxPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]notify.Notification, 0)
+	for rows.Next() {
+		var notification notify.Notification
+		if err := rows.Scan(&notification.ID, &notification.Attempts, &notification.Kind,
+			&notification.Recipient, &notification.Subject, &notification.Body); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// Mark a notification of outbox table as sent, the error of a previous attempt is kept
+func (_self DBRepo) MarkNotificationSent(ctx context.Context, id int) error {
+	return _self.setNotificationStatus(ctx, id, OutboxSent, time.Now(), sql.NullString{})
+}
+
+// Keep a notification of outbox table pending until nextAttemptAt with the error of its last attempt
+func (_self DBRepo) RetryNotification(ctx context.Context, id int, nextAttemptAt time.Time, lastError string) error {
+	return _self.setNotificationStatus(ctx, id, OutboxPending, nextAttemptAt, sql.NullString{String: lastError, Valid: true})
+}
+
+// Mark a notification of outbox table as dead with the error of its last attempt, it is not attempted anymore
+func (_self DBRepo) DeadLetterNotification(ctx context.Context, id int, lastError string) error {
+	return _self.setNotificationStatus(ctx, id, OutboxDead, time.Now(), sql.NullString{String: lastError, Valid: true})
+}
+
+func (_self DBRepo) setNotificationStatus(ctx context.Context, id int, status string, nextAttemptAt time.Time, lastError sql.NullString) error {
+	result, err := _self.Db.ExecContext(ctx,
+		`UPDATE outbox SET status = $2, next_attempt_at = $3, last_error = COALESCE($4, last_error), updated_at = $5 WHERE id = $1`,
+		id, status, nextAttemptAt, lastError, time.Now())
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/stretchr/testify/require"
+)
+
+// countNotifications is the number of notifications of outbox table
+func countNotifications(t *testing.T, db *sql.DB) int {
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM outbox`).Scan(&count))
+	return count
+}
+
+// notificationStatus is the status, the attempts and the last error of a notification of outbox table
+func notificationStatus(t *testing.T, db *sql.DB, id int) (string, int, sql.NullString) {
+	var status string
+	var attempts int
+	var lastError sql.NullString
+	require.NoError(t, db.QueryRow(`SELECT status, attempts, last_error FROM outbox WHERE id = $1`, id).
+		Scan(&status, &attempts, &lastError))
+	return status, attempts, lastError
+}
+
+func TestRepository_ClaimNotifications(t *testing.T) {
+	ctx := context.Background()
+	db, err := config.NewDatabase()
+	require.NoError(t, err)
+	repo := NewDBRepo(db)
+
+	// load testdata
+	loadSqlTestFile(t, db, "testdata/friends.sql")
+	require.NoError(t, insertNotifications(ctx, db, []notify.Message{
+		notify.NewFriendAdded("john@example.com", "andy@example.com"),
+		notify.NewFriendAdded("andy@example.com", "john@example.com"),
+		notify.NewFriendAdded("lisa@example.com", "kate@example.com"),
+	}))
+	now := time.Now()
+
+	// The oldest notifications first, up to the limit
+	claimed, err := repo.ClaimNotifications(ctx, now, time.Minute, 2)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	require.Equal(t, "john@example.com", claimed[0].Recipient)
+	require.Equal(t, "andy@example.com", claimed[1].Recipient)
+	require.Equal(t, notify.FriendAdded, claimed[0].Kind)
+	require.Equal(t, "andy@example.com is now your friend", claimed[0].Subject)
+	require.Equal(t, 1, claimed[0].Attempts)
+
+	// Claimed notifications are left out until their lease ends
+	others, err := repo.ClaimNotifications(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, others, 1)
+	require.Equal(t, "lisa@example.com", others[0].Recipient)
+
+	again, err := repo.ClaimNotifications(ctx, now.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, again, 3)
+	require.Equal(t, 2, again[0].Attempts)
+}
+
+func TestRepository_NotificationStatus(t *testing.T) {
+	ctx := context.Background()
+	db, err := config.NewDatabase()
+	require.NoError(t, err)
+	repo := NewDBRepo(db)
+
+	// load testdata
+	loadSqlTestFile(t, db, "testdata/friends.sql")
+	require.NoError(t, insertNotifications(ctx, db, []notify.Message{
+		notify.NewFriendAdded("john@example.com", "andy@example.com"),
+		notify.NewFriendAdded("andy@example.com", "john@example.com"),
+	}))
+	now := time.Now()
+	claimed, err := repo.ClaimNotifications(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	sentID, deadID := claimed[0].ID, claimed[1].ID
+
+	// A retried notification is due again at its next attempt
+	require.NoError(t, repo.RetryNotification(ctx, sentID, now.Add(time.Hour), "connection refused"))
+	claimed, err = repo.ClaimNotifications(ctx, now.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, deadID, claimed[0].ID)
+
+	require.NoError(t, repo.MarkNotificationSent(ctx, sentID))
+	status, attempts, lastError := notificationStatus(t, db, sentID)
+	require.Equal(t, OutboxSent, status)
+	require.Equal(t, 1, attempts)
+	require.Equal(t, sql.NullString{String: "connection refused", Valid: true}, lastError)
+
+	require.NoError(t, repo.DeadLetterNotification(ctx, deadID, "mailbox unavailable"))
+	status, attempts, lastError = notificationStatus(t, db, deadID)
+	require.Equal(t, OutboxDead, status)
+	require.Equal(t, 2, attempts)
+	require.Equal(t, sql.NullString{String: "mailbox unavailable", Valid: true}, lastError)
+
+	// Sent and dead notifications are never claimed again
+	claimed, err = repo.ClaimNotifications(ctx, now.Add(2*time.Hour), time.Minute, 10)
+	require.NoError(t, err)
+	require.Empty(t, claimed)
+
+	require.Equal(t, sql.ErrNoRows, repo.MarkNotificationSent(ctx, 0))
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/lib/pq"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+// Emails are compared byte-wise like the cursors, the users_email_keyset index serves these comparisons
+const keysetColumn = `email COLLATE "C"`
+
+// Condition of the users who have no blocking relationship with the user of the two parameters
+const notBlockedCondition = `NOT EXISTS (
+	SELECT 1 FROM user_blocks b
+	WHERE (b.requestor_id = users.id AND b.target_id = ?) OR (b.target_id = users.id AND b.requestor_id = ?))`
+
+// keysetMods selects the users of a page: ordered by email in the direction of the page, within its cursors
+// and one more than its size to know whether the list goes on
+func keysetMods(page pagination.Page) []qm.QueryMod {
+	mods := []qm.QueryMod{qm.Select(models.UserColumns.Email)}
+	if page.After != "" {
+		mods = append(mods, qm.Where(keysetColumn+" > ?", page.After))
+	}
+	if page.Before != "" {
+		mods = append(mods, qm.Where(keysetColumn+" < ?", page.Before))
+	}
+	if page.Backward {
+		mods = append(mods, qm.OrderBy(keysetColumn+" DESC"))
+	} else {
+		mods = append(mods, qm.OrderBy(keysetColumn+" ASC"))
+	}
+	return append(mods, qm.Limit(page.Size+1))
+}
+
+// recipientMods selects the friends and subscribers of a sender who have no blocking relationship with the sender
+func recipientMods(senderId int) []qm.QueryMod {
+	return []qm.QueryMod{
+		qm.Where(`id <> ?`, senderId),
+		qm.Where(`id IN (
+			SELECT friend_id FROM friends WHERE user_id = ?
+			UNION SELECT user_id FROM friends WHERE friend_id = ?
+			UNION SELECT subscription_requestor_id FROM subscriptions WHERE subscription_target_id = ?)`,
+			senderId, senderId, senderId),
+		qm.Where(notBlockedCondition, senderId, senderId),
+	}
+}
+
+// Get a page of users from users table
+func (_self DBRepo) GetUsersPage(ctx context.Context, page pagination.Page) (models.UserSlice, error) {
+	return models.Users(keysetMods(page)...).All(ctx, _self.Db)
+}
+
+// Count all users of users table
+func (_self DBRepo) CountUsers(ctx context.Context) (int64, error) {
+	return models.Users().Count(ctx, _self.Db)
+}
+
+// Get a page of the users receiving the updates of a sender
+func (_self DBRepo) GetRecipientsPage(ctx context.Context, senderId int, page pagination.Page) (models.UserSlice, error) {
+	return models.Users(append(recipientMods(senderId), keysetMods(page)...)...).All(ctx, _self.Db)
+}
+
+// Count the users receiving the updates of a sender
+func (_self DBRepo) CountRecipients(ctx context.Context, senderId int) (int64, error) {
+	return models.Users(recipientMods(senderId)...).Count(ctx, _self.Db)
+}
+
+// Get the users of a list of emails who receive the updates of a sender
+func (_self DBRepo) GetRecipientsByEmails(ctx context.Context, senderId int, emails []string) (models.UserSlice, error) {
+	if len(emails) == 0 {
+		return models.UserSlice{}, nil
+	}
+
+	mods := append(recipientMods(senderId), qm.Select(models.UserColumns.Email), models.UserWhere.Email.IN(emails))
+	return models.Users(mods...).All(ctx, _self.Db)
+}
+
+// RelationPage is the window fetched for the page of the users related to a user with the count of all of them.
+// The window is in the order of the page and has one extra email when the list goes on, like the pages of users.
+type RelationPage struct {
+	Emails     []string
+	TotalCount int64
+}
+
+// Relationships of the users of $1 as pairs of a user and a related user
+const (
+	friendPairs = `SELECT user_id AS owner_id, friend_id AS related_id FROM friends WHERE user_id = ANY($1)
+	    UNION SELECT friend_id, user_id FROM friends WHERE friend_id = ANY($1)`
+	subscriberPairs = `SELECT subscription_target_id AS owner_id, subscription_requestor_id AS related_id
+	    FROM subscriptions WHERE subscription_target_id = ANY($1)`
+	subscriptionPairs = `SELECT subscription_requestor_id AS owner_id, subscription_target_id AS related_id
+	    FROM subscriptions WHERE subscription_requestor_id = ANY($1)`
+	blockedUserPairs = `SELECT requestor_id AS owner_id, target_id AS related_id FROM user_blocks WHERE requestor_id = ANY($1)`
+)
+
+// Condition of the pairs whose users have no blocking relationship
+const notBlockedPairCondition = `NOT EXISTS (
+	SELECT 1 FROM user_blocks b
+	WHERE (b.requestor_id = p.related_id AND b.target_id = p.owner_id) OR (b.target_id = p.related_id AND b.requestor_id = p.owner_id))`
+
+// Condition of the users within the cursors $2 and $3 of a page, an empty cursor is no bound
+const inPageCondition = `(($2::text = '' OR ` + keysetColumn + ` > $2) AND ($3::text = '' OR ` + keysetColumn + ` < $3))`
+
+// relationRow is a user related to the owner of a page with the count of all the users related to the owner
+type relationRow struct {
+	OwnerID    int    `boil:"owner_id"`
+	Email      string `boil:"email"`
+	TotalCount int64  `boil:"total_count"`
+	InPage     bool   `boil:"in_page"`
+}
+
+// getRelationPages gets the page of the users related by pairs to every user of a list in one query.
+// The related users of a user are counted and numbered in the order of the page, the first one out of
+// the page is kept too so the count of a user whose page is empty is not lost.
+func (_self DBRepo) getRelationPages(ctx context.Context, pairs string, condition string, userIds []int, page pagination.Page) (map[int]RelationPage, error) {
+	pages := make(map[int]RelationPage, len(userIds))
+	if len(userIds) == 0 {
+		return pages, nil
+	}
+
+	direction := "ASC"
+	if page.Backward {
+		direction = "DESC"
+	}
+	query := `SELECT owner_id, email, total_count, in_page FROM (
+	        SELECT p.owner_id, u.email, ` + inPageCondition + ` AS in_page,
+	            count(*) OVER (PARTITION BY p.owner_id) AS total_count,
+	            row_number() OVER (PARTITION BY p.owner_id, ` + inPageCondition + `
+	                ORDER BY ` + keysetColumn + ` ` + direction + `) AS row_position
+	        FROM (` + pairs + `) AS p JOIN users u ON u.id = p.related_id
+	        WHERE ` + condition + `
+	    ) AS related
+	    WHERE (in_page AND row_position <= $4) OR row_position = 1
+	    ORDER BY owner_id, row_position`
+
+	ids := make([]int64, len(userIds))
+	for i, userId := range userIds {
+		ids[i] = int64(userId)
+		pages[userId] = RelationPage{Emails: []string{}}
+	}
+
+	rows := []relationRow{}
+	if err := queries.Raw(query, pq.Array(ids), page.After, page.Before, page.Size+1).Bind(ctx, _self.Db, &rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		relationPage := pages[row.OwnerID]
+		relationPage.TotalCount = row.TotalCount
+		if row.InPage {
+			relationPage.Emails = append(relationPage.Emails, row.Email)
+		}
+		pages[row.OwnerID] = relationPage
+	}
+	return pages, nil
+}
+
+// Get the pages of the friends of a list of users with their counts, blocked friends are left out
+func (_self DBRepo) GetFriendsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]RelationPage, error) {
+	return _self.getRelationPages(ctx, friendPairs, notBlockedPairCondition, userIds, page)
+}
+
+// Get the pages of the subscribers of a list of users with their counts, blocked subscribers are left out
+func (_self DBRepo) GetSubscribersPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]RelationPage, error) {
+	return _self.getRelationPages(ctx, subscriberPairs, notBlockedPairCondition, userIds, page)
+}
+
+// Get the pages of the users a list of users subscribe to with their counts, blocked users are left out
+func (_self DBRepo) GetSubscriptionsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]RelationPage, error) {
+	return _self.getRelationPages(ctx, subscriptionPairs, notBlockedPairCondition, userIds, page)
+}
+
+// Get the pages of the users blocked by a list of users with their counts
+func (_self DBRepo) GetBlockedUsersPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]RelationPage, error) {
+	return _self.getRelationPages(ctx, blockedUserPairs, "TRUE", userIds, page)
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+// emailsOf returns the emails of a user slice in its order
+func emailsOf(users models.UserSlice) []string {
+	emails := make([]string, len(users))
+	for i, user := range users {
+		emails[i] = user.Email
+	}
+	return emails
+}
+
+func TestRepository_GetUsersPage(t *testing.T) {
+	tcs := map[string]struct {
+		page      pagination.Page
+		expResult []string
+	}{
+		"first users with an extra one": {
+			page:      pagination.Page{Size: 2},
+			expResult: []string{"andy@example.com", "common@example.com", "john@example.com"},
+		},
+		"users after a cursor": {
+			page:      pagination.Page{Size: 2, After: "john@example.com"},
+			expResult: []string{"kate@example.com", "lisa@example.com"},
+		},
+		"last users before a cursor in descending order": {
+			page:      pagination.Page{Size: 1, Before: "john@example.com", Backward: true},
+			expResult: []string{"common@example.com", "andy@example.com"},
+		},
+		"users between two cursors": {
+			page:      pagination.Page{Size: 10, After: "andy@example.com", Before: "kate@example.com"},
+			expResult: []string{"common@example.com", "john@example.com"},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			users, err := repo.GetUsersPage(ctx, tc.page)
+			require.NoError(t, err)
+			require.Equal(t, tc.expResult, emailsOf(users))
+
+			count, err := repo.CountUsers(ctx)
+			require.NoError(t, err)
+			require.Equal(t, int64(5), count)
+		})
+	}
+}
+
+func TestRepository_GetFriendsPages(t *testing.T) {
+	tcs := map[string]struct {
+		userIds   []int
+		page      pagination.Page
+		expResult map[int]RelationPage
+	}{
+		"friends in both directions": {
+			userIds: []int{102},
+			page:    pagination.Page{Size: 5},
+			expResult: map[int]RelationPage{
+				102: {Emails: []string{"andy@example.com", "john@example.com", "lisa@example.com"}, TotalCount: 3},
+			},
+		},
+		"friends after a cursor": {
+			userIds: []int{102},
+			page:    pagination.Page{Size: 1, After: "andy@example.com"},
+			expResult: map[int]RelationPage{
+				102: {Emails: []string{"john@example.com", "lisa@example.com"}, TotalCount: 3},
+			},
+		},
+		"last friends in descending order": {
+			userIds: []int{102},
+			page:    pagination.Page{Size: 1, Backward: true},
+			expResult: map[int]RelationPage{
+				102: {Emails: []string{"lisa@example.com", "john@example.com"}, TotalCount: 3},
+			},
+		},
+		"friends of several users in one query": {
+			userIds: []int{100, 101, 102, 104},
+			page:    pagination.Page{Size: 1},
+			expResult: map[int]RelationPage{
+				100: {Emails: []string{"common@example.com"}, TotalCount: 1},
+				101: {Emails: []string{"common@example.com"}, TotalCount: 1},
+				102: {Emails: []string{"andy@example.com", "john@example.com"}, TotalCount: 3},
+				104: {Emails: []string{}, TotalCount: 0},
+			},
+		},
+		"count of a user without friends in the page": {
+			userIds: []int{100},
+			page:    pagination.Page{Size: 5, After: "common@example.com"},
+			expResult: map[int]RelationPage{
+				100: {Emails: []string{}, TotalCount: 1},
+			},
+		},
+		"no users": {
+			userIds:   []int{},
+			page:      pagination.Page{Size: 5},
+			expResult: map[int]RelationPage{},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			pages, err := repo.GetFriendsPages(ctx, tc.userIds, tc.page)
+			require.NoError(t, err)
+			require.Equal(t, tc.expResult, pages)
+		})
+	}
+}
+
+func TestRepository_GetRecipientsPage(t *testing.T) {
+	tcs := map[string]struct {
+		senderId     int
+		page         pagination.Page
+		mentioned    []string
+		expResult    []string
+		expCount     int64
+		expMentioned []string
+	}{
+		"friends and subscribers of the sender": {
+			senderId:     103,
+			page:         pagination.Page{Size: 5},
+			mentioned:    []string{"kate@example.com", "andy@example.com"},
+			expResult:    []string{"andy@example.com", "common@example.com"},
+			expCount:     2,
+			expMentioned: []string{"andy@example.com"},
+		},
+		"blocked friends are left out": {
+			senderId:     100,
+			page:         pagination.Page{Size: 5},
+			mentioned:    []string{"kate@example.com"},
+			expResult:    []string{"common@example.com"},
+			expCount:     1,
+			expMentioned: []string{},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			recipients, err := repo.GetRecipientsPage(ctx, tc.senderId, tc.page)
+			require.NoError(t, err)
+			require.Equal(t, tc.expResult, emailsOf(recipients))
+
+			count, err := repo.CountRecipients(ctx, tc.senderId)
+			require.NoError(t, err)
+			require.Equal(t, tc.expCount, count)
+
+			mentioned, err := repo.GetRecipientsByEmails(ctx, tc.senderId, tc.mentioned)
+			require.NoError(t, err)
+			require.Equal(t, tc.expMentioned, emailsOf(mentioned))
+		})
+	}
+}
+
+func TestRepository_GetRelationPages(t *testing.T) {
+	tcs := map[string]struct {
+		userId    int
+		getPages  func(DBRepo, context.Context, []int, pagination.Page) (map[int]RelationPage, error)
+		expResult RelationPage
+	}{
+		"subscribers of a user": {
+			userId:    103,
+			getPages:  DBRepo.GetSubscribersPages,
+			expResult: RelationPage{Emails: []string{"andy@example.com"}, TotalCount: 1},
+		},
+		"subscriptions of a user": {
+			userId:    101,
+			getPages:  DBRepo.GetSubscriptionsPages,
+			expResult: RelationPage{Emails: []string{"lisa@example.com"}, TotalCount: 1},
+		},
+		"users blocked by a user": {
+			userId:    100,
+			getPages:  DBRepo.GetBlockedUsersPages,
+			expResult: RelationPage{Emails: []string{"kate@example.com", "lisa@example.com"}, TotalCount: 2},
+		},
+		"users blocked by nobody": {
+			userId:    103,
+			getPages:  DBRepo.GetBlockedUsersPages,
+			expResult: RelationPage{Emails: []string{}, TotalCount: 0},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			pages, err := tc.getPages(repo, ctx, []int{tc.userId}, pagination.Page{Size: 5})
+			require.NoError(t, err)
+			require.Equal(t, map[int]RelationPage{tc.userId: tc.expResult}, pages)
+		})
+	}
+}
@@ -4,20 +4,55 @@ import (
 	"context"
 
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
 )
 
 // SpecRepo is the interface for repository methods
 type SpecRepo interface {
-	CreateFriend(ctx context.Context, userId int, friendId int) error
+	DeleteFriend(ctx context.Context, userId int, friendId int, change webhooks.Payload) error
 	GetFriendsByID(ctx context.Context, userId int) (models.FriendSlice, error)
 	GetUserBlocksByID(ctx context.Context, userId int) (models.UserBlockSlice, error)
-	CreateSubscription(ctx context.Context, requestorId int, targetId int) error
+	CreateSubscription(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) error
+	DeleteSubscription(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) (int64, error)
 	GetRecipientEmails(ctx context.Context, senderId int) (models.UserSlice, error)
-	CreateUserBlock(ctx context.Context, requestorId int, targetId int) error
+	GetMentionableUsers(ctx context.Context, senderId int, emails []string) (models.UserSlice, error)
+	CreateUpdate(ctx context.Context, senderId int, text string, mentionedEmails []string, notification notify.Message) (int, models.UserSlice, error)
+	CreateUserBlock(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) error
+	DeleteUserBlock(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) (int64, error)
 	IsExistedFriend(ctx context.Context, userId int, friendId int) (bool, error)
 	IsBlockedUser(ctx context.Context, userId int, friendId int) (bool, error)
 	IsSubscribedUser(ctx context.Context, requestorId int, targetId int) (bool, error)
 	GetUserIDByEmail(ctx context.Context, email string) (int, error)
 	GetEmailsByUserIDs(ctx context.Context, userIDs []int) ([]string, error)
 	GetUsers(ctx context.Context) (models.UserSlice, error)
+	CreateUser(ctx context.Context, name string, email string, password string) (int, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	UpdateUser(ctx context.Context, userId int, name string, email string) error
+	DeleteUser(ctx context.Context, userId int) error
+	CreateFriendRequest(ctx context.Context, requestorId int, targetId int) error
+	IsPendingFriendRequest(ctx context.Context, requestorId int, targetId int) (bool, error)
+	UpdateFriendRequestStatus(ctx context.Context, requestorId int, targetId int, status string) (int64, error)
+	AcceptFriendRequest(ctx context.Context, requestorId int, targetId int, notifications []notify.Message, change webhooks.Payload) error
+	GetIncomingFriendRequests(ctx context.Context, targetId int) (models.FriendRequestSlice, error)
+	GetOutgoingFriendRequests(ctx context.Context, requestorId int) (models.FriendRequestSlice, error)
+	GetUsersPage(ctx context.Context, page pagination.Page) (models.UserSlice, error)
+	CountUsers(ctx context.Context) (int64, error)
+	GetFriendsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]RelationPage, error)
+	GetRecipientsPage(ctx context.Context, senderId int, page pagination.Page) (models.UserSlice, error)
+	CountRecipients(ctx context.Context, senderId int) (int64, error)
+	GetRecipientsByEmails(ctx context.Context, senderId int, emails []string) (models.UserSlice, error)
+	GetSubscribersPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]RelationPage, error)
+	GetSubscriptionsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]RelationPage, error)
+	GetBlockedUsersPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]RelationPage, error)
+	GetUsersByEmails(ctx context.Context, emails []string) (models.UserSlice, error)
+	GetUsersByIDs(ctx context.Context, userIds []int) (models.UserSlice, error)
+	GetFriendsByIDs(ctx context.Context, userIds []int) (models.FriendSlice, error)
+	GetUserBlocksByIDs(ctx context.Context, userIds []int) (models.UserBlockSlice, error)
+	CreateWebhook(ctx context.Context, url string, secret string, events []webhooks.Event) (webhooks.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]webhooks.Webhook, error)
+	IsExistedWebhook(ctx context.Context, webhookId int) (bool, error)
+	DeleteWebhook(ctx context.Context, webhookId int) (int64, error)
+	GetWebhookDeliveries(ctx context.Context, webhookId int, limit int) ([]webhooks.DeliveryLog, error)
 }
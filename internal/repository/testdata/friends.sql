@@ -3,14 +3,20 @@ TRUNCATE TABLE users CASCADE;
 TRUNCATE TABLE friends CASCADE;
 TRUNCATE TABLE subscriptions CASCADE;
 TRUNCATE TABLE user_blocks CASCADE;
+TRUNCATE TABLE friend_requests CASCADE;
+TRUNCATE TABLE updates CASCADE;
+TRUNCATE TABLE outbox;
+TRUNCATE TABLE webhooks CASCADE;
 
 
-INSERT INTO users(id, name, email, created_at, updated_at) VALUES
-(100, 'john','john@example.com', now(), now()),
-(101, 'andy','andy@example.com', now(), now()),
-(102, 'common','common@example.com', now(), now()),
-(103, 'lisa','lisa@example.com', now(), now()),
-(104, 'kate','kate@example.com', now(), now());
+INSERT INTO users(id, name, email, created_at, updated_at, password) VALUES
+(100, 'john','john@example.com', now(), now(), '$2a$10$IJHVXhLnbbKxYuQq0mRd8.1xXIVh0.p9u177pyEYJLh8O8U3KcM1.'),
+(101, 'andy','andy@example.com', now(), now(), '$2a$10$IJHVXhLnbbKxYuQq0mRd8.1xXIVh0.p9u177pyEYJLh8O8U3KcM1.'),
+(102, 'common','common@example.com', now(), now(), '$2a$10$IJHVXhLnbbKxYuQq0mRd8.1xXIVh0.p9u177pyEYJLh8O8U3KcM1.'),
+(103, 'lisa','lisa@example.com', now(), now(), '$2a$10$IJHVXhLnbbKxYuQq0mRd8.1xXIVh0.p9u177pyEYJLh8O8U3KcM1.'),
+(104, 'kate','kate@example.com', now(), now(), '$2a$10$IJHVXhLnbbKxYuQq0mRd8.1xXIVh0.p9u177pyEYJLh8O8U3KcM1.');
+
+UPDATE users SET role = 'ADMIN' WHERE id = 102;
 
 INSERT INTO friends(user_id, friend_id) VALUES
 (100, 102),
@@ -22,4 +28,7 @@ INSERT INTO user_blocks(requestor_id, target_id) VALUES (100,104);
 
 INSERT INTO subscriptions(subscription_requestor_id, subscription_target_id) VALUES (101,103);
 
-
+INSERT INTO friend_requests(requestor_id, target_id, status, created_at, updated_at) VALUES
+(101, 104, 'pending', now(), now()),
+(103, 101, 'pending', now(), now()),
+(104, 100, 'declined', now(), now());
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/lib/pq"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+)
+
+// Insert an update of sender into updates table, a delivery to each of its recipients into update_deliveries table
+// and a notification to each of them into outbox table in one transaction. The recipients are the users of
+// recipientsQuery and the mentioned users, the mentioned emails must be mentionable by the sender.
+// The notification is the message of every recipient, its recipient is filled with each of them.
+// Return the id of the update and the delivered users.
+func (_self DBRepo) CreateUpdate(ctx context.Context, senderId int, text string, mentionedEmails []string, notification notify.Message) (int, models.UserSlice, error) {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var updateId int
+	if err := tx.QueryRowContext(ctx,
+		`INSERT INTO updates (sender_id, text, created_at) VALUES ($1, $2, $3) RETURNING id`,
+		senderId, text, now).Scan(&updateId); err != nil {
+		return 0, nil, err
+	}
+
+	query := `WITH delivered AS (
+	        INSERT INTO update_deliveries (update_id, recipient_id)
+	        SELECT $2::integer, recipient.id FROM (
+	            ` + recipientsQuery + `
+	            UNION
+	            SELECT id, email FROM users WHERE email = ANY($3)
+	        ) AS recipient
+	        RETURNING recipient_id
+	    ), recipients AS (
+	        SELECT u.email FROM users u JOIN delivered d ON u.id = d.recipient_id
+	    ), notified AS (
+	        INSERT INTO outbox (kind, recipient, subject, body, status, next_attempt_at, created_at, updated_at)
+	        SELECT $4::varchar, r.email, $5::text, $6::text, $7::varchar, $8::timestamptz, $8::timestamptz, $8::timestamptz
+	        FROM recipients r
+	    )
+	    SELECT email FROM recipients
+	    ORDER BY email`
+
+	recipients := models.UserSlice{}
+	if err := queries.Raw(query, senderId, updateId, pq.Array(mentionedEmails),
+		notification.Kind, notification.Subject, notification.Body, OutboxPending, now).Bind(ctx, tx, &recipients); err != nil {
+		return 0, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, err
+	}
+	return updateId, recipients, nil
+}
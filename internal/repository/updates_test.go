@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_CreateUpdate(t *testing.T) {
+	tcs := map[string]struct {
+		senderId        int
+		mentionedEmails []string
+		expResult       []string
+		expError        error
+	}{
+		"success with friends and subscribers": {
+			senderId:  102,
+			expResult: []string{"andy@example.com", "john@example.com", "lisa@example.com"},
+		},
+		"success with blocked users left out and a mentioned user": {
+			senderId:        100,
+			mentionedEmails: []string{"andy@example.com"},
+			expResult:       []string{"andy@example.com", "common@example.com"},
+		},
+		"success with a mentioned user who is already a recipient": {
+			senderId:        103,
+			mentionedEmails: []string{"andy@example.com"},
+			expResult:       []string{"andy@example.com", "common@example.com"},
+		},
+		"query by an unknown input senderId": {
+			senderId: 99,
+			expError: errors.New("pq: insert or update on table \"updates\" violates foreign key constraint \"updates_sender_id_fkey\""),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			notification := notify.NewUpdatePublished("sender@example.com", "hello")
+			updateId, result, err := repo.CreateUpdate(ctx, tc.senderId, "hello", tc.mentionedEmails, notification)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+				require.Equal(t, 0, countNotifications(t, db))
+				return
+			}
+			require.NoError(t, err)
+
+			emails := make([]string, len(result))
+			for i, user := range result {
+				emails[i] = user.Email
+			}
+			require.Equal(t, tc.expResult, emails)
+
+			var deliveries int
+			err = db.QueryRow(`SELECT COUNT(*) FROM update_deliveries WHERE update_id = $1`, updateId).Scan(&deliveries)
+			require.NoError(t, err)
+			require.Equal(t, len(tc.expResult), deliveries)
+			require.Equal(t, len(tc.expResult), countNotifications(t, db))
+		})
+	}
+}
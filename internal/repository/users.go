@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/lib/pq"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+// Code of the postgres error raised on a unique constraint violation
+const uniqueViolationCode = "unique_violation"
+
+// ErrDuplicatedEmail is returned when the email of a user is already used by another user
+var ErrDuplicatedEmail = errors.New("repository: duplicated email")
+
+// Insert a new record into users table, return the id of the new user
+func (_self DBRepo) CreateUser(ctx context.Context, name string, email string, password string) (int, error) {
+	user := models.User{
+		Name:     name,
+		Email:    email,
+		Password: password,
+	}
+	if err := user.Insert(ctx, _self.Db, boil.Infer()); err != nil {
+		return 0, toUserError(err)
+	}
+	return user.ID, nil
+}
+
+// Get a user from users table by email
+func (_self DBRepo) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return models.Users(models.UserWhere.Email.EQ(email)).One(ctx, _self.Db)
+}
+
+// Get users from users table by emails, unknown emails are left out
+func (_self DBRepo) GetUsersByEmails(ctx context.Context, emails []string) (models.UserSlice, error) {
+	if len(emails) == 0 {
+		return models.UserSlice{}, nil
+	}
+	return models.Users(models.UserWhere.Email.IN(emails)).All(ctx, _self.Db)
+}
+
+// Get users from users table by ids, unknown ids are left out
+func (_self DBRepo) GetUsersByIDs(ctx context.Context, userIds []int) (models.UserSlice, error) {
+	if len(userIds) == 0 {
+		return models.UserSlice{}, nil
+	}
+	return models.Users(models.UserWhere.ID.IN(userIds)).All(ctx, _self.Db)
+}
+
+// Change name and email of a user in users table, an empty value keeps the stored one
+func (_self DBRepo) UpdateUser(ctx context.Context, userId int, name string, email string) error {
+	user, err := models.FindUser(ctx, _self.Db, userId)
+	if err != nil {
+		return err
+	}
+
+	if name != "" {
+		user.Name = name
+	}
+	if email != "" {
+		user.Email = email
+	}
+	if _, err := user.Update(ctx, _self.Db, boil.Whitelist(
+		models.UserColumns.Name,
+		models.UserColumns.Email,
+		models.UserColumns.UpdatedAt)); err != nil {
+		return toUserError(err)
+	}
+	return nil
+}
+
+// Delete a user and all relationships referencing the user in one transaction
+func (_self DBRepo) DeleteUser(ctx context.Context, userId int) error {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := models.Friends(
+		models.FriendWhere.UserID.EQ(userId)).DeleteAll(ctx, tx); err != nil {
+		return err
+	}
+	if _, err := models.Friends(
+		models.FriendWhere.FriendID.EQ(userId)).DeleteAll(ctx, tx); err != nil {
+		return err
+	}
+	if _, err := models.Subscriptions(
+		models.SubscriptionWhere.SubscriptionRequestorID.EQ(userId)).DeleteAll(ctx, tx); err != nil {
+		return err
+	}
+	if _, err := models.Subscriptions(
+		models.SubscriptionWhere.SubscriptionTargetID.EQ(userId)).DeleteAll(ctx, tx); err != nil {
+		return err
+	}
+	if _, err := models.UserBlocks(
+		models.UserBlockWhere.RequestorID.EQ(userId)).DeleteAll(ctx, tx); err != nil {
+		return err
+	}
+	if _, err := models.UserBlocks(
+		models.UserBlockWhere.TargetID.EQ(userId)).DeleteAll(ctx, tx); err != nil {
+		return err
+	}
+	if _, err := models.FriendRequests(
+		models.FriendRequestWhere.RequestorID.EQ(userId)).DeleteAll(ctx, tx); err != nil {
+		return err
+	}
+	if _, err := models.FriendRequests(
+		models.FriendRequestWhere.TargetID.EQ(userId)).DeleteAll(ctx, tx); err != nil {
+		return err
+	}
+
+	deleted, err := models.Users(models.UserWhere.ID.EQ(userId)).DeleteAll(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// Replace the unique violation on users.email by ErrDuplicatedEmail
+func toUserError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == uniqueViolationCode {
+		return ErrDuplicatedEmail
+	}
+	return err
+}
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_CreateUser(t *testing.T) {
+	tcs := map[string]struct {
+		name     string
+		email    string
+		expError error
+	}{
+		"success with adding input of a new user": {
+			name:  "tom",
+			email: "tom@example.com",
+		},
+		"query by an existing email": {
+			name:     "andy",
+			email:    "andy@example.com",
+			expError: ErrDuplicatedEmail,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			userId, err := repo.CreateUser(ctx, tc.name, tc.email, "$2a$10$IJHVXhLnbbKxYuQq0mRd8.1xXIVh0.p9u177pyEYJLh8O8U3KcM1.")
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				user, err := models.FindUser(ctx, db, userId)
+				require.NoError(t, err)
+				require.Equal(t, tc.email, user.Email)
+				require.False(t, user.CreatedAt.IsZero())
+				require.Equal(t, user.CreatedAt, user.UpdatedAt)
+			}
+		})
+	}
+}
+
+func TestRepository_GetUserByEmail(t *testing.T) {
+	tcs := map[string]struct {
+		email     string
+		expResult int
+		expError  error
+	}{
+		"success with input of an existing email": {
+			email:     "andy@example.com",
+			expResult: 101,
+		},
+		"query by an unknown input email": {
+			email:    "test@example.com",
+			expError: sql.ErrNoRows,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			user, err := repo.GetUserByEmail(ctx, tc.email)
+			if tc.expError != nil {
+				require.True(t, errors.Is(err, tc.expError))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, user.ID)
+				require.NotEmpty(t, user.Password)
+			}
+		})
+	}
+}
+
+func TestRepository_UpdateUser(t *testing.T) {
+	tcs := map[string]struct {
+		userId   int
+		name     string
+		email    string
+		expName  string
+		expEmail string
+		expError error
+	}{
+		"success with changing name and email": {
+			userId:   101,
+			name:     "andrew",
+			email:    "andrew@example.com",
+			expName:  "andrew",
+			expEmail: "andrew@example.com",
+		},
+		"success with changing name only": {
+			userId:   101,
+			name:     "andrew",
+			expName:  "andrew",
+			expEmail: "andy@example.com",
+		},
+		"query by an email of another user": {
+			userId:   101,
+			email:    "john@example.com",
+			expError: ErrDuplicatedEmail,
+		},
+		"query by an unknown input userId": {
+			userId:   99,
+			name:     "test",
+			expError: sql.ErrNoRows,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			err = repo.UpdateUser(ctx, tc.userId, tc.name, tc.email)
+			if tc.expError != nil {
+				require.True(t, errors.Is(err, tc.expError))
+			} else {
+				require.NoError(t, err)
+				user, err := models.FindUser(ctx, db, tc.userId)
+				require.NoError(t, err)
+				require.Equal(t, tc.expName, user.Name)
+				require.Equal(t, tc.expEmail, user.Email)
+				require.True(t, user.UpdatedAt.After(user.CreatedAt))
+			}
+		})
+	}
+}
+
+func TestRepository_DeleteUser(t *testing.T) {
+	tcs := map[string]struct {
+		userId   int
+		expError error
+	}{
+		"success with input of a user having relationships": {
+			userId: 101,
+		},
+		"query by an unknown input userId": {
+			userId:   99,
+			expError: sql.ErrNoRows,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			err = repo.DeleteUser(ctx, tc.userId)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				isExisted, err := models.UserExists(ctx, db, tc.userId)
+				require.NoError(t, err)
+				require.False(t, isExisted)
+				friends, err := repo.GetFriendsByID(ctx, tc.userId)
+				require.NoError(t, err)
+				require.Empty(t, friends)
+				isSubscribed, err := repo.IsSubscribedUser(ctx, tc.userId, 103)
+				require.NoError(t, err)
+				require.False(t, isSubscribed)
+				incoming, err := repo.GetIncomingFriendRequests(ctx, tc.userId)
+				require.NoError(t, err)
+				require.Empty(t, incoming)
+			}
+		})
+	}
+}
+
+func TestRepository_GetUsersByEmails(t *testing.T) {
+	tcs := map[string]struct {
+		emails    []string
+		expResult []string
+	}{
+		"known and unknown emails": {
+			emails:    []string{"kate@example.com", "test@example.com", "andy@example.com"},
+			expResult: []string{"andy@example.com", "kate@example.com"},
+		},
+		"no emails": {
+			emails:    []string{},
+			expResult: []string{},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			users, err := repo.GetUsersByEmails(ctx, tc.emails)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.expResult, emailsOf(users))
+		})
+	}
+}
+
+func TestRepository_GetUsersByIDs(t *testing.T) {
+	tcs := map[string]struct {
+		userIds   []int
+		expResult []string
+	}{
+		"known and unknown ids": {
+			userIds:   []int{104, 99, 101},
+			expResult: []string{"andy@example.com", "kate@example.com"},
+		},
+		"no ids": {
+			userIds:   []int{},
+			expResult: []string{},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			users, err := repo.GetUsersByIDs(ctx, tc.userIds)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.expResult, emailsOf(users))
+		})
+	}
+}
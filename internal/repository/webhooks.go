@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+	"github.com/lib/pq"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+// Insert a webhook into webhooks table, an empty list of events receives every event
+func (_self DBRepo) CreateWebhook(ctx context.Context, url string, secret string, events []webhooks.Event) (webhooks.Webhook, error) {
+	webhook := webhooks.Webhook{URL: url, Secret: secret, Events: events, CreatedAt: time.Now()}
+	if err := _self.Db.QueryRowContext(ctx,
+		`INSERT INTO webhooks (url, secret, events, created_at, updated_at) VALUES ($1, $2, $3, $4, $4) RETURNING id`,
+		url, secret, pq.Array(eventNames(events)), webhook.CreatedAt).Scan(&webhook.ID); err != nil {
+		return webhooks.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// Get all webhooks of webhooks table, the oldest first
+func (_self DBRepo) GetWebhooks(ctx context.Context) ([]webhooks.Webhook, error) {
+	rows, err := _self.Db.QueryContext(ctx, `SELECT id, url, secret, events, created_at FROM webhooks ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]webhooks.Webhook, 0)
+	for rows.Next() {
+		var webhook webhooks.Webhook
+		var events []string
+		if err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, pq.Array(&events), &webhook.CreatedAt); err != nil {
+			return nil, err
+		}
+		webhook.Events = make([]webhooks.Event, len(events))
+		for i, event := range events {
+			webhook.Events[i] = webhooks.Event(event)
+		}
+		result = append(result, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Check a webhook exists in webhooks table
+func (_self DBRepo) IsExistedWebhook(ctx context.Context, webhookId int) (bool, error) {
+	var existed bool
+	err := _self.Db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM webhooks WHERE id = $1)`, webhookId).Scan(&existed)
+	return existed, err
+}
+
+// Delete a webhook from webhooks table with its deliveries, return the number of deleted webhooks
+func (_self DBRepo) DeleteWebhook(ctx context.Context, webhookId int) (int64, error) {
+	result, err := _self.Db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, webhookId)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// insertWebhookDeliveries inserts a pending delivery of a change into webhook_deliveries table for each webhook
+// whose events have it, they are due at once. It runs in the transaction of the change, so a change is never
+// stored without its deliveries.
+func insertWebhookDeliveries(ctx context.Context, exec boil.ContextExecutor, change webhooks.Payload) error {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+
+	_, err = exec.ExecContext(ctx,
+		`INSERT INTO webhook_deliveries (webhook_id, event, payload, status, next_attempt_at, created_at, updated_at)
+		SELECT id, $1::varchar, $2::text, $3::varchar, $4::timestamptz, $4::timestamptz, $4::timestamptz
+		FROM webhooks
+		WHERE cardinality(events) = 0 OR $1::varchar = ANY(events)`,
+		change.Event, string(payload), webhooks.StatusPending, time.Now())
+	return err
+}
+
+// Claim the pending deliveries of webhook_deliveries table which are due at now, the oldest first. Each claimed
+// delivery gets one more attempt and is not due again before now + lease. Concurrent dispatchers claim different
+// deliveries.
+func (_self DBRepo) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]webhooks.Delivery, error) {
+	query := `WITH claimed AS (
+	        UPDATE webhook_deliveries SET attempts = attempts + 1, next_attempt_at = $2, updated_at = $1
+	        WHERE id IN (
+	            SELECT id FROM webhook_deliveries
+	            WHERE status = $3 AND next_attempt_at <= $1
+	            ORDER BY id
+	            LIMIT $4
+	            FOR UPDATE SKIP LOCKED
+	        )
+	        RETURNING id, webhook_id, event, payload, attempts
+	    )
+	    SELECT c.id, c.webhook_id, c.event, c.payload, c.attempts, w.url, w.secret
+	    FROM claimed c JOIN webhooks w ON w.id = c.webhook_id
+	    ORDER BY c.id`
+
+	rows, err := _self.Db.QueryContext(ctx, query, now, now.Add(lease), webhooks.StatusPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]webhooks.Delivery, 0)
+	for rows.Next() {
+		var delivery webhooks.Delivery
+		if err := rows.Scan(&delivery.ID, &delivery.WebhookID, &delivery.Event, &delivery.Payload,
+			&delivery.Attempts, &delivery.URL, &delivery.Secret); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Mark a delivery of webhook_deliveries table as delivered with the status answered by its webhook,
+// the error of a previous attempt is kept
+func (_self DBRepo) MarkWebhookDelivered(ctx context.Context, id int, responseStatus int) error {
+	return _self.setWebhookDeliveryStatus(ctx, id, webhooks.StatusDelivered, time.Now(), responseStatus, sql.NullString{})
+}
+
+// Keep a delivery of webhook_deliveries table pending until nextAttemptAt with the result of its last attempt
+func (_self DBRepo) RetryWebhookDelivery(ctx context.Context, id int, nextAttemptAt time.Time, responseStatus int, lastError string) error {
+	return _self.setWebhookDeliveryStatus(ctx, id, webhooks.StatusPending, nextAttemptAt, responseStatus,
+		sql.NullString{String: lastError, Valid: true})
+}
+
+// Mark a delivery of webhook_deliveries table as dead with the result of its last attempt, it is not attempted anymore
+func (_self DBRepo) DeadLetterWebhookDelivery(ctx context.Context, id int, responseStatus int, lastError string) error {
+	return _self.setWebhookDeliveryStatus(ctx, id, webhooks.StatusDead, time.Now(), responseStatus,
+		sql.NullString{String: lastError, Valid: true})
+}
+
+// Get up to limit deliveries of a webhook from webhook_deliveries table, the newest first
+func (_self DBRepo) GetWebhookDeliveries(ctx context.Context, webhookId int, limit int) ([]webhooks.DeliveryLog, error) {
+	rows, err := _self.Db.QueryContext(ctx,
+		`SELECT id, webhook_id, event, payload, status, attempts, response_status, last_error,
+		next_attempt_at, created_at, updated_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY id DESC
+		LIMIT $2`, webhookId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]webhooks.DeliveryLog, 0)
+	for rows.Next() {
+		var delivery webhooks.DeliveryLog
+		var responseStatus sql.NullInt64
+		var lastError sql.NullString
+		if err := rows.Scan(&delivery.ID, &delivery.WebhookID, &delivery.Event, &delivery.Payload, &delivery.Status,
+			&delivery.Attempts, &responseStatus, &lastError, &delivery.NextAttemptAt, &delivery.CreatedAt,
+			&delivery.UpdatedAt); err != nil {
+			return nil, err
+		}
+		delivery.ResponseStatus = int(responseStatus.Int64)
+		delivery.LastError = lastError.String
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// responseStatus is the answer of the last attempt, 0 is stored as NULL when the endpoint did not answer
+func (_self DBRepo) setWebhookDeliveryStatus(ctx context.Context, id int, status string, nextAttemptAt time.Time, responseStatus int, lastError sql.NullString) error {
+	result, err := _self.Db.ExecContext(ctx,
+		`UPDATE webhook_deliveries SET status = $2, next_attempt_at = $3, response_status = $4,
+		last_error = COALESCE($5, last_error), updated_at = $6 WHERE id = $1`,
+		id, status, nextAttemptAt, sql.NullInt64{Int64: int64(responseStatus), Valid: responseStatus != 0}, lastError, time.Now())
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Names of the events as stored in webhooks table
+func eventNames(events []webhooks.Event) []string {
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = string(event)
+	}
+	return names
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+	"github.com/stretchr/testify/require"
+)
+
+// testChange is a change of andy about john at a fixed time
+func testChange(event webhooks.Event) webhooks.Payload {
+	return webhooks.Payload{
+		Event:      event,
+		Requestor:  "andy@example.com",
+		Target:     "john@example.com",
+		OccurredAt: time.Date(2022, 1, 23, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+// countWebhookDeliveries is the number of deliveries of webhook_deliveries table
+func countWebhookDeliveries(t *testing.T, db *sql.DB) int {
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM webhook_deliveries`).Scan(&count))
+	return count
+}
+
+func TestRepository_Webhooks(t *testing.T) {
+	ctx := context.Background()
+	db, err := config.NewDatabase()
+	require.NoError(t, err)
+	repo := NewDBRepo(db)
+
+	// load testdata
+	loadSqlTestFile(t, db, "testdata/friends.sql")
+
+	crm, err := repo.CreateWebhook(ctx, "https://crm.example.com/hooks", "crm-secret-0123456789",
+		[]webhooks.Event{webhooks.FriendAdded, webhooks.FriendRemoved})
+	require.NoError(t, err)
+	analytics, err := repo.CreateWebhook(ctx, "https://analytics.example.com/hooks", "analytics-secret-0123", []webhooks.Event{})
+	require.NoError(t, err)
+
+	result, err := repo.GetWebhooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, crm.ID, result[0].ID)
+	require.Equal(t, "https://crm.example.com/hooks", result[0].URL)
+	require.Equal(t, "crm-secret-0123456789", result[0].Secret)
+	require.Equal(t, []webhooks.Event{webhooks.FriendAdded, webhooks.FriendRemoved}, result[0].Events)
+	require.Equal(t, []webhooks.Event{}, result[1].Events)
+
+	existed, err := repo.IsExistedWebhook(ctx, analytics.ID)
+	require.NoError(t, err)
+	require.True(t, existed)
+
+	// The deliveries of a webhook are removed with it
+	require.NoError(t, insertWebhookDeliveries(ctx, db, testChange(webhooks.FriendAdded)))
+	require.Equal(t, 2, countWebhookDeliveries(t, db))
+
+	deleted, err := repo.DeleteWebhook(ctx, crm.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+	deleted, err = repo.DeleteWebhook(ctx, crm.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), deleted)
+
+	existed, err = repo.IsExistedWebhook(ctx, crm.ID)
+	require.NoError(t, err)
+	require.False(t, existed)
+	deliveries, err := repo.GetWebhookDeliveries(ctx, crm.ID, 10)
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
+
+func TestRepository_InsertWebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+	db, err := config.NewDatabase()
+	require.NoError(t, err)
+	repo := NewDBRepo(db)
+
+	// load testdata
+	loadSqlTestFile(t, db, "testdata/friends.sql")
+	crm, err := repo.CreateWebhook(ctx, "https://crm.example.com/hooks", "crm-secret-0123456789",
+		[]webhooks.Event{webhooks.FriendAdded})
+	require.NoError(t, err)
+	analytics, err := repo.CreateWebhook(ctx, "https://analytics.example.com/hooks", "analytics-secret-0123", []webhooks.Event{})
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		event    webhooks.Event
+		expCount int
+	}{
+		"event of both webhooks": {
+			event:    webhooks.FriendAdded,
+			expCount: 2,
+		},
+		"event out of the filter of a webhook": {
+			event:    webhooks.BlockCreated,
+			expCount: 1,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			before := countWebhookDeliveries(t, db)
+			require.NoError(t, insertWebhookDeliveries(ctx, db, testChange(tc.event)))
+			require.Equal(t, tc.expCount, countWebhookDeliveries(t, db)-before)
+		})
+	}
+
+	deliveries, err := repo.GetWebhookDeliveries(ctx, analytics.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	// The newest first
+	require.Equal(t, webhooks.BlockCreated, deliveries[0].Event)
+	require.Equal(t, webhooks.FriendAdded, deliveries[1].Event)
+	require.Equal(t, webhooks.StatusPending, deliveries[1].Status)
+	require.Equal(t, `{"event":"friend.added","requestor":"andy@example.com","target":"john@example.com","occurred_at":"2022-01-23T09:00:00Z"}`,
+		deliveries[1].Payload)
+	require.Equal(t, 0, deliveries[1].Attempts)
+
+	deliveries, err = repo.GetWebhookDeliveries(ctx, crm.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, webhooks.FriendAdded, deliveries[0].Event)
+}
+
+func TestRepository_WebhookDeliveriesOfChanges(t *testing.T) {
+	// An event too long for the event column makes the insert of the deliveries fail
+	failing := webhooks.Event("subscription.created.with.a.too.long.name")
+	createSubscription := func(repo DBRepo, ctx context.Context, requestorId int, targetId int, event webhooks.Event) error {
+		return repo.CreateSubscription(ctx, requestorId, targetId, testChange(event))
+	}
+	deleteSubscription := func(repo DBRepo, ctx context.Context, requestorId int, targetId int, event webhooks.Event) error {
+		_, err := repo.DeleteSubscription(ctx, requestorId, targetId, testChange(event))
+		return err
+	}
+
+	tcs := map[string]struct {
+		write         func(repo DBRepo, ctx context.Context, requestorId int, targetId int, event webhooks.Event) error
+		requestorId   int
+		targetId      int
+		event         webhooks.Event
+		expDeliveries int
+		expSubscribed bool
+		expError      error
+	}{
+		"new subscription with its delivery": {
+			write:         createSubscription,
+			requestorId:   102,
+			targetId:      103,
+			event:         webhooks.SubscriptionCreated,
+			expDeliveries: 1,
+			expSubscribed: true,
+		},
+		"new subscription rolled back with its failed delivery": {
+			write:       createSubscription,
+			requestorId: 102,
+			targetId:    103,
+			event:       failing,
+			expError:    errors.New("pq: value too long for type character varying(30)"),
+		},
+		"removed subscription with its delivery": {
+			write:         deleteSubscription,
+			requestorId:   101,
+			targetId:      103,
+			event:         webhooks.SubscriptionRemoved,
+			expDeliveries: 1,
+		},
+		"removed subscription rolled back with its failed delivery": {
+			write:         deleteSubscription,
+			requestorId:   101,
+			targetId:      103,
+			event:         failing,
+			expSubscribed: true,
+			expError:      errors.New("pq: value too long for type character varying(30)"),
+		},
+		"missing subscription without delivery": {
+			write:       deleteSubscription,
+			requestorId: 103,
+			targetId:    101,
+			event:       webhooks.SubscriptionRemoved,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := config.NewDatabase()
+			require.NoError(t, err)
+			repo := NewDBRepo(db)
+
+			// load testdata, the webhook receives every event
+			loadSqlTestFile(t, db, "testdata/friends.sql")
+			_, err = repo.CreateWebhook(ctx, "https://crm.example.com/hooks", "crm-secret-0123456789", []webhooks.Event{})
+			require.NoError(t, err)
+
+			err = tc.write(repo, ctx, tc.requestorId, tc.targetId, tc.event)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expDeliveries, countWebhookDeliveries(t, db))
+			var subscribed bool
+			require.NoError(t, db.QueryRow(`SELECT EXISTS(SELECT 1 FROM subscriptions
+				WHERE subscription_requestor_id = $1 AND subscription_target_id = $2)`, tc.requestorId, tc.targetId).Scan(&subscribed))
+			require.Equal(t, tc.expSubscribed, subscribed)
+		})
+	}
+}
+
+func TestRepository_WebhookDeliveryStatus(t *testing.T) {
+	ctx := context.Background()
+	db, err := config.NewDatabase()
+	require.NoError(t, err)
+	repo := NewDBRepo(db)
+
+	// load testdata
+	loadSqlTestFile(t, db, "testdata/friends.sql")
+	crm, err := repo.CreateWebhook(ctx, "https://crm.example.com/hooks", "crm-secret-0123456789", []webhooks.Event{})
+	require.NoError(t, err)
+	require.NoError(t, insertWebhookDeliveries(ctx, db, testChange(webhooks.FriendAdded)))
+	require.NoError(t, insertWebhookDeliveries(ctx, db, testChange(webhooks.BlockCreated)))
+	now := time.Now()
+
+	// The claimed deliveries carry the URL and the secret of their webhook
+	claimed, err := repo.ClaimWebhookDeliveries(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	require.Equal(t, crm.ID, claimed[0].WebhookID)
+	require.Equal(t, webhooks.FriendAdded, claimed[0].Event)
+	require.Equal(t, "https://crm.example.com/hooks", claimed[0].URL)
+	require.Equal(t, "crm-secret-0123456789", claimed[0].Secret)
+	require.Equal(t, 1, claimed[0].Attempts)
+	deliveredID, deadID := claimed[0].ID, claimed[1].ID
+
+	// Claimed deliveries are left out until their lease ends
+	others, err := repo.ClaimWebhookDeliveries(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Empty(t, others)
+
+	require.NoError(t, repo.RetryWebhookDelivery(ctx, deliveredID, now.Add(time.Hour), 503, "endpoint answered 503"))
+	claimed, err = repo.ClaimWebhookDeliveries(ctx, now.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, deadID, claimed[0].ID)
+
+	require.NoError(t, repo.MarkWebhookDelivered(ctx, deliveredID, 204))
+	require.NoError(t, repo.DeadLetterWebhookDelivery(ctx, deadID, 0, "connection refused"))
+
+	deliveries, err := repo.GetWebhookDeliveries(ctx, crm.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	require.Equal(t, webhooks.StatusDead, deliveries[0].Status)
+	require.Equal(t, 2, deliveries[0].Attempts)
+	require.Equal(t, 0, deliveries[0].ResponseStatus)
+	require.Equal(t, "connection refused", deliveries[0].LastError)
+	require.Equal(t, webhooks.StatusDelivered, deliveries[1].Status)
+	require.Equal(t, 1, deliveries[1].Attempts)
+	require.Equal(t, 204, deliveries[1].ResponseStatus)
+	require.Equal(t, "endpoint answered 503", deliveries[1].LastError)
+
+	// The log is limited to the newest deliveries
+	deliveries, err = repo.GetWebhookDeliveries(ctx, crm.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, deadID, deliveries[0].ID)
+
+	// Delivered and dead deliveries are never claimed again
+	claimed, err = repo.ClaimWebhookDeliveries(ctx, now.Add(2*time.Hour), time.Minute, 10)
+	require.NoError(t, err)
+	require.Empty(t, claimed)
+}
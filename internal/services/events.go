@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/events"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+)
+
+// publish sends the event of a successful write, a service without publisher sends nothing
+func (_self FriendService) publish(ctx context.Context, event events.Event) {
+	if _self.Events != nil {
+		_self.Events.Publish(ctx, event)
+	}
+}
+
+// publishFriendship sends a friendship event to both friends
+func (_self FriendService) publishFriendship(ctx context.Context, kind events.Kind, userEmail string, friendEmail string) {
+	_self.publish(ctx, events.Event{Kind: kind, Email: userEmail, Friend: friendEmail})
+	_self.publish(ctx, events.Event{Kind: kind, Email: friendEmail, Friend: userEmail})
+}
+
+// publishBlock sends a block to both parties, their lists leave each other out from now on
+func (_self FriendService) publishBlock(ctx context.Context, requestorEmail string, targetEmail string) {
+	for _, email := range []string{requestorEmail, targetEmail} {
+		_self.publish(ctx, events.Event{Kind: events.Blocked, Email: email, Requestor: requestorEmail, Target: targetEmail})
+	}
+}
+
+// publishUpdate sends an update of sender to each of its recipients
+func (_self FriendService) publishUpdate(ctx context.Context, senderEmail string, text string, recipients []string) {
+	for _, email := range recipients {
+		_self.publish(ctx, events.Event{Kind: events.UpdatePublished, Email: email, Sender: senderEmail, Text: text})
+	}
+}
+
+// friendshipNotifications tell both friends about a new friendship, they are stored with the friendship
+func friendshipNotifications(userEmail string, friendEmail string) []notify.Message {
+	return []notify.Message{
+		notify.NewFriendAdded(userEmail, friendEmail),
+		notify.NewFriendAdded(friendEmail, userEmail),
+	}
+}
+
+// webhookChange is a relationship change made by requestor about target, the repository stores its webhook
+// deliveries in the transaction of the change
+func webhookChange(event webhooks.Event, requestorEmail string, targetEmail string) webhooks.Payload {
+	return webhooks.Payload{
+		Event:      event,
+		Requestor:  requestorEmail,
+		Target:     targetEmail,
+		OccurredAt: time.Now().UTC(),
+	}
+}
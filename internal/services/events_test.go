@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/events"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordPublisher keeps the published events in their order
+type recordPublisher struct {
+	events *[]events.Event
+}
+
+func (p recordPublisher) Publish(ctx context.Context, event events.Event) {
+	*p.events = append(*p.events, event)
+}
+
+func TestServices_PublishEvents(t *testing.T) {
+	tcs := map[string]struct {
+		mockCalls func(mockRepo *SpecRepo) []*mock.Call
+		write     func(service FriendService, ctx context.Context) error
+		expEvents []events.Event
+		expError  error
+	}{
+		"an accepted friend request is sent to both friends": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("IsPendingFriendRequest", mock.Anything, 100, 101).Return(true, nil),
+					mockRepo.On("IsExistedFriend", mock.Anything, 100, 101).Return(false, nil),
+					mockRepo.On("IsBlockedUser", mock.Anything, 100, 101).Return(false, nil),
+					mockRepo.On("AcceptFriendRequest", mock.Anything, 100, 101,
+						friendshipNotifications("john@example.com", "andy@example.com"), mock.Anything).Return(nil),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				return service.AcceptFriendRequest(ctx, "john@example.com", "andy@example.com")
+			},
+			expEvents: []events.Event{
+				{Kind: events.FriendAdded, Email: "john@example.com", Friend: "andy@example.com"},
+				{Kind: events.FriendAdded, Email: "andy@example.com", Friend: "john@example.com"},
+			},
+		},
+		"a removed friendship is sent to both friends": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("IsExistedFriend", mock.Anything, 100, 101).Return(true, nil),
+					mockRepo.On("DeleteFriend", mock.Anything, 100, 101, mock.Anything).Return(nil),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				return service.RemoveFriend(ctx, "john@example.com", "andy@example.com")
+			},
+			expEvents: []events.Event{
+				{Kind: events.FriendRemoved, Email: "john@example.com", Friend: "andy@example.com"},
+				{Kind: events.FriendRemoved, Email: "andy@example.com", Friend: "john@example.com"},
+			},
+		},
+		"a block is sent to both parties": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("IsBlockedUser", mock.Anything, 100, 101).Return(false, nil),
+					mockRepo.On("CreateUserBlock", mock.Anything, 100, 101, mock.Anything).Return(nil),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				return service.CreateUserBlock(ctx, "john@example.com", "andy@example.com")
+			},
+			expEvents: []events.Event{
+				{Kind: events.Blocked, Email: "john@example.com", Requestor: "john@example.com", Target: "andy@example.com"},
+				{Kind: events.Blocked, Email: "andy@example.com", Requestor: "john@example.com", Target: "andy@example.com"},
+			},
+		},
+		"a recipients lookup is not sent": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("GetRecipientEmails", mock.Anything, 100).Return(models.UserSlice{{Email: "andy@example.com"}}, nil),
+					mockRepo.On("GetMentionableUsers", mock.Anything, 100, []string{"kate@example.com"}).
+						Return(models.UserSlice{{Email: "kate@example.com"}}, nil),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				_, err := service.GetRecipientEmails(ctx, "john@example.com", "hello kate@example.com")
+				return err
+			},
+			expEvents: []events.Event{},
+		},
+		"a published update is sent to each delivered recipient": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("GetMentionableUsers", mock.Anything, 100, []string{"kate@example.com"}).
+						Return(models.UserSlice{{Email: "kate@example.com"}}, nil),
+					mockRepo.On("CreateUpdate", mock.Anything, 100, "hello kate@example.com", []string{"kate@example.com"},
+						notify.NewUpdatePublished("john@example.com", "hello kate@example.com")).
+						Return(7, models.UserSlice{{Email: "andy@example.com"}, {Email: "kate@example.com"}}, nil),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				_, err := service.PublishUpdate(ctx, "john@example.com", "hello kate@example.com")
+				return err
+			},
+			expEvents: []events.Event{
+				{Kind: events.UpdatePublished, Email: "andy@example.com", Sender: "john@example.com", Text: "hello kate@example.com"},
+				{Kind: events.UpdatePublished, Email: "kate@example.com", Sender: "john@example.com", Text: "hello kate@example.com"},
+			},
+		},
+		"a failed write is not sent": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("IsPendingFriendRequest", mock.Anything, 100, 101).Return(true, nil),
+					mockRepo.On("IsExistedFriend", mock.Anything, 100, 101).Return(false, nil),
+					mockRepo.On("IsBlockedUser", mock.Anything, 100, 101).Return(false, nil),
+					mockRepo.On("AcceptFriendRequest", mock.Anything, 100, 101, mock.Anything, mock.Anything).Return(errors.New("connection refused")),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				return service.AcceptFriendRequest(ctx, "john@example.com", "andy@example.com")
+			},
+			expEvents: []events.Event{},
+			expError:  errs.NewUnavailableError(errors.New("connection refused")),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = append([]*mock.Call{
+				mockRepo.On("GetUserIDByEmail", "john@example.com").Return(100, nil),
+				mockRepo.On("GetUserIDByEmail", "andy@example.com").Return(101, nil),
+			}, tc.mockCalls(&mockRepo)...)
+
+			published := []events.Event{}
+			friendService := NewFriendService(mockRepo, nil, recordPublisher{events: &published})
+			err := tc.write(friendService, ctx)
+			require.Equal(t, tc.expError, err)
+			require.Equal(t, tc.expEvents, published)
+		})
+	}
+}
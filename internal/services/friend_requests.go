@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/events"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+)
+
+// Send a friend request from requestor to target, the friendship is created once target accepts it
+func (_self FriendService) SendFriendRequest(ctx context.Context, requestorEmail string, targetEmail string) error {
+	// Get requestor id and user target id from repository
+	requestorId, err := _self.Repo.GetUserIDByEmail(ctx, requestorEmail)
+	if err != nil {
+		return errs.NewUserLookupError(requestorEmail, err)
+	}
+	targetId, err := _self.Repo.GetUserIDByEmail(ctx, targetEmail)
+	if err != nil {
+		return errs.NewUserLookupError(targetEmail, err)
+	}
+
+	if err := _self.checkNewFriendship(ctx, requestorId, targetId); err != nil {
+		return err
+	}
+
+	// Check pending friend request in both directions
+	isPending, err := _self.Repo.IsPendingFriendRequest(ctx, requestorId, targetId)
+	if err != nil {
+		return errs.NewUnavailableError(err)
+	}
+	if !isPending {
+		isPending, err = _self.Repo.IsPendingFriendRequest(ctx, targetId, requestorId)
+		if err != nil {
+			return errs.NewUnavailableError(err)
+		}
+	}
+	if isPending {
+		return errs.NewConflictError(errs.CodeFriendRequestExists, errs.MsgExistedFriendRequest)
+	}
+
+	if err := _self.Repo.CreateFriendRequest(ctx, requestorId, targetId); err != nil {
+		return errs.NewUnavailableError(err)
+	}
+
+	return nil
+}
+
+// Accept a pending friend request of requestor, it creates the friendship with target
+func (_self FriendService) AcceptFriendRequest(ctx context.Context, requestorEmail string, targetEmail string) error {
+	// Get requestor id and user target id from repository
+	requestorId, err := _self.Repo.GetUserIDByEmail(ctx, requestorEmail)
+	if err != nil {
+		return errs.NewUserLookupError(requestorEmail, err)
+	}
+	targetId, err := _self.Repo.GetUserIDByEmail(ctx, targetEmail)
+	if err != nil {
+		return errs.NewUserLookupError(targetEmail, err)
+	}
+
+	isPending, err := _self.Repo.IsPendingFriendRequest(ctx, requestorId, targetId)
+	if err != nil {
+		return errs.NewUnavailableError(err)
+	}
+	if !isPending {
+		return errs.NewNotFoundError(errs.CodeFriendRequestNotFound, errs.MsgNotExistedFriendRequest)
+	}
+
+	// The users may have become friends or blocked each other since the request was sent
+	if err := _self.checkNewFriendship(ctx, requestorId, targetId); err != nil {
+		return err
+	}
+
+	change := webhookChange(webhooks.FriendAdded, requestorEmail, targetEmail)
+	if err := _self.Repo.AcceptFriendRequest(ctx, requestorId, targetId, friendshipNotifications(requestorEmail, targetEmail), change); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.NewNotFoundError(errs.CodeFriendRequestNotFound, errs.MsgNotExistedFriendRequest)
+		}
+		return errs.NewUnavailableError(err)
+	}
+
+	_self.publishFriendship(ctx, events.FriendAdded, requestorEmail, targetEmail)
+	return nil
+}
+
+// Decline a pending friend request of requestor, it is done by target
+func (_self FriendService) DeclineFriendRequest(ctx context.Context, requestorEmail string, targetEmail string) error {
+	return _self.closeFriendRequest(ctx, requestorEmail, targetEmail, repository.FriendRequestDeclined)
+}
+
+// Cancel a pending friend request to target, it is done by requestor
+func (_self FriendService) CancelFriendRequest(ctx context.Context, requestorEmail string, targetEmail string) error {
+	return _self.closeFriendRequest(ctx, requestorEmail, targetEmail, repository.FriendRequestCancelled)
+}
+
+// Get emails of users who sent a pending friend request to user
+func (_self FriendService) GetIncomingFriendRequests(ctx context.Context, userEmail string) ([]string, error) {
+	// Get user id from an email
+	userId, err := _self.Repo.GetUserIDByEmail(ctx, userEmail)
+	if err != nil {
+		return nil, errs.NewUserLookupError(userEmail, err)
+	}
+
+	friendRequests, err := _self.Repo.GetIncomingFriendRequests(ctx, userId)
+	if err != nil {
+		return nil, errs.NewUnavailableError(err)
+	}
+	requestorIds := make([]int, 0)
+	for _, friendRequest := range friendRequests {
+		requestorIds = append(requestorIds, friendRequest.RequestorID)
+	}
+
+	emails, err := _self.Repo.GetEmailsByUserIDs(ctx, requestorIds)
+	if err != nil {
+		return nil, errs.NewUnavailableError(err)
+	}
+
+	return emails, nil
+}
+
+// Get emails of users whom user sent a pending friend request to
+func (_self FriendService) GetOutgoingFriendRequests(ctx context.Context, userEmail string) ([]string, error) {
+	// Get user id from an email
+	userId, err := _self.Repo.GetUserIDByEmail(ctx, userEmail)
+	if err != nil {
+		return nil, errs.NewUserLookupError(userEmail, err)
+	}
+
+	friendRequests, err := _self.Repo.GetOutgoingFriendRequests(ctx, userId)
+	if err != nil {
+		return nil, errs.NewUnavailableError(err)
+	}
+	targetIds := make([]int, 0)
+	for _, friendRequest := range friendRequests {
+		targetIds = append(targetIds, friendRequest.TargetID)
+	}
+
+	emails, err := _self.Repo.GetEmailsByUserIDs(ctx, targetIds)
+	if err != nil {
+		return nil, errs.NewUnavailableError(err)
+	}
+
+	return emails, nil
+}
+
+// Move a pending friend request from requestor to target into a closed status
+func (_self FriendService) closeFriendRequest(ctx context.Context, requestorEmail string, targetEmail string, status string) error {
+	// Get requestor id and user target id from repository
+	requestorId, err := _self.Repo.GetUserIDByEmail(ctx, requestorEmail)
+	if err != nil {
+		return errs.NewUserLookupError(requestorEmail, err)
+	}
+	targetId, err := _self.Repo.GetUserIDByEmail(ctx, targetEmail)
+	if err != nil {
+		return errs.NewUserLookupError(targetEmail, err)
+	}
+
+	updated, err := _self.Repo.UpdateFriendRequestStatus(ctx, requestorId, targetId, status)
+	if err != nil {
+		return errs.NewUnavailableError(err)
+	}
+	if updated == 0 {
+		return errs.NewNotFoundError(errs.CodeFriendRequestNotFound, errs.MsgNotExistedFriendRequest)
+	}
+
+	return nil
+}
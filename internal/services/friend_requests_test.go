@@ -0,0 +1,488 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServices_SendFriendRequest(t *testing.T) {
+	type mockGetUserID struct {
+		result int
+		err    error
+	}
+	type mockIsRelated struct {
+		result bool
+		err    error
+	}
+
+	tcs := map[string]struct {
+		requestorEmail   string
+		targetEmail      string
+		firstUser        mockGetUserID
+		secondUser       mockGetUserID
+		isExistedFriend  mockIsRelated
+		isBlockedUser    mockIsRelated
+		isPendingRequest mockIsRelated
+		isPendingReverse mockIsRelated
+		expError         error
+	}{
+		"success with an input": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "john@example.com",
+			firstUser: mockGetUserID{
+				result: 101,
+			},
+			secondUser: mockGetUserID{
+				result: 100,
+			},
+		},
+		"failed with an unknow format input of requestor": {
+			requestorEmail: "test@example.com",
+			targetEmail:    "john@example.com",
+			firstUser: mockGetUserID{
+				err: sql.ErrNoRows,
+			},
+			expError: errors.New(`test@example.com is not exists`),
+		},
+		"failed with an friendship is existing": {
+			requestorEmail: "john@example.com",
+			targetEmail:    "common@example.com",
+			firstUser: mockGetUserID{
+				result: 100,
+			},
+			secondUser: mockGetUserID{
+				result: 102,
+			},
+			isExistedFriend: mockIsRelated{
+				result: true,
+			},
+			expError: errors.New(`The friend relationship has been existed`),
+		},
+		"failed with an blocking relationship is existing": {
+			requestorEmail: "john@example.com",
+			targetEmail:    "lisa@example.com",
+			firstUser: mockGetUserID{
+				result: 100,
+			},
+			secondUser: mockGetUserID{
+				result: 103,
+			},
+			isBlockedUser: mockIsRelated{
+				result: true,
+			},
+			expError: errors.New(`The users have blocked each other`),
+		},
+		"failed with an pending request is existing": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "kate@example.com",
+			firstUser: mockGetUserID{
+				result: 101,
+			},
+			secondUser: mockGetUserID{
+				result: 104,
+			},
+			isPendingRequest: mockIsRelated{
+				result: true,
+			},
+			expError: errors.New(`The pending friend request has been existed`),
+		},
+		"failed with an pending request of target is existing": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "lisa@example.com",
+			firstUser: mockGetUserID{
+				result: 101,
+			},
+			secondUser: mockGetUserID{
+				result: 103,
+			},
+			isPendingReverse: mockIsRelated{
+				result: true,
+			},
+			expError: errors.New(`The pending friend request has been existed`),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(tc.firstUser.result, tc.firstUser.err).Once(),
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(tc.secondUser.result, tc.secondUser.err),
+				mockRepo.On("IsExistedFriend", mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.isExistedFriend.result, tc.isExistedFriend.err),
+				mockRepo.On("IsBlockedUser", mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.isBlockedUser.result, tc.isBlockedUser.err),
+				mockRepo.On("IsPendingFriendRequest", mock.Anything, tc.firstUser.result, tc.secondUser.result).
+					Return(tc.isPendingRequest.result, tc.isPendingRequest.err),
+				mockRepo.On("IsPendingFriendRequest", mock.Anything, tc.secondUser.result, tc.firstUser.result).
+					Return(tc.isPendingReverse.result, tc.isPendingReverse.err),
+				mockRepo.On("CreateFriendRequest", mock.Anything, mock.Anything, mock.Anything).
+					Return(nil),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			err := friendService.SendFriendRequest(ctx, tc.requestorEmail, tc.targetEmail)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestServices_AcceptFriendRequest(t *testing.T) {
+	type mockGetUserID struct {
+		result int
+		err    error
+	}
+	type mockIsRelated struct {
+		result bool
+		err    error
+	}
+
+	tcs := map[string]struct {
+		requestorEmail   string
+		targetEmail      string
+		firstUser        mockGetUserID
+		secondUser       mockGetUserID
+		isPendingRequest mockIsRelated
+		isExistedFriend  mockIsRelated
+		isBlockedUser    mockIsRelated
+		acceptErr        error
+		expError         error
+	}{
+		"success with an input": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "kate@example.com",
+			firstUser: mockGetUserID{
+				result: 101,
+			},
+			secondUser: mockGetUserID{
+				result: 104,
+			},
+			isPendingRequest: mockIsRelated{
+				result: true,
+			},
+		},
+		"failed with an unknow format input of target user": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "test@example.com",
+			firstUser: mockGetUserID{
+				result: 101,
+			},
+			secondUser: mockGetUserID{
+				err: sql.ErrNoRows,
+			},
+			expError: errors.New(`test@example.com is not exists`),
+		},
+		"failed with an pending request is not existing": {
+			requestorEmail: "john@example.com",
+			targetEmail:    "kate@example.com",
+			firstUser: mockGetUserID{
+				result: 100,
+			},
+			secondUser: mockGetUserID{
+				result: 104,
+			},
+			expError: errors.New(`The pending friend request does not exist`),
+		},
+		"failed with an blocking relationship is existing": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "kate@example.com",
+			firstUser: mockGetUserID{
+				result: 101,
+			},
+			secondUser: mockGetUserID{
+				result: 104,
+			},
+			isPendingRequest: mockIsRelated{
+				result: true,
+			},
+			isBlockedUser: mockIsRelated{
+				result: true,
+			},
+			expError: errors.New(`The users have blocked each other`),
+		},
+		"failed with an pending request is closed concurrently": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "kate@example.com",
+			firstUser: mockGetUserID{
+				result: 101,
+			},
+			secondUser: mockGetUserID{
+				result: 104,
+			},
+			isPendingRequest: mockIsRelated{
+				result: true,
+			},
+			acceptErr: sql.ErrNoRows,
+			expError:  errors.New(`The pending friend request does not exist`),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(tc.firstUser.result, tc.firstUser.err).Once(),
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(tc.secondUser.result, tc.secondUser.err),
+				mockRepo.On("IsPendingFriendRequest", mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.isPendingRequest.result, tc.isPendingRequest.err),
+				mockRepo.On("IsExistedFriend", mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.isExistedFriend.result, tc.isExistedFriend.err),
+				mockRepo.On("IsBlockedUser", mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.isBlockedUser.result, tc.isBlockedUser.err),
+				mockRepo.On("AcceptFriendRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.acceptErr),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			err := friendService.AcceptFriendRequest(ctx, tc.requestorEmail, tc.targetEmail)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestServices_DeclineFriendRequest(t *testing.T) {
+	type mockUpdateStatus struct {
+		result int64
+		err    error
+	}
+
+	tcs := map[string]struct {
+		requestorEmail string
+		targetEmail    string
+		updateStatus   mockUpdateStatus
+		expError       error
+	}{
+		"success with an input": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "kate@example.com",
+			updateStatus: mockUpdateStatus{
+				result: 1,
+			},
+		},
+		"failed with an pending request is not existing": {
+			requestorEmail: "kate@example.com",
+			targetEmail:    "andy@example.com",
+			updateStatus: mockUpdateStatus{
+				result: 0,
+			},
+			expError: errors.New(`The pending friend request does not exist`),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(101, nil).Once(),
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(104, nil),
+				mockRepo.On("UpdateFriendRequestStatus", mock.Anything, 101, 104, "declined").
+					Return(tc.updateStatus.result, tc.updateStatus.err),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			err := friendService.DeclineFriendRequest(ctx, tc.requestorEmail, tc.targetEmail)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestServices_CancelFriendRequest(t *testing.T) {
+	type mockUpdateStatus struct {
+		result int64
+		err    error
+	}
+
+	tcs := map[string]struct {
+		requestorEmail string
+		targetEmail    string
+		updateStatus   mockUpdateStatus
+		expError       error
+	}{
+		"success with an input": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "kate@example.com",
+			updateStatus: mockUpdateStatus{
+				result: 1,
+			},
+		},
+		"failed with an updating error": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "kate@example.com",
+			updateStatus: mockUpdateStatus{
+				err: errors.New(`sql: database is closed`),
+			},
+			expError: errors.New(errs.MsgUnavailable),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(101, nil).Once(),
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(104, nil),
+				mockRepo.On("UpdateFriendRequestStatus", mock.Anything, 101, 104, "cancelled").
+					Return(tc.updateStatus.result, tc.updateStatus.err),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			err := friendService.CancelFriendRequest(ctx, tc.requestorEmail, tc.targetEmail)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestServices_GetIncomingFriendRequests(t *testing.T) {
+	type mockGetUserID struct {
+		result int
+		err    error
+	}
+	type mockGetFriendRequests struct {
+		result models.FriendRequestSlice
+		err    error
+	}
+	type mockGetEmails struct {
+		result []string
+		err    error
+	}
+
+	tcs := map[string]struct {
+		userEmail          string
+		expResult          []string
+		expError           error
+		mockUser           mockGetUserID
+		mockFriendRequests mockGetFriendRequests
+		mockEmails         mockGetEmails
+	}{
+		"success with an input": {
+			userEmail: "andy@example.com",
+			expResult: []string{"lisa@example.com"},
+			mockUser: mockGetUserID{
+				result: 101,
+			},
+			mockFriendRequests: mockGetFriendRequests{
+				result: models.FriendRequestSlice{
+					&models.FriendRequest{RequestorID: 103, TargetID: 101, Status: "pending"},
+				},
+			},
+			mockEmails: mockGetEmails{
+				result: []string{"lisa@example.com"},
+			},
+		},
+		"failed with an unknow format input": {
+			userEmail: "test@example.com",
+			mockUser: mockGetUserID{
+				err: sql.ErrNoRows,
+			},
+			expError: errors.New(`test@example.com is not exists`),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(tc.mockUser.result, tc.mockUser.err),
+				mockRepo.On("GetIncomingFriendRequests", mock.Anything, mock.Anything).
+					Return(tc.mockFriendRequests.result, tc.mockFriendRequests.err),
+				mockRepo.On("GetEmailsByUserIDs", []int{103}).
+					Return(tc.mockEmails.result, tc.mockEmails.err),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			result, err := friendService.GetIncomingFriendRequests(ctx, tc.userEmail)
+			if tc.expError != nil {
+				require.EqualError(t, tc.expError, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
+
+func TestServices_GetOutgoingFriendRequests(t *testing.T) {
+	type mockGetFriendRequests struct {
+		result models.FriendRequestSlice
+		err    error
+	}
+
+	tcs := map[string]struct {
+		userEmail          string
+		expResult          []string
+		expError           error
+		mockFriendRequests mockGetFriendRequests
+	}{
+		"success with an input": {
+			userEmail: "andy@example.com",
+			expResult: []string{"kate@example.com"},
+			mockFriendRequests: mockGetFriendRequests{
+				result: models.FriendRequestSlice{
+					&models.FriendRequest{RequestorID: 101, TargetID: 104, Status: "pending"},
+				},
+			},
+		},
+		"failed with a querying error": {
+			userEmail: "andy@example.com",
+			mockFriendRequests: mockGetFriendRequests{
+				err: errors.New(`sql: database is closed`),
+			},
+			expError: errors.New(errs.MsgUnavailable),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(101, nil),
+				mockRepo.On("GetOutgoingFriendRequests", mock.Anything, 101).
+					Return(tc.mockFriendRequests.result, tc.mockFriendRequests.err),
+				mockRepo.On("GetEmailsByUserIDs", []int{104}).
+					Return([]string{"kate@example.com"}, nil),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			result, err := friendService.GetOutgoingFriendRequests(ctx, tc.userEmail)
+			if tc.expError != nil {
+				require.EqualError(t, tc.expError, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
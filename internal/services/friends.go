@@ -2,17 +2,20 @@ package services
 
 import (
 	"context"
-	"net/http"
 
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
-	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/utils"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/events"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
 )
 
 // Get all emails of users from repository
 func (_self FriendService) GetUsers(ctx context.Context) ([]string, error) {
 	users, err := _self.Repo.GetUsers(ctx)
 	if err != nil {
-		return nil, &errs.FriendError{Code: http.StatusInternalServerError, Description: err.Error()}
+		return nil, errs.NewUnavailableError(err)
 	}
 
 	emails := []string{}
@@ -23,40 +26,46 @@ func (_self FriendService) GetUsers(ctx context.Context) ([]string, error) {
 	return emails, nil
 }
 
-// Create a new friendship by user id and friend id
-func (_self FriendService) CreateFriend(ctx context.Context, userEmail string, friendEmail string) error {
+// Get a page of emails of users from repository
+func (_self FriendService) GetUsersPage(ctx context.Context, page pagination.Page) (pagination.Result, error) {
+	users, err := _self.Repo.GetUsersPage(ctx, page)
+	if err != nil {
+		return pagination.Result{}, errs.NewUnavailableError(err)
+	}
+	count, err := _self.Repo.CountUsers(ctx)
+	if err != nil {
+		return pagination.Result{}, errs.NewUnavailableError(err)
+	}
+
+	return pagination.NewResult(page, userEmails(users), int(count)), nil
+}
+
+// Remove an existing friendship between user and friend
+func (_self FriendService) RemoveFriend(ctx context.Context, userEmail string, friendEmail string) error {
 	// Get user id and friend id from repository
 	userId, err := _self.Repo.GetUserIDByEmail(ctx, userEmail)
 	if err != nil {
-		return &errs.FriendError{Code: http.StatusBadGateway, Description: userEmail + " is not exists"}
+		return errs.NewUserLookupError(userEmail, err)
 	}
 	friendId, err := _self.Repo.GetUserIDByEmail(ctx, friendEmail)
 	if err != nil {
-		return &errs.FriendError{Code: http.StatusBadGateway, Description: friendEmail + " is not exists"}
+		return errs.NewUserLookupError(friendEmail, err)
 	}
 
 	// Check friend relationship is exists
 	isExisted, err := _self.Repo.IsExistedFriend(ctx, userId, friendId)
 	if err != nil {
-		return &errs.FriendError{Code: http.StatusInternalServerError, Description: err.Error()}
-	}
-	if isExisted {
-		return &errs.FriendError{Code: http.StatusBadGateway, Description: errs.MsgExistedFriendship}
-	}
-
-	// Check blocking between 2 emails
-	isBlocked, err := _self.Repo.IsBlockedUser(ctx, userId, friendId)
-	if err != nil {
-		return &errs.FriendError{Code: http.StatusInternalServerError, Description: err.Error()}
+		return errs.NewUnavailableError(err)
 	}
-	if isBlocked {
-		return &errs.FriendError{Code: http.StatusBadGateway, Description: errs.MsgExistedBlockedUser}
+	if !isExisted {
+		return errs.NewNotFoundError(errs.CodeFriendshipNotFound, errs.MsgNotExistedFriendship)
 	}
 
-	if err := _self.Repo.CreateFriend(ctx, userId, friendId); err != nil {
-		return &errs.FriendError{Code: http.StatusInternalServerError, Description: errs.MsgCreatedFriendship}
+	if err := _self.Repo.DeleteFriend(ctx, userId, friendId, webhookChange(webhooks.FriendRemoved, userEmail, friendEmail)); err != nil {
+		return errs.NewUnavailableError(err)
 	}
 
+	_self.publishFriendship(ctx, events.FriendRemoved, userEmail, friendEmail)
 	return nil
 }
 
@@ -65,38 +74,58 @@ func (_self FriendService) GetFriends(ctx context.Context, userEmail string) ([]
 	// Get user id from an email
 	userId, err := _self.Repo.GetUserIDByEmail(ctx, userEmail)
 	if err != nil {
-		return nil, &errs.FriendError{Code: http.StatusBadGateway, Description: userEmail + " is not exists"}
+		return nil, errs.NewUserLookupError(userEmail, err)
 	}
 
 	// Get friends available
 	friendEmails, err := _self.getFriendEmailsWithoutBlocking(ctx, userId)
 	if err != nil {
-		return nil, &errs.FriendError{Code: http.StatusInternalServerError, Description: err.Error()}
+		return nil, errs.NewUnavailableError(err)
 	}
 
 	return friendEmails, nil
 }
 
+// Get a page of friends of user, blocked friends are left out
+func (_self FriendService) GetFriendsPage(ctx context.Context, userEmail string, page pagination.Page) (pagination.Result, error) {
+	return _self.getRelationPage(ctx, userEmail, page, _self.Repo.GetFriendsPages)
+}
+
+// Get a page of users subscribing to the updates of user, blocked users are left out
+func (_self FriendService) GetSubscribersPage(ctx context.Context, userEmail string, page pagination.Page) (pagination.Result, error) {
+	return _self.getRelationPage(ctx, userEmail, page, _self.Repo.GetSubscribersPages)
+}
+
+// Get a page of users whose updates user subscribes to, blocked users are left out
+func (_self FriendService) GetSubscriptionsPage(ctx context.Context, userEmail string, page pagination.Page) (pagination.Result, error) {
+	return _self.getRelationPage(ctx, userEmail, page, _self.Repo.GetSubscriptionsPages)
+}
+
+// Get a page of users blocked by user
+func (_self FriendService) GetBlockedUsersPage(ctx context.Context, userEmail string, page pagination.Page) (pagination.Result, error) {
+	return _self.getRelationPage(ctx, userEmail, page, _self.Repo.GetBlockedUsersPages)
+}
+
 // Get emails of common friends by first user and second user
 func (_self FriendService) GetCommonFriends(ctx context.Context, firstUserEmail string, secondUserEmail string) ([]string, error) {
 	// Get user id and friend id from repository
 	firstUserID, err := _self.Repo.GetUserIDByEmail(ctx, firstUserEmail)
 	if err != nil {
-		return nil, &errs.FriendError{Code: http.StatusBadGateway, Description: firstUserEmail + " is not exists"}
+		return nil, errs.NewUserLookupError(firstUserEmail, err)
 	}
 	secondUserID, err := _self.Repo.GetUserIDByEmail(ctx, secondUserEmail)
 	if err != nil {
-		return nil, &errs.FriendError{Code: http.StatusBadGateway, Description: secondUserEmail + " is not exists"}
+		return nil, errs.NewUserLookupError(secondUserEmail, err)
 	}
 
 	// Get friends of first user and second user
 	firstFriendEmails, err := _self.getFriendEmailsWithoutBlocking(ctx, firstUserID)
 	if err != nil {
-		return nil, &errs.FriendError{Code: http.StatusInternalServerError, Description: err.Error()}
+		return nil, errs.NewUnavailableError(err)
 	}
 	secondFriendEmails, err := _self.getFriendEmailsWithoutBlocking(ctx, secondUserID)
 	if err != nil {
-		return nil, &errs.FriendError{Code: http.StatusInternalServerError, Description: err.Error()}
+		return nil, errs.NewUnavailableError(err)
 	}
 
 	// Get common friends
@@ -119,33 +148,56 @@ func (_self FriendService) CreateSubscription(ctx context.Context, requestorEmai
 	// Get requestor id and user target id from repository
 	requestorId, err := _self.Repo.GetUserIDByEmail(ctx, requestorEmail)
 	if err != nil {
-		return &errs.FriendError{Code: http.StatusBadGateway, Description: requestorEmail + " is not exists"}
+		return errs.NewUserLookupError(requestorEmail, err)
 	}
 	targetId, err := _self.Repo.GetUserIDByEmail(ctx, targetEmail)
 	if err != nil {
-		return &errs.FriendError{Code: http.StatusBadGateway, Description: targetEmail + " is not exists"}
+		return errs.NewUserLookupError(targetEmail, err)
 	}
 
 	// Check subscription relationship is exists
 	isSubscribed, err := _self.Repo.IsSubscribedUser(ctx, requestorId, targetId)
 	if err != nil {
-		return &errs.FriendError{Code: http.StatusInternalServerError, Description: err.Error()}
+		return errs.NewUnavailableError(err)
 	}
 	if isSubscribed {
-		return &errs.FriendError{Code: http.StatusBadGateway, Description: errs.MsgExistedSubscription}
+		return errs.NewConflictError(errs.CodeAlreadySubscribed, errs.MsgExistedSubscription)
 	}
 
 	// Check blocking between 2 user
 	isBlocked, err := _self.Repo.IsBlockedUser(ctx, requestorId, targetId)
 	if err != nil {
-		return &errs.FriendError{Code: http.StatusInternalServerError, Description: err.Error()}
+		return errs.NewUnavailableError(err)
 	}
 	if isBlocked {
-		return &errs.FriendError{Code: http.StatusBadGateway, Description: errs.MsgExistedBlockedUser}
+		return errs.NewConflictError(errs.CodeBlocked, errs.MsgExistedBlockedUser)
+	}
+
+	if err := _self.Repo.CreateSubscription(ctx, requestorId, targetId, webhookChange(webhooks.SubscriptionCreated, requestorEmail, targetEmail)); err != nil {
+		return errs.NewUnavailableError(err)
+	}
+
+	return nil
+}
+
+// Remove the subscription of requestor to updates of target
+func (_self FriendService) RemoveSubscription(ctx context.Context, requestorEmail string, targetEmail string) error {
+	// Get requestor id and user target id from repository
+	requestorId, err := _self.Repo.GetUserIDByEmail(ctx, requestorEmail)
+	if err != nil {
+		return errs.NewUserLookupError(requestorEmail, err)
+	}
+	targetId, err := _self.Repo.GetUserIDByEmail(ctx, targetEmail)
+	if err != nil {
+		return errs.NewUserLookupError(targetEmail, err)
 	}
 
-	if err := _self.Repo.CreateSubscription(ctx, requestorId, targetId); err != nil {
-		return &errs.FriendError{Code: http.StatusInternalServerError, Description: err.Error()}
+	deleted, err := _self.Repo.DeleteSubscription(ctx, requestorId, targetId, webhookChange(webhooks.SubscriptionRemoved, requestorEmail, targetEmail))
+	if err != nil {
+		return errs.NewUnavailableError(err)
+	}
+	if deleted == 0 {
+		return errs.NewNotFoundError(errs.CodeSubscriptionNotFound, errs.MsgNotExistedSubscription)
 	}
 
 	return nil
@@ -155,39 +207,95 @@ func (_self FriendService) CreateUserBlock(ctx context.Context, requestorEmail s
 	// Get requestor id and user target id from repository
 	requestorId, err := _self.Repo.GetUserIDByEmail(ctx, requestorEmail)
 	if err != nil {
-		return &errs.FriendError{Code: http.StatusBadGateway, Description: requestorEmail + " is not exists"}
+		return errs.NewUserLookupError(requestorEmail, err)
 	}
 	targetId, err := _self.Repo.GetUserIDByEmail(ctx, targetEmail)
 	if err != nil {
-		return &errs.FriendError{Code: http.StatusBadGateway, Description: targetEmail + " is not exists"}
+		return errs.NewUserLookupError(targetEmail, err)
 	}
 
 	// Check blocking between 2 user
 	isBlocked, err := _self.Repo.IsBlockedUser(ctx, requestorId, targetId)
 	if err != nil {
-		return &errs.FriendError{Code: http.StatusInternalServerError, Description: err.Error()}
+		return errs.NewUnavailableError(err)
 	}
 	if isBlocked {
-		return &errs.FriendError{Code: http.StatusBadGateway, Description: errs.MsgExistedBlockedUser}
+		return errs.NewConflictError(errs.CodeBlocked, errs.MsgExistedBlockedUser)
 	}
 
-	if err := _self.Repo.CreateUserBlock(ctx, requestorId, targetId); err != nil {
-		return &errs.FriendError{Code: http.StatusInternalServerError, Description: err.Error()}
+	if err := _self.Repo.CreateUserBlock(ctx, requestorId, targetId, webhookChange(webhooks.BlockCreated, requestorEmail, targetEmail)); err != nil {
+		return errs.NewUnavailableError(err)
 	}
 
+	_self.publishBlock(ctx, requestorEmail, targetEmail)
 	return nil
 }
 
-func (_self FriendService) GetRecipientEmails(ctx context.Context, senderEmail string, text string) ([]string, error) {
+// Remove a blocking relationship, only the requestor who created the block can remove it
+func (_self FriendService) RemoveUserBlock(ctx context.Context, requestorEmail string, targetEmail string) (UnblockResult, error) {
+	result := UnblockResult{}
+
+	// Get requestor id and user target id from repository
+	requestorId, err := _self.Repo.GetUserIDByEmail(ctx, requestorEmail)
+	if err != nil {
+		return result, errs.NewUserLookupError(requestorEmail, err)
+	}
+	targetId, err := _self.Repo.GetUserIDByEmail(ctx, targetEmail)
+	if err != nil {
+		return result, errs.NewUserLookupError(targetEmail, err)
+	}
+
+	deleted, err := _self.Repo.DeleteUserBlock(ctx, requestorId, targetId, webhookChange(webhooks.BlockRemoved, requestorEmail, targetEmail))
+	if err != nil {
+		return result, errs.NewUnavailableError(err)
+	}
+
+	// Check blocking between 2 user, the remaining one is created by the target
+	isBlocked, err := _self.Repo.IsBlockedUser(ctx, requestorId, targetId)
+	if err != nil {
+		return result, errs.NewUnavailableError(err)
+	}
+	if deleted == 0 {
+		if isBlocked {
+			return result, errs.NewForbiddenError(errs.CodeForbidden, errs.MsgForbiddenUnblock)
+		}
+		return result, errs.NewNotFoundError(errs.CodeBlockNotFound, errs.MsgNotExistedBlockedUser)
+	}
+	if isBlocked {
+		return result, nil
+	}
+
+	// Friendships and subscriptions are kept while blocking, so they are active again
+	result.FriendshipRestored, err = _self.Repo.IsExistedFriend(ctx, requestorId, targetId)
+	if err != nil {
+		return result, errs.NewUnavailableError(err)
+	}
+	result.SubscriptionRestored, err = _self.Repo.IsSubscribedUser(ctx, requestorId, targetId)
+	if err != nil {
+		return result, errs.NewUnavailableError(err)
+	}
+
+	// A restored friendship is back in the friend lists of both users
+	if result.FriendshipRestored {
+		_self.publishFriendship(ctx, events.FriendAdded, requestorEmail, targetEmail)
+	}
+	return result, nil
+}
+
+func (_self FriendService) GetRecipientEmails(ctx context.Context, senderEmail string, text string) (Recipients, error) {
 	// Check existed email and get userID
 	senderID, err := _self.Repo.GetUserIDByEmail(ctx, senderEmail)
 	if err != nil {
-		return nil, &errs.FriendError{Code: http.StatusBadGateway, Description: senderEmail + " is not exists"}
+		return Recipients{}, errs.NewUserLookupError(senderEmail, err)
 	}
 
 	recipients, err := _self.Repo.GetRecipientEmails(ctx, senderID)
 	if err != nil {
-		return nil, &errs.FriendError{Code: http.StatusInternalServerError, Description: err.Error()}
+		return Recipients{}, errs.NewUnavailableError(err)
+	}
+	mentions, err := _self.resolveMentions(ctx, senderID, senderEmail, text)
+	if err != nil {
+		return Recipients{}, err
 	}
 
 	result := make([]string, 0)
@@ -198,15 +306,82 @@ func (_self FriendService) GetRecipientEmails(ctx context.Context, senderEmail s
 		existedEmailsMap[user.Email] = true
 	}
 
-	// Add mentioned emails to result
-	mentionedEmails := utils.GetMentionedEmailFromText(text)
-	for _, email := range mentionedEmails {
+	// Add mentioned users to result
+	for _, email := range mentions.Emails {
 		if _, ok := existedEmailsMap[email]; !ok {
 			result = append(result, email)
 		}
 	}
 
-	return result, nil
+	return Recipients{Emails: result, Unresolved: mentions.Unresolved}, nil
+}
+
+// Get a page of emails receiving the updates of sender, the mentioned users are part of the list
+func (_self FriendService) GetRecipientEmailsPage(ctx context.Context, senderEmail string, text string, page pagination.Page) (RecipientsPage, error) {
+	// Check existed email and get userID
+	senderID, err := _self.Repo.GetUserIDByEmail(ctx, senderEmail)
+	if err != nil {
+		return RecipientsPage{}, errs.NewUserLookupError(senderEmail, err)
+	}
+
+	recipients, err := _self.Repo.GetRecipientsPage(ctx, senderID, page)
+	if err != nil {
+		return RecipientsPage{}, errs.NewUnavailableError(err)
+	}
+	count, err := _self.Repo.CountRecipients(ctx, senderID)
+	if err != nil {
+		return RecipientsPage{}, errs.NewUnavailableError(err)
+	}
+	mentions, err := _self.resolveMentions(ctx, senderID, senderEmail, text)
+	if err != nil {
+		return RecipientsPage{}, err
+	}
+
+	// Mentioned users which are not recipients yet are added to the page and the count
+	mentionedRecipients, err := _self.Repo.GetRecipientsByEmails(ctx, senderID, mentions.Emails)
+	if err != nil {
+		return RecipientsPage{}, errs.NewUnavailableError(err)
+	}
+	existedEmailsMap := make(map[string]bool)
+	for _, user := range mentionedRecipients {
+		existedEmailsMap[user.Email] = true
+	}
+	emails := userEmails(recipients)
+	for _, email := range mentions.Emails {
+		if _, ok := existedEmailsMap[email]; !ok {
+			existedEmailsMap[email] = true
+			emails = append(emails, email)
+			count++
+		}
+	}
+
+	return RecipientsPage{
+		Result:     pagination.NewResult(page, pagination.Window(page, emails), int(count)),
+		Unresolved: mentions.Unresolved,
+	}, nil
+}
+
+// Check that a new friendship can be created between user and friend
+func (_self FriendService) checkNewFriendship(ctx context.Context, userId int, friendId int) error {
+	// Check friend relationship is exists
+	isExisted, err := _self.Repo.IsExistedFriend(ctx, userId, friendId)
+	if err != nil {
+		return errs.NewUnavailableError(err)
+	}
+	if isExisted {
+		return errs.NewConflictError(errs.CodeAlreadyFriends, errs.MsgExistedFriendship)
+	}
+
+	// Check blocking between 2 emails
+	isBlocked, err := _self.Repo.IsBlockedUser(ctx, userId, friendId)
+	if err != nil {
+		return errs.NewUnavailableError(err)
+	}
+	if isBlocked {
+		return errs.NewConflictError(errs.CodeBlocked, errs.MsgExistedBlockedUser)
+	}
+
+	return nil
 }
 
 // Get emails of users who are not being blocked by user
@@ -258,3 +433,34 @@ func (_self FriendService) getFriendEmailsWithoutBlocking(ctx context.Context, u
 
 	return emails, nil
 }
+
+// Get a page of the users related to user with the batched page query of the relationship
+func (_self FriendService) getRelationPage(
+	ctx context.Context,
+	userEmail string,
+	page pagination.Page,
+	getPages func(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error),
+) (pagination.Result, error) {
+	// Get user id from an email
+	userId, err := _self.Repo.GetUserIDByEmail(ctx, userEmail)
+	if err != nil {
+		return pagination.Result{}, errs.NewUserLookupError(userEmail, err)
+	}
+
+	pages, err := getPages(ctx, []int{userId}, page)
+	if err != nil {
+		return pagination.Result{}, errs.NewUnavailableError(err)
+	}
+
+	relationPage := pages[userId]
+	return pagination.NewResult(page, relationPage.Emails, int(relationPage.TotalCount)), nil
+}
+
+// Get emails of a user slice in its order
+func userEmails(users models.UserSlice) []string {
+	emails := make([]string, len(users))
+	for i, user := range users {
+		emails[i] = user.Email
+	}
+	return emails
+}
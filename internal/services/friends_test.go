@@ -2,10 +2,14 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -33,7 +37,7 @@ func TestServices_GetUsers(t *testing.T) {
 				mockRepo.On("GetUsers", mock.Anything).Return(tc.mockUsers, tc.expError),
 			}
 
-			friendService := NewFriendService(mockRepo)
+			friendService := NewFriendService(mockRepo, nil, nil)
 			result, err := friendService.GetUsers(ctx)
 			require.NoError(t, err)
 			require.Equal(t, len(tc.expResult), len(result))
@@ -44,7 +48,7 @@ func TestServices_GetUsers(t *testing.T) {
 	}
 }
 
-func TestServices_CreateFriends(t *testing.T) {
+func TestServices_RemoveFriend(t *testing.T) {
 	type mockGetUserID struct {
 		result int
 		err    error
@@ -53,10 +57,6 @@ func TestServices_CreateFriends(t *testing.T) {
 		result bool
 		err    error
 	}
-	type mockIsBlockedUser struct {
-		result bool
-		err    error
-	}
 
 	tcs := map[string]struct {
 		userEmail       string
@@ -64,7 +64,7 @@ func TestServices_CreateFriends(t *testing.T) {
 		firstUser       mockGetUserID
 		secondUser      mockGetUserID
 		isExistedFriend mockIsExistedFriend
-		isBlockedUser   mockIsBlockedUser
+		deleteErr       error
 		expError        error
 	}{
 		"success with an input": {
@@ -77,26 +77,14 @@ func TestServices_CreateFriends(t *testing.T) {
 				result: 100,
 			},
 			isExistedFriend: mockIsExistedFriend{
-				result: false,
-			},
-			isBlockedUser: mockIsBlockedUser{
-				result: false,
+				result: true,
 			},
 		},
 		"failed with an unknow format input of user": {
 			userEmail:   "test@example.com",
 			friendEmail: "john@example.com",
 			firstUser: mockGetUserID{
-				err: errors.New(`test@example.com is not exists`),
-			},
-			secondUser: mockGetUserID{
-				result: 100,
-			},
-			isExistedFriend: mockIsExistedFriend{
-				result: false,
-			},
-			isBlockedUser: mockIsBlockedUser{
-				result: false,
+				err: sql.ErrNoRows,
 			},
 			expError: errors.New(`test@example.com is not exists`),
 		},
@@ -107,17 +95,11 @@ func TestServices_CreateFriends(t *testing.T) {
 				result: 101,
 			},
 			secondUser: mockGetUserID{
-				err: errors.New(`test@example.com is not exists`),
-			},
-			isExistedFriend: mockIsExistedFriend{
-				result: false,
-			},
-			isBlockedUser: mockIsBlockedUser{
-				result: false,
+				err: sql.ErrNoRows,
 			},
 			expError: errors.New(`test@example.com is not exists`),
 		},
-		"failed with an friendship is existing": {
+		"failed with an friendship is not existing": {
 			userEmail:   "john@example.com",
 			friendEmail: "andy@example.com",
 			firstUser: mockGetUserID{
@@ -127,14 +109,11 @@ func TestServices_CreateFriends(t *testing.T) {
 				result: 101,
 			},
 			isExistedFriend: mockIsExistedFriend{
-				result: true,
-			},
-			isBlockedUser: mockIsBlockedUser{
 				result: false,
 			},
-			expError: errors.New(`The friend relationship has been existed`),
+			expError: errors.New(`The friend relationship does not exist`),
 		},
-		"failed with an blocking relationship is existing": {
+		"failed with a deleting error": {
 			userEmail:   "john@example.com",
 			friendEmail: "andy@example.com",
 			firstUser: mockGetUserID{
@@ -144,12 +123,10 @@ func TestServices_CreateFriends(t *testing.T) {
 				result: 101,
 			},
 			isExistedFriend: mockIsExistedFriend{
-				result: false,
-			},
-			isBlockedUser: mockIsBlockedUser{
 				result: true,
 			},
-			expError: errors.New(`The users have blocked each other`),
+			deleteErr: errors.New(`sql: database is closed`),
+			expError:  errors.New(errs.MsgUnavailable),
 		},
 	}
 
@@ -164,19 +141,16 @@ func TestServices_CreateFriends(t *testing.T) {
 					Return(tc.secondUser.result, tc.secondUser.err),
 				mockRepo.On("IsExistedFriend", mock.Anything, mock.Anything, mock.Anything).
 					Return(tc.isExistedFriend.result, tc.isExistedFriend.err),
-				mockRepo.On("IsBlockedUser", mock.Anything, mock.Anything, mock.Anything).
-					Return(tc.isBlockedUser.result, tc.isBlockedUser.err),
-				mockRepo.On("CreateFriend", mock.Anything, mock.Anything, mock.Anything).
-					Return(nil),
+				mockRepo.On("DeleteFriend", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.deleteErr),
 			}
-			friendService := NewFriendService(mockRepo)
-			err := friendService.CreateFriend(ctx, tc.userEmail, tc.friendEmail)
+			friendService := NewFriendService(mockRepo, nil, nil)
+			err := friendService.RemoveFriend(ctx, tc.userEmail, tc.friendEmail)
 			if tc.expError != nil {
 				require.EqualError(t, err, tc.expError.Error())
 			} else {
 				require.NoError(t, err)
 			}
-
 		})
 	}
 }
@@ -232,7 +206,7 @@ func TestServices_GetFriends(t *testing.T) {
 		"failed with an unknow format input": {
 			userEmail: "test@example.com",
 			mockUser: mockGetUserID{
-				err: errors.New(`test@example.com is not exists`),
+				err: sql.ErrNoRows,
 			},
 			expError: errors.New(`test@example.com is not exists`),
 		},
@@ -252,7 +226,7 @@ func TestServices_GetFriends(t *testing.T) {
 				mockRepo.On("GetEmailsByUserIDs", mock.Anything, mock.Anything).
 					Return(tc.mockEmails.result, tc.mockEmails.err),
 			}
-			friendService := NewFriendService(mockRepo)
+			friendService := NewFriendService(mockRepo, nil, nil)
 			result, err := friendService.GetFriends(ctx, tc.userEmail)
 			if tc.expError != nil {
 				require.EqualError(t, tc.expError, err.Error())
@@ -338,7 +312,7 @@ func TestServices_GetCommonFriends(t *testing.T) {
 		"failed with an unknow format input of first user": {
 			firstEmail: "test@example.com",
 			firstMockUser: mockGetUserID{
-				err: errors.New(`test@example.com is not exists`),
+				err: sql.ErrNoRows,
 			},
 			expError: errors.New(`test@example.com is not exists`),
 		},
@@ -349,7 +323,7 @@ func TestServices_GetCommonFriends(t *testing.T) {
 				result: 100,
 			},
 			secondMockUser: mockGetUserID{
-				err: errors.New(`test@example.com is not exists`),
+				err: sql.ErrNoRows,
 			},
 			expError: errors.New(`test@example.com is not exists`),
 		},
@@ -378,7 +352,7 @@ func TestServices_GetCommonFriends(t *testing.T) {
 				mockRepo.On("GetEmailsByUserIDs", mock.Anything, mock.Anything).
 					Return(tc.secondMockEmails.result, tc.secondMockEmails.err),
 			}
-			friendService := NewFriendService(mockRepo)
+			friendService := NewFriendService(mockRepo, nil, nil)
 			result, err := friendService.GetCommonFriends(ctx, tc.firstEmail, tc.secondEmail)
 			if tc.expError != nil {
 				require.EqualError(t, tc.expError, err.Error())
@@ -433,7 +407,7 @@ func TestServices_CreateSubcription(t *testing.T) {
 			requestorEmail: "test@example.com",
 			targetEmail:    "john@example.com",
 			firstUser: mockGetUserID{
-				err: errors.New(`test@example.com is not exists`),
+				err: sql.ErrNoRows,
 			},
 			secondUser: mockGetUserID{
 				result: 100,
@@ -453,7 +427,7 @@ func TestServices_CreateSubcription(t *testing.T) {
 				result: 101,
 			},
 			secondUser: mockGetUserID{
-				err: errors.New(`test@example.com is not exists`),
+				err: sql.ErrNoRows,
 			},
 			isSubscribedUser: mockIsSubscribedUser{
 				result: false,
@@ -512,10 +486,10 @@ func TestServices_CreateSubcription(t *testing.T) {
 					Return(tc.isSubscribedUser.result, tc.isSubscribedUser.err),
 				mockRepo.On("IsBlockedUser", mock.Anything, mock.Anything, mock.Anything).
 					Return(tc.isBlockedUser.result, tc.isBlockedUser.err),
-				mockRepo.On("CreateSubscription", mock.Anything, mock.Anything, mock.Anything).
+				mockRepo.On("CreateSubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil),
 			}
-			friendService := NewFriendService(mockRepo)
+			friendService := NewFriendService(mockRepo, nil, nil)
 			err := friendService.CreateSubscription(ctx, tc.requestorEmail, tc.targetEmail)
 			if tc.expError != nil {
 				require.EqualError(t, err, tc.expError.Error())
@@ -527,6 +501,109 @@ func TestServices_CreateSubcription(t *testing.T) {
 	}
 }
 
+func TestServices_RemoveSubcription(t *testing.T) {
+	type mockGetUserID struct {
+		result int
+		err    error
+	}
+	type mockDeleteSubscription struct {
+		result int64
+		err    error
+	}
+
+	tcs := map[string]struct {
+		requestorEmail     string
+		targetEmail        string
+		firstUser          mockGetUserID
+		secondUser         mockGetUserID
+		deleteSubscription mockDeleteSubscription
+		expError           error
+	}{
+		"success with an input": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "lisa@example.com",
+			firstUser: mockGetUserID{
+				result: 101,
+			},
+			secondUser: mockGetUserID{
+				result: 103,
+			},
+			deleteSubscription: mockDeleteSubscription{
+				result: 1,
+			},
+		},
+		"failed with an unknow format input of requestor": {
+			requestorEmail: "test@example.com",
+			targetEmail:    "lisa@example.com",
+			firstUser: mockGetUserID{
+				err: sql.ErrNoRows,
+			},
+			expError: errors.New(`test@example.com is not exists`),
+		},
+		"failed with an unknow format input of target user": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "test@example.com",
+			firstUser: mockGetUserID{
+				result: 101,
+			},
+			secondUser: mockGetUserID{
+				err: sql.ErrNoRows,
+			},
+			expError: errors.New(`test@example.com is not exists`),
+		},
+		"failed with an subscribed relatinship is not existing": {
+			requestorEmail: "john@example.com",
+			targetEmail:    "andy@example.com",
+			firstUser: mockGetUserID{
+				result: 100,
+			},
+			secondUser: mockGetUserID{
+				result: 101,
+			},
+			deleteSubscription: mockDeleteSubscription{
+				result: 0,
+			},
+			expError: errors.New(`The subscription does not exist`),
+		},
+		"failed with a deleting error": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "lisa@example.com",
+			firstUser: mockGetUserID{
+				result: 101,
+			},
+			secondUser: mockGetUserID{
+				result: 103,
+			},
+			deleteSubscription: mockDeleteSubscription{
+				err: errors.New(`sql: database is closed`),
+			},
+			expError: errors.New(errs.MsgUnavailable),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(tc.firstUser.result, tc.firstUser.err).Once(),
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(tc.secondUser.result, tc.secondUser.err),
+				mockRepo.On("DeleteSubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.deleteSubscription.result, tc.deleteSubscription.err),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			err := friendService.RemoveSubscription(ctx, tc.requestorEmail, tc.targetEmail)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestServices_CreateUserBlocks(t *testing.T) {
 	type mockGetUserID struct {
 		result int
@@ -562,7 +639,7 @@ func TestServices_CreateUserBlocks(t *testing.T) {
 			requestorEmail: "test@example.com",
 			targetEmail:    "john@example.com",
 			firstUser: mockGetUserID{
-				err: errors.New(`test@example.com is not exists`),
+				err: sql.ErrNoRows,
 			},
 			secondUser: mockGetUserID{
 				result: 100,
@@ -579,7 +656,7 @@ func TestServices_CreateUserBlocks(t *testing.T) {
 				result: 101,
 			},
 			secondUser: mockGetUserID{
-				err: errors.New(`test@example.com is not exists`),
+				err: sql.ErrNoRows,
 			},
 			isBlockedUser: mockIsBlockedUser{
 				result: false,
@@ -616,10 +693,10 @@ func TestServices_CreateUserBlocks(t *testing.T) {
 				mockRepo.On("IsBlockedUser", mock.Anything, mock.Anything, mock.Anything).
 					Return(tc.isBlockedUser.result, tc.isBlockedUser.err),
 
-				mockRepo.On("CreateUserBlock", mock.Anything, mock.Anything, mock.Anything).
+				mockRepo.On("CreateUserBlock", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil),
 			}
-			friendService := NewFriendService(mockRepo)
+			friendService := NewFriendService(mockRepo, nil, nil)
 			err := friendService.CreateUserBlock(ctx, tc.requestorEmail, tc.targetEmail)
 			if tc.expError != nil {
 				require.EqualError(t, err, tc.expError.Error())
@@ -631,6 +708,168 @@ func TestServices_CreateUserBlocks(t *testing.T) {
 	}
 }
 
+func TestServices_RemoveUserBlocks(t *testing.T) {
+	type mockGetUserID struct {
+		result int
+		err    error
+	}
+	type mockDeleteUserBlock struct {
+		result int64
+		err    error
+	}
+	type mockIsRelated struct {
+		result bool
+		err    error
+	}
+
+	tcs := map[string]struct {
+		requestorEmail   string
+		targetEmail      string
+		firstUser        mockGetUserID
+		secondUser       mockGetUserID
+		deleteUserBlock  mockDeleteUserBlock
+		isBlockedUser    mockIsRelated
+		isExistedFriend  mockIsRelated
+		isSubscribedUser mockIsRelated
+		expResult        UnblockResult
+		expError         error
+	}{
+		"success with restored relationships": {
+			requestorEmail: "john@example.com",
+			targetEmail:    "lisa@example.com",
+			firstUser: mockGetUserID{
+				result: 100,
+			},
+			secondUser: mockGetUserID{
+				result: 103,
+			},
+			deleteUserBlock: mockDeleteUserBlock{
+				result: 1,
+			},
+			isExistedFriend: mockIsRelated{
+				result: true,
+			},
+			isSubscribedUser: mockIsRelated{
+				result: true,
+			},
+			expResult: UnblockResult{FriendshipRestored: true, SubscriptionRestored: true},
+		},
+		"success without relationships to restore": {
+			requestorEmail: "john@example.com",
+			targetEmail:    "kate@example.com",
+			firstUser: mockGetUserID{
+				result: 100,
+			},
+			secondUser: mockGetUserID{
+				result: 104,
+			},
+			deleteUserBlock: mockDeleteUserBlock{
+				result: 1,
+			},
+			expResult: UnblockResult{},
+		},
+		"success with target still blocking requestor": {
+			requestorEmail: "john@example.com",
+			targetEmail:    "kate@example.com",
+			firstUser: mockGetUserID{
+				result: 100,
+			},
+			secondUser: mockGetUserID{
+				result: 104,
+			},
+			deleteUserBlock: mockDeleteUserBlock{
+				result: 1,
+			},
+			isBlockedUser: mockIsRelated{
+				result: true,
+			},
+			isExistedFriend: mockIsRelated{
+				result: true,
+			},
+			expResult: UnblockResult{},
+		},
+		"failed with an unknow format input of requestor": {
+			requestorEmail: "test@example.com",
+			targetEmail:    "john@example.com",
+			firstUser: mockGetUserID{
+				err: sql.ErrNoRows,
+			},
+			expError: errors.New(`test@example.com is not exists`),
+		},
+		"failed with an unknow format input of target user": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "test@example.com",
+			firstUser: mockGetUserID{
+				result: 101,
+			},
+			secondUser: mockGetUserID{
+				err: sql.ErrNoRows,
+			},
+			expError: errors.New(`test@example.com is not exists`),
+		},
+		"failed with an blocking relationship is created by target": {
+			requestorEmail: "lisa@example.com",
+			targetEmail:    "john@example.com",
+			firstUser: mockGetUserID{
+				result: 103,
+			},
+			secondUser: mockGetUserID{
+				result: 100,
+			},
+			deleteUserBlock: mockDeleteUserBlock{
+				result: 0,
+			},
+			isBlockedUser: mockIsRelated{
+				result: true,
+			},
+			expError: errors.New(`Only the requestor of the block can unblock the target`),
+		},
+		"failed with an blocking relationship is not existing": {
+			requestorEmail: "andy@example.com",
+			targetEmail:    "john@example.com",
+			firstUser: mockGetUserID{
+				result: 101,
+			},
+			secondUser: mockGetUserID{
+				result: 100,
+			},
+			deleteUserBlock: mockDeleteUserBlock{
+				result: 0,
+			},
+			expError: errors.New(`The blocking relationship does not exist`),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(tc.firstUser.result, tc.firstUser.err).Once(),
+				mockRepo.On("GetUserIDByEmail", mock.Anything, mock.Anything).
+					Return(tc.secondUser.result, tc.secondUser.err),
+				mockRepo.On("DeleteUserBlock", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.deleteUserBlock.result, tc.deleteUserBlock.err),
+				mockRepo.On("IsBlockedUser", mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.isBlockedUser.result, tc.isBlockedUser.err),
+				mockRepo.On("IsExistedFriend", mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.isExistedFriend.result, tc.isExistedFriend.err),
+				mockRepo.On("IsSubscribedUser", mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.isSubscribedUser.result, tc.isSubscribedUser.err),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			result, err := friendService.RemoveUserBlock(ctx, tc.requestorEmail, tc.targetEmail)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
+
 func TestServices_GetRecipientEmails(t *testing.T) {
 	type mockGetUserID struct {
 		result int
@@ -646,17 +885,42 @@ func TestServices_GetRecipientEmails(t *testing.T) {
 	}
 
 	tcs := map[string]struct {
-		userEmail      string
-		text           string
-		expResult      []string
-		expError       error
-		mockUser       mockGetUserID
-		mockRecipients mockGetRecipients
+		userEmail       string
+		text            string
+		expResult       Recipients
+		expError        error
+		mockUser        mockGetUserID
+		mockRecipients  mockGetRecipients
+		mockMentionable mockGetRecipients
 	}{
 		"success with an input": {
 			userEmail: "andy@example.com",
 			text:      "hello! kate@example.com",
-			expResult: []string{"john@example.com", "kate@example.com"},
+			expResult: Recipients{
+				Emails:     []string{"john@example.com", "kate@example.com"},
+				Unresolved: []string{},
+			},
+			mockUser: mockGetUserID{
+				result: 100,
+			},
+			mockRecipients: mockGetRecipients{
+				result: models.UserSlice{
+					&models.User{Name: "john", Email: "john@example.com"},
+				},
+			},
+			mockMentionable: mockGetRecipients{
+				result: models.UserSlice{
+					&models.User{Email: "kate@example.com"},
+				},
+			},
+		},
+		"success with resolved and unresolved mentions": {
+			userEmail: "andy@example.com",
+			text:      "hi john@example.com, kate@example.com. Do you know bob@example.com? lisa@example.com andy@example.com",
+			expResult: Recipients{
+				Emails:     []string{"john@example.com", "kate@example.com"},
+				Unresolved: []string{"bob@example.com", "lisa@example.com"},
+			},
 			mockUser: mockGetUserID{
 				result: 100,
 			},
@@ -665,11 +929,29 @@ func TestServices_GetRecipientEmails(t *testing.T) {
 					&models.User{Name: "john", Email: "john@example.com"},
 				},
 			},
+			mockMentionable: mockGetRecipients{
+				result: models.UserSlice{
+					&models.User{Email: "john@example.com"},
+					&models.User{Email: "kate@example.com"},
+				},
+			},
+		},
+		"failed with an unavailable storage of the users": {
+			userEmail: "andy@example.com",
+			text:      "hello! kate@example.com",
+			mockUser: mockGetUserID{
+				result: 100,
+			},
+			mockMentionable: mockGetRecipients{
+				result: models.UserSlice{},
+				err:    errors.New("sql: database is closed"),
+			},
+			expError: errors.New(errs.MsgUnavailable),
 		},
 		"failed with an unknow format input": {
 			userEmail: "test@example.com",
 			mockUser: mockGetUserID{
-				err: errors.New(`test@example.com is not exists`),
+				err: sql.ErrNoRows,
 			},
 			expError: errors.New(`test@example.com is not exists`),
 		},
@@ -685,8 +967,11 @@ func TestServices_GetRecipientEmails(t *testing.T) {
 
 				mockRepo.On("GetRecipientEmails", mock.Anything, mock.Anything).
 					Return(tc.mockRecipients.result, tc.mockRecipients.err),
+
+				mockRepo.On("GetMentionableUsers", mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.mockMentionable.result, tc.mockMentionable.err),
 			}
-			friendService := NewFriendService(mockRepo)
+			friendService := NewFriendService(mockRepo, nil, nil)
 			result, err := friendService.GetRecipientEmails(ctx, tc.userEmail, tc.text)
 			if tc.expError != nil {
 				require.EqualError(t, tc.expError, err.Error())
@@ -697,3 +982,334 @@ func TestServices_GetRecipientEmails(t *testing.T) {
 		})
 	}
 }
+
+func TestServices_GetUsersPage(t *testing.T) {
+	tcs := map[string]struct {
+		page      pagination.Page
+		mockUsers models.UserSlice
+		mockCount int64
+		mockErr   error
+		expResult pagination.Result
+		expError  error
+	}{
+		"success with a page which goes on": {
+			page: pagination.Page{Size: 2},
+			mockUsers: models.UserSlice{
+				&models.User{Email: "andy@example.com"},
+				&models.User{Email: "common@example.com"},
+				&models.User{Email: "john@example.com"},
+			},
+			mockCount: 5,
+			expResult: pagination.Result{
+				Emails:      []string{"andy@example.com", "common@example.com"},
+				HasNextPage: true,
+				TotalCount:  5,
+			},
+		},
+		"success with a last page": {
+			page: pagination.Page{Size: 2, Backward: true},
+			mockUsers: models.UserSlice{
+				&models.User{Email: "lisa@example.com"},
+			},
+			mockCount: 1,
+			expResult: pagination.Result{
+				Emails:     []string{"lisa@example.com"},
+				TotalCount: 1,
+			},
+		},
+		"failed with a repository error": {
+			page:     pagination.Page{Size: 2},
+			mockErr:  errors.New("sql: database is closed"),
+			expError: errors.New(errs.MsgUnavailable),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUsersPage", mock.Anything, tc.page).Return(tc.mockUsers, tc.mockErr),
+				mockRepo.On("CountUsers", mock.Anything).Return(tc.mockCount, nil),
+			}
+
+			friendService := NewFriendService(mockRepo, nil, nil)
+			result, err := friendService.GetUsersPage(ctx, tc.page)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
+
+func TestServices_GetFriendsPage(t *testing.T) {
+	type mockGetUserID struct {
+		result int
+		err    error
+	}
+
+	tcs := map[string]struct {
+		userEmail   string
+		page        pagination.Page
+		mockUser    mockGetUserID
+		mockFriends repository.RelationPage
+		expResult   pagination.Result
+		expError    error
+	}{
+		"success with a page after a cursor": {
+			userEmail: "andy@example.com",
+			page:      pagination.Page{Size: 2, After: "common@example.com"},
+			mockUser:  mockGetUserID{result: 101},
+			mockFriends: repository.RelationPage{
+				Emails:     []string{"john@example.com"},
+				TotalCount: 2,
+			},
+			expResult: pagination.Result{
+				Emails:          []string{"john@example.com"},
+				HasPreviousPage: true,
+				TotalCount:      2,
+			},
+		},
+		"failed with a non-existent user": {
+			userEmail: "test@example.com",
+			page:      pagination.Page{Size: 2},
+			mockUser:  mockGetUserID{err: sql.ErrNoRows},
+			expError:  errors.New("test@example.com is not exists"),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", tc.userEmail).Return(tc.mockUser.result, tc.mockUser.err),
+				mockRepo.On("GetFriendsPages", mock.Anything, []int{tc.mockUser.result}, tc.page).
+					Return(map[int]repository.RelationPage{tc.mockUser.result: tc.mockFriends}, nil),
+			}
+
+			friendService := NewFriendService(mockRepo, nil, nil)
+			result, err := friendService.GetFriendsPage(ctx, tc.userEmail, tc.page)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
+
+func TestServices_GetRecipientEmailsPage(t *testing.T) {
+	type mockGetUserID struct {
+		result int
+		err    error
+	}
+
+	tcs := map[string]struct {
+		senderEmail        string
+		text               string
+		page               pagination.Page
+		mockUser           mockGetUserID
+		mockRecipients     models.UserSlice
+		mockCount          int64
+		mockMentionable    models.UserSlice
+		mockMentioned      models.UserSlice
+		expMentionedEmails []string
+		expResult          RecipientsPage
+		expError           error
+	}{
+		"success with mentioned emails merged into the page": {
+			senderEmail: "lisa@example.com",
+			text:        "Hello World! kate@example.com andy@example.com",
+			page:        pagination.Page{Size: 2},
+			mockUser:    mockGetUserID{result: 103},
+			mockRecipients: models.UserSlice{
+				&models.User{Email: "common@example.com"},
+				&models.User{Email: "kate@example.com"},
+			},
+			mockCount: 2,
+			mockMentionable: models.UserSlice{
+				&models.User{Email: "kate@example.com"},
+				&models.User{Email: "andy@example.com"},
+			},
+			mockMentioned: models.UserSlice{
+				&models.User{Email: "kate@example.com"},
+			},
+			expMentionedEmails: []string{"kate@example.com", "andy@example.com"},
+			expResult: RecipientsPage{
+				Result: pagination.Result{
+					Emails:      []string{"andy@example.com", "common@example.com"},
+					HasNextPage: true,
+					TotalCount:  3,
+				},
+				Unresolved: []string{},
+			},
+		},
+		"success with unresolved mentions left out of the page": {
+			senderEmail: "lisa@example.com",
+			text:        "Hello kate@example.com, bob@example.com and lisa@example.com!",
+			page:        pagination.Page{Size: 2},
+			mockUser:    mockGetUserID{result: 103},
+			mockRecipients: models.UserSlice{
+				&models.User{Email: "common@example.com"},
+			},
+			mockCount: 1,
+			mockMentionable: models.UserSlice{
+				&models.User{Email: "kate@example.com"},
+			},
+			mockMentioned:      models.UserSlice{},
+			expMentionedEmails: []string{"kate@example.com"},
+			expResult: RecipientsPage{
+				Result: pagination.Result{
+					Emails:     []string{"common@example.com", "kate@example.com"},
+					TotalCount: 2,
+				},
+				Unresolved: []string{"bob@example.com"},
+			},
+		},
+		"success with mentioned emails out of the page": {
+			senderEmail: "lisa@example.com",
+			text:        "Hello World! andy@example.com",
+			page:        pagination.Page{Size: 2, After: "andy@example.com"},
+			mockUser:    mockGetUserID{result: 103},
+			mockRecipients: models.UserSlice{
+				&models.User{Email: "common@example.com"},
+			},
+			mockCount: 1,
+			mockMentionable: models.UserSlice{
+				&models.User{Email: "andy@example.com"},
+			},
+			mockMentioned:      models.UserSlice{},
+			expMentionedEmails: []string{"andy@example.com"},
+			expResult: RecipientsPage{
+				Result: pagination.Result{
+					Emails:          []string{"common@example.com"},
+					HasPreviousPage: true,
+					TotalCount:      2,
+				},
+				Unresolved: []string{},
+			},
+		},
+		"failed with a non-existent sender": {
+			senderEmail: "test@example.com",
+			text:        "Hello World!",
+			page:        pagination.Page{Size: 2},
+			mockUser:    mockGetUserID{err: sql.ErrNoRows},
+			expError:    errors.New("test@example.com is not exists"),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", tc.senderEmail).Return(tc.mockUser.result, tc.mockUser.err),
+				mockRepo.On("GetRecipientsPage", mock.Anything, tc.mockUser.result, tc.page).Return(tc.mockRecipients, nil),
+				mockRepo.On("CountRecipients", mock.Anything, tc.mockUser.result).Return(tc.mockCount, nil),
+				mockRepo.On("GetMentionableUsers", mock.Anything, tc.mockUser.result, mock.Anything).
+					Return(tc.mockMentionable, nil),
+				mockRepo.On("GetRecipientsByEmails", mock.Anything, tc.mockUser.result, tc.expMentionedEmails).
+					Return(tc.mockMentioned, nil),
+			}
+
+			friendService := NewFriendService(mockRepo, nil, nil)
+			result, err := friendService.GetRecipientEmailsPage(ctx, tc.senderEmail, tc.text, tc.page)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
+
+func TestServices_GetRelationPages(t *testing.T) {
+	page := pagination.Page{Size: 1}
+
+	tcs := map[string]struct {
+		getPage   func(FriendService, context.Context, string, pagination.Page) (pagination.Result, error)
+		pageQuery string
+	}{
+		"subscribers": {
+			getPage:   FriendService.GetSubscribersPage,
+			pageQuery: "GetSubscribersPages",
+		},
+		"subscriptions": {
+			getPage:   FriendService.GetSubscriptionsPage,
+			pageQuery: "GetSubscriptionsPages",
+		},
+		"blocked users": {
+			getPage:   FriendService.GetBlockedUsersPage,
+			pageQuery: "GetBlockedUsersPages",
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", "lisa@example.com").Return(103, nil),
+				mockRepo.On(tc.pageQuery, mock.Anything, []int{103}, page).Return(map[int]repository.RelationPage{
+					103: {Emails: []string{"andy@example.com", "kate@example.com"}, TotalCount: 2},
+				}, nil),
+			}
+
+			result, err := tc.getPage(NewFriendService(mockRepo, nil, nil), ctx, "lisa@example.com", page)
+			require.NoError(t, err)
+			require.Equal(t, pagination.Result{
+				Emails:      []string{"andy@example.com"},
+				HasNextPage: true,
+				TotalCount:  2,
+			}, result)
+		})
+	}
+}
+
+func TestServices_UserLookupErrors(t *testing.T) {
+	tcs := map[string]struct {
+		mockErr    error
+		expKind    error
+		expCause   error
+		expMessage string
+	}{
+		"unknown user is not found": {
+			mockErr:    sql.ErrNoRows,
+			expKind:    errs.ErrNotFound,
+			expCause:   sql.ErrNoRows,
+			expMessage: "andy@example.com is not exists",
+		},
+		"failed database is unavailable": {
+			mockErr:    sql.ErrConnDone,
+			expKind:    errs.ErrUnavailable,
+			expCause:   sql.ErrConnDone,
+			expMessage: errs.MsgUnavailable,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", "andy@example.com").Return(0, tc.mockErr),
+			}
+
+			friendService := NewFriendService(mockRepo, nil, nil)
+			_, err := friendService.GetFriends(ctx, "andy@example.com")
+			require.EqualError(t, err, tc.expMessage)
+			require.True(t, errors.Is(err, tc.expKind))
+			require.True(t, errors.Is(err, tc.expCause))
+
+			var friendErr *errs.FriendError
+			require.True(t, errors.As(err, &friendErr))
+			require.Equal(t, tc.expKind, friendErr.Kind)
+		})
+	}
+}
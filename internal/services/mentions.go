@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/mentions"
+)
+
+// resolveMentions finds the users mentioned in an update of a sender. A mention is resolved when it is a user
+// without blocking relationship with the sender, the other mentions are unresolved.
+// The unresolved mentions do not tell a block from an unknown user, and a sender mentioning itself is left out.
+func (_self FriendService) resolveMentions(ctx context.Context, senderID int, senderEmail string, text string) (Recipients, error) {
+	result := Recipients{Emails: make([]string, 0), Unresolved: make([]string, 0)}
+	mentionedEmails := mentions.Parse(text)
+	if len(mentionedEmails) == 0 {
+		return result, nil
+	}
+
+	users, err := _self.Repo.GetMentionableUsers(ctx, senderID, mentionedEmails)
+	if err != nil {
+		return Recipients{}, errs.NewUnavailableError(err)
+	}
+	mentionable := make(map[string]bool)
+	for _, user := range users {
+		mentionable[user.Email] = true
+	}
+
+	for _, email := range mentionedEmails {
+		switch {
+		case email == senderEmail:
+		case mentionable[email]:
+			result.Emails = append(result.Emails, email)
+		default:
+			result.Unresolved = append(result.Unresolved, email)
+		}
+	}
+	return result, nil
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -22,8 +26,8 @@ func (m SpecRepo) GetUsers(ctx context.Context) (models.UserSlice, error) {
 	return r1, r2
 }
 
-func (m SpecRepo) CreateFriend(ctx context.Context, userId int, friendId int) error {
-	args := m.Called(ctx, userId, friendId)
+func (m SpecRepo) DeleteFriend(ctx context.Context, userId int, friendId int, change webhooks.Payload) error {
+	args := m.Called(ctx, userId, friendId, change)
 	var r error
 	if args.Get(0) != nil {
 		r = args.Get(0).(error)
@@ -48,8 +52,8 @@ func (m SpecRepo) GetUserBlocksByID(ctx context.Context, userId int) (models.Use
 	return r1, r2
 }
 
-func (m SpecRepo) CreateSubscription(ctx context.Context, requestorId int, targetId int) error {
-	args := m.Called(ctx, requestorId, targetId)
+func (m SpecRepo) CreateSubscription(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) error {
+	args := m.Called(ctx, requestorId, targetId, change)
 	var r error
 	if args.Get(0) != nil {
 		r = args.Get(0).(error)
@@ -57,6 +61,17 @@ func (m SpecRepo) CreateSubscription(ctx context.Context, requestorId int, targe
 	return r
 }
 
+func (m SpecRepo) DeleteSubscription(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) (int64, error) {
+	args := m.Called(ctx, requestorId, targetId, change)
+	r1 := args.Get(0).(int64)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
 func (m SpecRepo) GetRecipientEmails(ctx context.Context, senderId int) (models.UserSlice, error) {
 	args := m.Called(ctx, senderId)
 	r1 := args.Get(0).(models.UserSlice)
@@ -68,8 +83,8 @@ func (m SpecRepo) GetRecipientEmails(ctx context.Context, senderId int) (models.
 	return r1, r2
 }
 
-func (m SpecRepo) CreateUserBlock(ctx context.Context, requestorId int, targetId int) error {
-	args := m.Called(ctx, requestorId, targetId)
+func (m SpecRepo) CreateUserBlock(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) error {
+	args := m.Called(ctx, requestorId, targetId, change)
 	var r error
 	if args.Get(0) != nil {
 		r = args.Get(0).(error)
@@ -77,6 +92,17 @@ func (m SpecRepo) CreateUserBlock(ctx context.Context, requestorId int, targetId
 	return r
 }
 
+func (m SpecRepo) DeleteUserBlock(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) (int64, error) {
+	args := m.Called(ctx, requestorId, targetId, change)
+	r1 := args.Get(0).(int64)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
 func (m SpecRepo) IsExistedFriend(ctx context.Context, userId int, friendId int) (bool, error) {
 	args := m.Called(ctx, userId, friendId)
 	r1 := args.Get(0).(bool)
@@ -127,3 +153,326 @@ func (m SpecRepo) GetEmailsByUserIDs(ctx context.Context, userIDs []int) ([]stri
 	}
 	return r1, r2
 }
+
+func (m SpecRepo) CreateFriendRequest(ctx context.Context, requestorId int, targetId int) error {
+	args := m.Called(ctx, requestorId, targetId)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (m SpecRepo) IsPendingFriendRequest(ctx context.Context, requestorId int, targetId int) (bool, error) {
+	args := m.Called(ctx, requestorId, targetId)
+	r1 := args.Get(0).(bool)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) UpdateFriendRequestStatus(ctx context.Context, requestorId int, targetId int, status string) (int64, error) {
+	args := m.Called(ctx, requestorId, targetId, status)
+	r1 := args.Get(0).(int64)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) AcceptFriendRequest(ctx context.Context, requestorId int, targetId int, notifications []notify.Message, change webhooks.Payload) error {
+	args := m.Called(ctx, requestorId, targetId, notifications, change)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (m SpecRepo) GetIncomingFriendRequests(ctx context.Context, targetId int) (models.FriendRequestSlice, error) {
+	args := m.Called(ctx, targetId)
+	r1 := args.Get(0).(models.FriendRequestSlice)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetOutgoingFriendRequests(ctx context.Context, requestorId int) (models.FriendRequestSlice, error) {
+	args := m.Called(ctx, requestorId)
+	r1 := args.Get(0).(models.FriendRequestSlice)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) CreateUser(ctx context.Context, name string, email string, password string) (int, error) {
+	args := m.Called(ctx, name, email, password)
+	r1 := args.Get(0).(int)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) UpdateUser(ctx context.Context, userId int, name string, email string) error {
+	args := m.Called(ctx, userId, name, email)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (m SpecRepo) DeleteUser(ctx context.Context, userId int) error {
+	args := m.Called(ctx, userId)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (m SpecRepo) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	args := m.Called(ctx, email)
+	r1 := args.Get(0).(*models.User)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetUsersPage(ctx context.Context, page pagination.Page) (models.UserSlice, error) {
+	args := m.Called(ctx, page)
+	r1 := args.Get(0).(models.UserSlice)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) CountUsers(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	r1 := args.Get(0).(int64)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetFriendsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	args := m.Called(ctx, userIds, page)
+	r1 := args.Get(0).(map[int]repository.RelationPage)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetRecipientsPage(ctx context.Context, senderId int, page pagination.Page) (models.UserSlice, error) {
+	args := m.Called(ctx, senderId, page)
+	r1 := args.Get(0).(models.UserSlice)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) CountRecipients(ctx context.Context, senderId int) (int64, error) {
+	args := m.Called(ctx, senderId)
+	r1 := args.Get(0).(int64)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetRecipientsByEmails(ctx context.Context, senderId int, emails []string) (models.UserSlice, error) {
+	args := m.Called(ctx, senderId, emails)
+	r1 := args.Get(0).(models.UserSlice)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetSubscribersPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	args := m.Called(ctx, userIds, page)
+	r1 := args.Get(0).(map[int]repository.RelationPage)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetSubscriptionsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	args := m.Called(ctx, userIds, page)
+	r1 := args.Get(0).(map[int]repository.RelationPage)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetBlockedUsersPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	args := m.Called(ctx, userIds, page)
+	r1 := args.Get(0).(map[int]repository.RelationPage)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetUsersByEmails(ctx context.Context, emails []string) (models.UserSlice, error) {
+	args := m.Called(ctx, emails)
+	r1 := args.Get(0).(models.UserSlice)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetUsersByIDs(ctx context.Context, userIds []int) (models.UserSlice, error) {
+	args := m.Called(ctx, userIds)
+	r1 := args.Get(0).(models.UserSlice)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetFriendsByIDs(ctx context.Context, userIds []int) (models.FriendSlice, error) {
+	args := m.Called(ctx, userIds)
+	r1 := args.Get(0).(models.FriendSlice)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetUserBlocksByIDs(ctx context.Context, userIds []int) (models.UserBlockSlice, error) {
+	args := m.Called(ctx, userIds)
+	r1 := args.Get(0).(models.UserBlockSlice)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetMentionableUsers(ctx context.Context, senderId int, emails []string) (models.UserSlice, error) {
+	args := m.Called(ctx, senderId, emails)
+	r1 := args.Get(0).(models.UserSlice)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) CreateUpdate(ctx context.Context, senderId int, text string, mentionedEmails []string, notification notify.Message) (int, models.UserSlice, error) {
+	args := m.Called(ctx, senderId, text, mentionedEmails, notification)
+	r1 := args.Get(0).(int)
+	r2 := args.Get(1).(models.UserSlice)
+
+	var r3 error
+	if args.Get(2) != nil {
+		r3 = args.Get(2).(error)
+	}
+	return r1, r2, r3
+}
+
+func (m SpecRepo) CreateWebhook(ctx context.Context, url string, secret string, events []webhooks.Event) (webhooks.Webhook, error) {
+	args := m.Called(ctx, url, secret, events)
+	r1 := args.Get(0).(webhooks.Webhook)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetWebhooks(ctx context.Context) ([]webhooks.Webhook, error) {
+	args := m.Called(ctx)
+	r1 := args.Get(0).([]webhooks.Webhook)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) IsExistedWebhook(ctx context.Context, webhookId int) (bool, error) {
+	args := m.Called(ctx, webhookId)
+	r1 := args.Get(0).(bool)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) DeleteWebhook(ctx context.Context, webhookId int) (int64, error) {
+	args := m.Called(ctx, webhookId)
+	r1 := args.Get(0).(int64)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
+
+func (m SpecRepo) GetWebhookDeliveries(ctx context.Context, webhookId int, limit int) ([]webhooks.DeliveryLog, error) {
+	args := m.Called(ctx, webhookId, limit)
+	r1 := args.Get(0).([]webhooks.DeliveryLog)
+
+	var r2 error
+	if args.Get(1) != nil {
+		r2 = args.Get(1).(error)
+	}
+	return r1, r2
+}
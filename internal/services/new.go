@@ -1,13 +1,71 @@
 package services
 
-import "github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
+import (
+	"context"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/events"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
+)
 
 type FriendService struct {
-	Repo repository.SpecRepo
+	Repo   repository.SpecRepo
+	Tokens TokenIssuer
+	Events Publisher
+}
+
+// TokenIssuer issues the token returned by Login, subject is the email of the user
+type TokenIssuer interface {
+	GenerateToken(subject string) (string, error)
+}
+
+// Publisher delivers the events of the writes of the service to their subscribers
+type Publisher interface {
+	Publish(ctx context.Context, event events.Event)
+}
+
+// UnblockResult tells which relationships between two users are active again after an unblock.
+// Relationships which are not restored have to be recreated.
+type UnblockResult struct {
+	FriendshipRestored   bool
+	SubscriptionRestored bool
+}
+
+// Recipients are the users receiving an update of a sender. Unresolved are the emails mentioned in the update
+// which receive nothing: they are not users, or they have a blocking relationship with the sender.
+type Recipients struct {
+	Emails     []string
+	Unresolved []string
+}
+
+// RecipientsPage is a page of the users receiving an update of a sender, see Recipients
+type RecipientsPage struct {
+	pagination.Result
+	Unresolved []string
+}
+
+// PublishedUpdate is an update stored for a sender with the number of users it is delivered to,
+// Unresolved are the mentions receiving nothing like in Recipients
+type PublishedUpdate struct {
+	ID             int
+	RecipientCount int
+	Unresolved     []string
+}
+
+// User is the public profile of a user, credentials and role are left out
+type User struct {
+	ID        int
+	Name      string
+	Email     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
-func NewFriendService(repo repository.SpecRepo) FriendService {
+func NewFriendService(repo repository.SpecRepo, tokens TokenIssuer, events Publisher) FriendService {
 	return FriendService{
-		Repo: repo,
+		Repo:   repo,
+		Tokens: tokens,
+		Events: events,
 	}
 }
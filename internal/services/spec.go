@@ -2,15 +2,44 @@ package services
 
 import (
 	"context"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
 )
 
 // SpecRepo is the interface for repository methods
 type SpecService interface {
-	CreateFriend(ctx context.Context, userEmail string, friendEmail string) error
+	RemoveFriend(ctx context.Context, userEmail string, friendEmail string) error
 	GetFriends(ctx context.Context, userEmail string) ([]string, error)
 	GetCommonFriends(ctx context.Context, firstUserEmail string, secondUserEmail string) ([]string, error)
 	CreateSubscription(ctx context.Context, requestorEmail string, targetEmail string) error
+	RemoveSubscription(ctx context.Context, requestorEmail string, targetEmail string) error
 	CreateUserBlock(ctx context.Context, requestorEmail string, targetEmail string) error
-	GetRecipientEmails(ctx context.Context, senderEmail string, text string) ([]string, error)
+	RemoveUserBlock(ctx context.Context, requestorEmail string, targetEmail string) (UnblockResult, error)
+	GetRecipientEmails(ctx context.Context, senderEmail string, text string) (Recipients, error)
 	GetUsers(ctx context.Context) ([]string, error)
+	GetUsersPage(ctx context.Context, page pagination.Page) (pagination.Result, error)
+	GetFriendsPage(ctx context.Context, userEmail string, page pagination.Page) (pagination.Result, error)
+	PublishUpdate(ctx context.Context, senderEmail string, text string) (PublishedUpdate, error)
+	GetRecipientEmailsPage(ctx context.Context, senderEmail string, text string, page pagination.Page) (RecipientsPage, error)
+	GetSubscribersPage(ctx context.Context, userEmail string, page pagination.Page) (pagination.Result, error)
+	GetSubscriptionsPage(ctx context.Context, userEmail string, page pagination.Page) (pagination.Result, error)
+	GetBlockedUsersPage(ctx context.Context, userEmail string, page pagination.Page) (pagination.Result, error)
+	GetUser(ctx context.Context, email string) (User, error)
+	GetUsersByEmails(ctx context.Context, emails []string) ([]User, error)
+	CreateUser(ctx context.Context, name string, email string, password string) error
+	Login(ctx context.Context, email string, password string) (string, error)
+	GetUserRole(ctx context.Context, email string) (string, error)
+	UpdateUser(ctx context.Context, email string, name string, newEmail string) error
+	DeleteUser(ctx context.Context, email string) error
+	SendFriendRequest(ctx context.Context, requestorEmail string, targetEmail string) error
+	AcceptFriendRequest(ctx context.Context, requestorEmail string, targetEmail string) error
+	DeclineFriendRequest(ctx context.Context, requestorEmail string, targetEmail string) error
+	CancelFriendRequest(ctx context.Context, requestorEmail string, targetEmail string) error
+	GetIncomingFriendRequests(ctx context.Context, userEmail string) ([]string, error)
+	GetOutgoingFriendRequests(ctx context.Context, userEmail string) ([]string, error)
+	CreateWebhook(ctx context.Context, url string, secret string, events []webhooks.Event) (webhooks.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]webhooks.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookId int) error
+	GetWebhookDeliveries(ctx context.Context, webhookId int, limit int) ([]webhooks.DeliveryLog, error)
 }
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+)
+
+// Store an update of sender and deliver it to the recipients of GetRecipientEmails, the update, its deliveries
+// and the notifications of the recipients are stored together or not at all
+func (_self FriendService) PublishUpdate(ctx context.Context, senderEmail string, text string) (PublishedUpdate, error) {
+	// Check existed email and get userID
+	senderID, err := _self.Repo.GetUserIDByEmail(ctx, senderEmail)
+	if err != nil {
+		return PublishedUpdate{}, errs.NewUserLookupError(senderEmail, err)
+	}
+
+	mentions, err := _self.resolveMentions(ctx, senderID, senderEmail, text)
+	if err != nil {
+		return PublishedUpdate{}, err
+	}
+	updateID, recipients, err := _self.Repo.CreateUpdate(ctx, senderID, text, mentions.Emails, notify.NewUpdatePublished(senderEmail, text))
+	if err != nil {
+		return PublishedUpdate{}, errs.NewUnavailableError(err)
+	}
+
+	emails := userEmails(recipients)
+	_self.publishUpdate(ctx, senderEmail, text, emails)
+	return PublishedUpdate{ID: updateID, RecipientCount: len(emails), Unresolved: mentions.Unresolved}, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServices_PublishUpdate(t *testing.T) {
+	type mockGetUserID struct {
+		result int
+		err    error
+	}
+	type mockGetUsers struct {
+		result models.UserSlice
+		err    error
+	}
+	type mockCreateUpdate struct {
+		mentionedEmails []string
+		result          int
+		recipients      models.UserSlice
+		err             error
+	}
+
+	tcs := map[string]struct {
+		senderEmail     string
+		text            string
+		expResult       PublishedUpdate
+		expError        error
+		mockUser        mockGetUserID
+		mockMentionable mockGetUsers
+		mockUpdate      mockCreateUpdate
+	}{
+		"success with an input": {
+			senderEmail: "andy@example.com",
+			text:        "hello",
+			expResult:   PublishedUpdate{ID: 7, RecipientCount: 2, Unresolved: []string{}},
+			mockUser: mockGetUserID{
+				result: 100,
+			},
+			mockUpdate: mockCreateUpdate{
+				mentionedEmails: []string{},
+				result:          7,
+				recipients: models.UserSlice{
+					&models.User{Email: "john@example.com"},
+					&models.User{Email: "lisa@example.com"},
+				},
+			},
+		},
+		"success with resolved and unresolved mentions": {
+			senderEmail: "andy@example.com",
+			text:        "hi kate@example.com and bob@example.com",
+			expResult:   PublishedUpdate{ID: 8, RecipientCount: 2, Unresolved: []string{"bob@example.com"}},
+			mockUser: mockGetUserID{
+				result: 100,
+			},
+			mockMentionable: mockGetUsers{
+				result: models.UserSlice{
+					&models.User{Email: "kate@example.com"},
+				},
+			},
+			mockUpdate: mockCreateUpdate{
+				mentionedEmails: []string{"kate@example.com"},
+				result:          8,
+				recipients: models.UserSlice{
+					&models.User{Email: "john@example.com"},
+					&models.User{Email: "kate@example.com"},
+				},
+			},
+		},
+		"failed with an unavailable storage of the updates": {
+			senderEmail: "andy@example.com",
+			text:        "hello",
+			mockUser: mockGetUserID{
+				result: 100,
+			},
+			mockUpdate: mockCreateUpdate{
+				mentionedEmails: []string{},
+				recipients:      models.UserSlice{},
+				err:             errors.New("sql: database is closed"),
+			},
+			expError: errors.New(errs.MsgUnavailable),
+		},
+		"failed with an unknown sender": {
+			senderEmail: "test@example.com",
+			text:        "hello",
+			mockUser: mockGetUserID{
+				err: sql.ErrNoRows,
+			},
+			expError: errors.New(`test@example.com is not exists`),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", tc.senderEmail).
+					Return(tc.mockUser.result, tc.mockUser.err),
+
+				mockRepo.On("GetMentionableUsers", mock.Anything, mock.Anything, mock.Anything).
+					Return(tc.mockMentionable.result, tc.mockMentionable.err),
+
+				mockRepo.On("CreateUpdate", mock.Anything, tc.mockUser.result, tc.text, tc.mockUpdate.mentionedEmails,
+					notify.NewUpdatePublished(tc.senderEmail, tc.text)).
+					Return(tc.mockUpdate.result, tc.mockUpdate.recipients, tc.mockUpdate.err),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			result, err := friendService.PublishUpdate(ctx, tc.senderEmail, tc.text)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// A bcrypt hash of the same cost as the stored passwords, compared for an unknown email so
+// that Login takes as long as for a registered one and does not reveal which emails exist
+const dummyPasswordHash = "$2a$10$WZ.bV87N91XLqd/w80T5xuxw6D4Gp30.oMbqpdqx7d026nPaUk0pu"
+
+// Create a new user with a name, an unused email and a password which is stored as a bcrypt hash
+func (_self FriendService) CreateUser(ctx context.Context, name string, email string, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return errs.NewInternalError(err)
+	}
+
+	if _, err := _self.Repo.CreateUser(ctx, name, email, string(hashedPassword)); err != nil {
+		if errors.Is(err, repository.ErrDuplicatedEmail) {
+			return errs.NewEmailTakenError(email)
+		}
+		return errs.NewUnavailableError(err)
+	}
+
+	return nil
+}
+
+// Verify email and password of a user, return a token which authenticates the user in later requests
+func (_self FriendService) Login(ctx context.Context, email string, password string) (string, error) {
+	user, err := _self.Repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
+			return "", errs.NewInvalidCredentialsError()
+		}
+		return "", errs.NewUnavailableError(err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return "", errs.NewInvalidCredentialsError()
+	}
+
+	token, err := _self.Tokens.GenerateToken(user.Email)
+	if err != nil {
+		return "", errs.NewInternalError(err)
+	}
+
+	return token, nil
+}
+
+// Get the profile of the user having email
+func (_self FriendService) GetUser(ctx context.Context, email string) (User, error) {
+	user, err := _self.Repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return User{}, errs.NewUserLookupError(email, err)
+	}
+
+	return toUser(user), nil
+}
+
+// Get the profiles of the users having emails, unknown emails are left out
+func (_self FriendService) GetUsersByEmails(ctx context.Context, emails []string) ([]User, error) {
+	users, err := _self.Repo.GetUsersByEmails(ctx, emails)
+	if err != nil {
+		return nil, errs.NewUnavailableError(err)
+	}
+
+	result := make([]User, len(users))
+	for i, user := range users {
+		result[i] = toUser(user)
+	}
+	return result, nil
+}
+
+// Change name and/or email of the user having email, an empty value keeps the current one
+func (_self FriendService) UpdateUser(ctx context.Context, email string, name string, newEmail string) error {
+	userId, err := _self.Repo.GetUserIDByEmail(ctx, email)
+	if err != nil {
+		return errs.NewUserLookupError(email, err)
+	}
+
+	if err := _self.Repo.UpdateUser(ctx, userId, name, newEmail); err != nil {
+		if errors.Is(err, repository.ErrDuplicatedEmail) {
+			return errs.NewEmailTakenError(newEmail)
+		}
+		return errs.NewUnavailableError(err)
+	}
+
+	return nil
+}
+
+// Delete the user having email together with friendships, subscriptions, blocks and friend requests of the user
+func (_self FriendService) DeleteUser(ctx context.Context, email string) error {
+	userId, err := _self.Repo.GetUserIDByEmail(ctx, email)
+	if err != nil {
+		return errs.NewUserLookupError(email, err)
+	}
+
+	if err := _self.Repo.DeleteUser(ctx, userId); err != nil {
+		return errs.NewUserLookupError(email, err)
+	}
+
+	return nil
+}
+
+// Get the role of the user having email, it is checked by the authorization rules of the API
+func (_self FriendService) GetUserRole(ctx context.Context, email string) (string, error) {
+	user, err := _self.Repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return "", errs.NewUserLookupError(email, err)
+	}
+
+	return user.Role, nil
+}
+
+// Get the profile of a user record
+func toUser(user *models.User) User {
+	return User{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/pkg/jwt"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestServices_CreateUser(t *testing.T) {
+	tcs := map[string]struct {
+		name     string
+		email    string
+		password string
+		mockErr  error
+		expError error
+	}{
+		"success with an input": {
+			name:     "tom",
+			email:    "tom@example.com",
+			password: "password",
+		},
+		"failed with an email is existing": {
+			name:     "andy",
+			email:    "andy@example.com",
+			password: "password",
+			mockErr:  repository.ErrDuplicatedEmail,
+			expError: errors.New(`andy@example.com has been used by another user`),
+		},
+		"failed with an inserting error": {
+			name:     "tom",
+			email:    "tom@example.com",
+			password: "password",
+			mockErr:  errors.New(`sql: database is closed`),
+			expError: errors.New(errs.MsgUnavailable),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("CreateUser", mock.Anything, tc.name, tc.email, mock.MatchedBy(func(hash string) bool {
+					return bcrypt.CompareHashAndPassword([]byte(hash), []byte(tc.password)) == nil
+				})).Return(105, tc.mockErr),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			err := friendService.CreateUser(ctx, tc.name, tc.email, tc.password)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+// newTestTokens creates a token manager signing with a HS256 test key
+func newTestTokens(t *testing.T) *jwt.Manager {
+	key, err := jwt.NewSecretKey("test", []byte("test-secret-with-at-least-32-bytes"))
+	require.NoError(t, err)
+	tokens, err := jwt.NewManager(jwt.Config{
+		Issuer:       "test",
+		Audience:     "test",
+		TTL:          time.Hour,
+		SigningKeyID: key.ID,
+		Keys:         []jwt.Key{key},
+	})
+	require.NoError(t, err)
+	return tokens
+}
+
+func TestServices_LoginDummyHash(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+	require.NoError(t, err)
+	require.Equal(t, bcrypt.DefaultCost, cost)
+}
+
+func TestServices_Login(t *testing.T) {
+	type mockGetUser struct {
+		result *models.User
+		err    error
+	}
+
+	tokens := newTestTokens(t)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		email    string
+		password string
+		mockUser mockGetUser
+		expError error
+	}{
+		"success with an input": {
+			email:    "andy@example.com",
+			password: "password",
+			mockUser: mockGetUser{
+				result: &models.User{ID: 101, Email: "andy@example.com", Password: string(hashedPassword)},
+			},
+		},
+		"failed with an incorrect password": {
+			email:    "andy@example.com",
+			password: "incorrect",
+			mockUser: mockGetUser{
+				result: &models.User{ID: 101, Email: "andy@example.com", Password: string(hashedPassword)},
+			},
+			expError: errors.New(`Email or password is incorrect`),
+		},
+		"failed with an unknow format input": {
+			email:    "test@example.com",
+			password: "password",
+			mockUser: mockGetUser{
+				result: (*models.User)(nil),
+				err:    sql.ErrNoRows,
+			},
+			expError: errors.New(`Email or password is incorrect`),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserByEmail", mock.Anything, tc.email).
+					Return(tc.mockUser.result, tc.mockUser.err),
+			}
+			friendService := NewFriendService(mockRepo, tokens, nil)
+			token, err := friendService.Login(ctx, tc.email, tc.password)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				email, err := tokens.ParseToken(token)
+				require.NoError(t, err)
+				require.Equal(t, tc.email, email)
+			}
+		})
+	}
+}
+
+func TestServices_UpdateUser(t *testing.T) {
+	type mockGetUserID struct {
+		result int
+		err    error
+	}
+
+	tcs := map[string]struct {
+		email    string
+		name     string
+		newEmail string
+		mockUser mockGetUserID
+		mockErr  error
+		expError error
+	}{
+		"success with an input": {
+			email:    "andy@example.com",
+			name:     "andrew",
+			newEmail: "andrew@example.com",
+			mockUser: mockGetUserID{
+				result: 101,
+			},
+		},
+		"failed with an unknow format input": {
+			email: "test@example.com",
+			name:  "test",
+			mockUser: mockGetUserID{
+				err: sql.ErrNoRows,
+			},
+			expError: errors.New(`test@example.com is not exists`),
+		},
+		"failed with an new email is existing": {
+			email:    "andy@example.com",
+			newEmail: "john@example.com",
+			mockUser: mockGetUserID{
+				result: 101,
+			},
+			mockErr:  repository.ErrDuplicatedEmail,
+			expError: errors.New(`john@example.com has been used by another user`),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", tc.email).
+					Return(tc.mockUser.result, tc.mockUser.err),
+				mockRepo.On("UpdateUser", mock.Anything, tc.mockUser.result, tc.name, tc.newEmail).
+					Return(tc.mockErr),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			err := friendService.UpdateUser(ctx, tc.email, tc.name, tc.newEmail)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestServices_DeleteUser(t *testing.T) {
+	type mockGetUserID struct {
+		result int
+		err    error
+	}
+
+	tcs := map[string]struct {
+		email    string
+		mockUser mockGetUserID
+		mockErr  error
+		expError error
+	}{
+		"success with an input": {
+			email: "andy@example.com",
+			mockUser: mockGetUserID{
+				result: 101,
+			},
+		},
+		"failed with an unknow format input": {
+			email: "test@example.com",
+			mockUser: mockGetUserID{
+				err: sql.ErrNoRows,
+			},
+			expError: errors.New(`test@example.com is not exists`),
+		},
+		"failed with an user is deleted concurrently": {
+			email: "andy@example.com",
+			mockUser: mockGetUserID{
+				result: 101,
+			},
+			mockErr:  sql.ErrNoRows,
+			expError: errors.New(`andy@example.com is not exists`),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserIDByEmail", tc.email).
+					Return(tc.mockUser.result, tc.mockUser.err),
+				mockRepo.On("DeleteUser", mock.Anything, tc.mockUser.result).
+					Return(tc.mockErr),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			err := friendService.DeleteUser(ctx, tc.email)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestServices_GetUser(t *testing.T) {
+	createdAt := time.Date(2021, 11, 27, 10, 16, 58, 0, time.UTC)
+
+	tcs := map[string]struct {
+		email     string
+		mockUser  *models.User
+		mockErr   error
+		expResult User
+		expError  error
+	}{
+		"success without the credentials": {
+			email: "andy@example.com",
+			mockUser: &models.User{
+				ID: 101, Name: "andy", Email: "andy@example.com", Password: "hash", Role: "USER",
+				CreatedAt: createdAt, UpdatedAt: createdAt,
+			},
+			expResult: User{ID: 101, Name: "andy", Email: "andy@example.com", CreatedAt: createdAt, UpdatedAt: createdAt},
+		},
+		"failed with a non-existent user": {
+			email:    "test@example.com",
+			mockErr:  sql.ErrNoRows,
+			expError: errors.New(`test@example.com is not exists`),
+		},
+		"failed with a querying error": {
+			email:    "andy@example.com",
+			mockErr:  errors.New(`sql: database is closed`),
+			expError: errors.New(errs.MsgUnavailable),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUserByEmail", mock.Anything, tc.email).Return(tc.mockUser, tc.mockErr),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			result, err := friendService.GetUser(ctx, tc.email)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
+
+func TestServices_GetUsersByEmails(t *testing.T) {
+	tcs := map[string]struct {
+		emails    []string
+		mockUsers models.UserSlice
+		mockErr   error
+		expResult []User
+		expError  error
+	}{
+		"success with known emails": {
+			emails: []string{"andy@example.com", "test@example.com"},
+			mockUsers: models.UserSlice{
+				&models.User{ID: 101, Name: "andy", Email: "andy@example.com", Password: "hash"},
+			},
+			expResult: []User{{ID: 101, Name: "andy", Email: "andy@example.com"}},
+		},
+		"failed with a querying error": {
+			emails:   []string{"andy@example.com"},
+			mockErr:  errors.New(`sql: database is closed`),
+			expError: errors.New(errs.MsgUnavailable),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("GetUsersByEmails", mock.Anything, tc.emails).Return(tc.mockUsers, tc.mockErr),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			result, err := friendService.GetUsersByEmails(ctx, tc.emails)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
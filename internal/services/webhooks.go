@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+)
+
+// Register a webhook receiving the events of the list, an empty list receives every event
+func (_self FriendService) CreateWebhook(ctx context.Context, url string, secret string, events []webhooks.Event) (webhooks.Webhook, error) {
+	webhook, err := _self.Repo.CreateWebhook(ctx, url, secret, events)
+	if err != nil {
+		return webhooks.Webhook{}, errs.NewUnavailableError(err)
+	}
+
+	return webhook, nil
+}
+
+// Get all registered webhooks
+func (_self FriendService) GetWebhooks(ctx context.Context) ([]webhooks.Webhook, error) {
+	result, err := _self.Repo.GetWebhooks(ctx)
+	if err != nil {
+		return nil, errs.NewUnavailableError(err)
+	}
+
+	return result, nil
+}
+
+// Remove a webhook, its pending deliveries are not sent anymore
+func (_self FriendService) DeleteWebhook(ctx context.Context, webhookId int) error {
+	deleted, err := _self.Repo.DeleteWebhook(ctx, webhookId)
+	if err != nil {
+		return errs.NewUnavailableError(err)
+	}
+	if deleted == 0 {
+		return errs.NewNotFoundError(errs.CodeWebhookNotFound, errs.MsgNotExistedWebhook)
+	}
+
+	return nil
+}
+
+// Get up to limit deliveries of a webhook, the newest first
+func (_self FriendService) GetWebhookDeliveries(ctx context.Context, webhookId int, limit int) ([]webhooks.DeliveryLog, error) {
+	isExisted, err := _self.Repo.IsExistedWebhook(ctx, webhookId)
+	if err != nil {
+		return nil, errs.NewUnavailableError(err)
+	}
+	if !isExisted {
+		return nil, errs.NewNotFoundError(errs.CodeWebhookNotFound, errs.MsgNotExistedWebhook)
+	}
+
+	deliveries, err := _self.Repo.GetWebhookDeliveries(ctx, webhookId, limit)
+	if err != nil {
+		return nil, errs.NewUnavailableError(err)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// matchChange matches the webhook change of a repository write
+func matchChange(event webhooks.Event, requestorEmail string, targetEmail string) interface{} {
+	return mock.MatchedBy(func(change webhooks.Payload) bool {
+		return change.Event == event && change.Requestor == requestorEmail && change.Target == targetEmail &&
+			!change.OccurredAt.IsZero()
+	})
+}
+
+func TestServices_WebhookChanges(t *testing.T) {
+	tcs := map[string]struct {
+		mockCalls func(mockRepo *SpecRepo) []*mock.Call
+		write     func(service FriendService, ctx context.Context) error
+		expError  error
+	}{
+		"an accepted friend request": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("IsPendingFriendRequest", mock.Anything, 100, 101).Return(true, nil),
+					mockRepo.On("IsExistedFriend", mock.Anything, 100, 101).Return(false, nil),
+					mockRepo.On("IsBlockedUser", mock.Anything, 100, 101).Return(false, nil),
+					mockRepo.On("AcceptFriendRequest", mock.Anything, 100, 101, mock.Anything,
+						matchChange(webhooks.FriendAdded, "john@example.com", "andy@example.com")).Return(nil),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				return service.AcceptFriendRequest(ctx, "john@example.com", "andy@example.com")
+			},
+		},
+		"a removed friendship": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("IsExistedFriend", mock.Anything, 100, 101).Return(true, nil),
+					mockRepo.On("DeleteFriend", mock.Anything, 100, 101,
+						matchChange(webhooks.FriendRemoved, "john@example.com", "andy@example.com")).Return(nil),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				return service.RemoveFriend(ctx, "john@example.com", "andy@example.com")
+			},
+		},
+		"a new subscription": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("IsSubscribedUser", mock.Anything, 100, 101).Return(false, nil),
+					mockRepo.On("IsBlockedUser", mock.Anything, 100, 101).Return(false, nil),
+					mockRepo.On("CreateSubscription", mock.Anything, 100, 101,
+						matchChange(webhooks.SubscriptionCreated, "john@example.com", "andy@example.com")).Return(nil),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				return service.CreateSubscription(ctx, "john@example.com", "andy@example.com")
+			},
+		},
+		"a removed subscription": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("DeleteSubscription", mock.Anything, 100, 101,
+						matchChange(webhooks.SubscriptionRemoved, "john@example.com", "andy@example.com")).Return(int64(1), nil),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				return service.RemoveSubscription(ctx, "john@example.com", "andy@example.com")
+			},
+		},
+		"a new block": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("IsBlockedUser", mock.Anything, 100, 101).Return(false, nil),
+					mockRepo.On("CreateUserBlock", mock.Anything, 100, 101,
+						matchChange(webhooks.BlockCreated, "john@example.com", "andy@example.com")).Return(nil),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				return service.CreateUserBlock(ctx, "john@example.com", "andy@example.com")
+			},
+		},
+		"a removed block while the target still blocks the requestor": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("DeleteUserBlock", mock.Anything, 100, 101,
+						matchChange(webhooks.BlockRemoved, "john@example.com", "andy@example.com")).Return(int64(1), nil),
+					mockRepo.On("IsBlockedUser", mock.Anything, 100, 101).Return(true, nil),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				_, err := service.RemoveUserBlock(ctx, "john@example.com", "andy@example.com")
+				return err
+			},
+		},
+		"a change failing with its deliveries": {
+			mockCalls: func(mockRepo *SpecRepo) []*mock.Call {
+				return []*mock.Call{
+					mockRepo.On("IsBlockedUser", mock.Anything, 100, 101).Return(false, nil),
+					mockRepo.On("CreateUserBlock", mock.Anything, 100, 101, mock.Anything).Return(errors.New("connection refused")),
+				}
+			},
+			write: func(service FriendService, ctx context.Context) error {
+				return service.CreateUserBlock(ctx, "john@example.com", "andy@example.com")
+			},
+			expError: errs.NewUnavailableError(errors.New("connection refused")),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			ctx := context.Background()
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = append([]*mock.Call{
+				mockRepo.On("GetUserIDByEmail", "john@example.com").Return(100, nil),
+				mockRepo.On("GetUserIDByEmail", "andy@example.com").Return(101, nil),
+			}, tc.mockCalls(&mockRepo)...)
+
+			friendService := NewFriendService(mockRepo, nil, nil)
+			err := tc.write(friendService, ctx)
+			require.Equal(t, tc.expError, err)
+		})
+	}
+}
+
+func TestServices_CreateWebhook(t *testing.T) {
+	createdAt := time.Date(2022, 1, 23, 9, 0, 0, 0, time.UTC)
+	tcs := map[string]struct {
+		events    []webhooks.Event
+		mockErr   error
+		expResult webhooks.Webhook
+		expError  error
+	}{
+		"success": {
+			events: []webhooks.Event{webhooks.FriendAdded},
+			expResult: webhooks.Webhook{ID: 1, URL: "https://crm.example.com/hooks", Secret: "crm-secret-0123456789",
+				Events: []webhooks.Event{webhooks.FriendAdded}, CreatedAt: createdAt},
+		},
+		"failed with an unavailable storage": {
+			events:   []webhooks.Event{},
+			mockErr:  errors.New("sql: database is closed"),
+			expError: errors.New(errs.MsgUnavailable),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("CreateWebhook", mock.Anything, "https://crm.example.com/hooks", "crm-secret-0123456789", tc.events).
+					Return(tc.expResult, tc.mockErr),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			result, err := friendService.CreateWebhook(context.Background(), "https://crm.example.com/hooks", "crm-secret-0123456789", tc.events)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
+
+func TestServices_DeleteWebhook(t *testing.T) {
+	tcs := map[string]struct {
+		mockDeleted int64
+		mockErr     error
+		expError    error
+	}{
+		"success": {
+			mockDeleted: 1,
+		},
+		"failed with an unknown webhook": {
+			expError: errors.New(errs.MsgNotExistedWebhook),
+		},
+		"failed with an unavailable storage": {
+			mockErr:  errors.New("sql: database is closed"),
+			expError: errors.New(errs.MsgUnavailable),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("DeleteWebhook", mock.Anything, 7).Return(tc.mockDeleted, tc.mockErr),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			err := friendService.DeleteWebhook(context.Background(), 7)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestServices_GetWebhookDeliveries(t *testing.T) {
+	deliveries := []webhooks.DeliveryLog{
+		{ID: 2, WebhookID: 7, Event: webhooks.BlockCreated, Status: webhooks.StatusPending, Attempts: 1,
+			ResponseStatus: 503, LastError: "webhook: endpoint answered 503 Service Unavailable"},
+		{ID: 1, WebhookID: 7, Event: webhooks.FriendAdded, Status: webhooks.StatusDelivered, Attempts: 1, ResponseStatus: 200},
+	}
+	tcs := map[string]struct {
+		mockExisted    bool
+		mockExistedErr error
+		expResult      []webhooks.DeliveryLog
+		expError       error
+	}{
+		"success": {
+			mockExisted: true,
+			expResult:   deliveries,
+		},
+		"failed with an unknown webhook": {
+			expError: errors.New(errs.MsgNotExistedWebhook),
+		},
+		"failed with an unavailable storage": {
+			mockExistedErr: errors.New("sql: database is closed"),
+			expError:       errors.New(errs.MsgUnavailable),
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			var mockRepo SpecRepo
+			mockRepo.ExpectedCalls = []*mock.Call{
+				mockRepo.On("IsExistedWebhook", mock.Anything, 7).Return(tc.mockExisted, tc.mockExistedErr),
+				mockRepo.On("GetWebhookDeliveries", mock.Anything, 7, 50).Return(deliveries, nil),
+			}
+			friendService := NewFriendService(mockRepo, nil, nil)
+			result, err := friendService.GetWebhookDeliveries(context.Background(), 7, 50)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, result)
+			}
+		})
+	}
+}
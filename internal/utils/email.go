@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// Length limits of the parts of an email address (RFC 5321)
+const (
+	maxLocalPartLength   = 64
+	maxDomainLength      = 253
+	maxDomainLabelLength = 63
+)
+
+var ErrEmailInvalid = errors.New("invalid email address")
+
+// ParseEmail parses a whole string as a single email address like "andy@example.com" and returns
+// its canonical form: the surrounding spaces are trimmed, the domain is lowercased and
+// an internationalized domain is converted to its ASCII form, e.g. "andy@Bücher.de" is "andy@xn--bcher-kva.de".
+// Display names, comments, quoted local parts and domain literals are refused.
+func ParseEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Name != "" || address.Address != email {
+		return "", ErrEmailInvalid
+	}
+
+	at := strings.LastIndex(email, "@")
+	local, domain := email[:at], email[at+1:]
+	if len(local) > maxLocalPartLength || strings.HasPrefix(local, "\"") || !isASCII(local) {
+		return "", ErrEmailInvalid
+	}
+
+	domain, err = canonicalDomain(domain)
+	if err != nil {
+		return "", err
+	}
+	return local + "@" + domain, nil
+}
+
+// canonicalDomain maps a domain with IDNA (lowercase, NFC normalization) and converts its labels to ASCII,
+// so the spellings of a same domain have one form. The domain needs a top level domain of at least two letters like "example.com"
+func canonicalDomain(domain string) (string, error) {
+	domain, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return "", ErrEmailInvalid
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "", ErrEmailInvalid
+	}
+
+	for _, label := range labels {
+		if !isValidLabel(label) {
+			return "", ErrEmailInvalid
+		}
+	}
+
+	tld := labels[len(labels)-1]
+	if !strings.HasPrefix(tld, "xn--") && (len(tld) < 2 || strings.Trim(tld, "abcdefghijklmnopqrstuvwxyz") != "") {
+		return "", ErrEmailInvalid
+	}
+
+	domain = strings.Join(labels, ".")
+	if len(domain) > maxDomainLength {
+		return "", ErrEmailInvalid
+	}
+	return domain, nil
+}
+
+// isValidLabel tells whether a label of a domain only has letters, digits and inner hyphens
+func isValidLabel(label string) bool {
+	if label == "" || len(label) > maxDomainLabelLength || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, c := range label {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUtils_ParseEmail(t *testing.T) {
+	tcs := map[string]struct {
+		input    string
+		expEmail string
+		expError error
+	}{
+		"valid email":                    {input: "andy@example.com", expEmail: "andy@example.com"},
+		"surrounding spaces are trimmed": {input: "  andy@example.com\t", expEmail: "andy@example.com"},
+		"domain is lowercased":           {input: "Andy@Example.COM", expEmail: "Andy@example.com"},
+		"internationalized domain":       {input: "andy@Bücher.de", expEmail: "andy@xn--bcher-kva.de"},
+		"decomposed domain":              {input: "andy@Bu\u0308cher.de", expEmail: "andy@xn--bcher-kva.de"},
+		"subdomain":                      {input: "andy.lee+news@mail.example.co.uk", expEmail: "andy.lee+news@mail.example.co.uk"},
+		"email inside a text":            {input: "garbage a@b.co garbage", expError: ErrEmailInvalid},
+		"email after a text":             {input: "garbage a@b.co", expError: ErrEmailInvalid},
+		"display name":                   {input: "Andy <andy@example.com>", expError: ErrEmailInvalid},
+		"comment":                        {input: "andy@example.com (Andy)", expError: ErrEmailInvalid},
+		"quoted local part":              {input: `"andy lee"@example.com`, expError: ErrEmailInvalid},
+		"domain literal":                 {input: "andy@[127.0.0.1]", expError: ErrEmailInvalid},
+		"missing top level domain":       {input: "andy@example", expError: ErrEmailInvalid},
+		"numeric top level domain":       {input: "andy@example.123", expError: ErrEmailInvalid},
+		"hyphen at the end of a label":   {input: "andy@example-.com", expError: ErrEmailInvalid},
+		"underscore in the domain":       {input: "andy@exa_mple.com", expError: ErrEmailInvalid},
+		"missing at sign":                {input: "andy.example.com", expError: ErrEmailInvalid},
+		"empty":                          {input: "", expError: ErrEmailInvalid},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			email, err := ParseEmail(tc.input)
+			if tc.expError != nil {
+				require.EqualError(t, err, tc.expError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expEmail, email)
+			}
+		})
+	}
+}
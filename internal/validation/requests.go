@@ -0,0 +1,44 @@
+package validation
+
+import "github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+
+type FriendRequest struct {
+	Emails []string `json:"friends"`
+}
+
+type UserRequest struct {
+	Email string `json:"email"`
+}
+
+type RequestorRequest struct {
+	Requestor string `json:"requestor"`
+	Target    string `json:"target"`
+}
+
+type RecipientsRequest struct {
+	Sender string `json:"sender"`
+	Text   string `json:"text"`
+}
+
+type NewUserRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type UpdateUserRequest struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	NewEmail string `json:"new_email"`
+}
+
+type WebhookRequest struct {
+	URL    string           `json:"url"`
+	Secret string           `json:"secret"`
+	Events []webhooks.Event `json:"events"`
+}
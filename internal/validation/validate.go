@@ -0,0 +1,227 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/errs"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/utils"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+)
+
+// Length of name and email columns in users table
+const maxUserFieldLength = 100
+
+// Length limits of a password, bcrypt only uses the first 72 bytes
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+// Length limits of the URL and the secret of a webhook
+const (
+	maxWebhookURLLength    = 2048
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 256
+)
+
+// fieldErrors collects every invalid field of a request
+type fieldErrors []*errs.FieldError
+
+func (_self *fieldErrors) add(field string, err error) {
+	*_self = append(*_self, &errs.FieldError{Field: field, Err: err})
+}
+
+// email adds the error of the field when the email is invalid or too long, a valid email is replaced
+// by its canonical form
+func (_self *fieldErrors) email(field string, email *string) {
+	canonical, err := utils.ParseEmail(*email)
+	if err != nil || len(canonical) > maxUserFieldLength {
+		*_self = append(*_self, errs.NewEmailFieldError(field, *email))
+		return
+	}
+	*email = canonical
+}
+
+// err is the validation error of the collected fields, it is nil without any invalid field
+func (_self fieldErrors) err() error {
+	if len(_self) == 0 {
+		return nil
+	}
+	return &errs.ValidationError{Fields: _self}
+}
+
+func isValidName(name string) bool {
+	return strings.TrimSpace(name) != "" && len(name) <= maxUserFieldLength
+}
+
+// Validate to body of friend request, the emails of the valid requests are replaced by their canonical form
+func (_self *FriendRequest) Validate() error {
+	if _self.Emails == nil && len(_self.Emails) == 0 {
+		return errs.ErrBodyRequestEmpty
+	}
+
+	var fields fieldErrors
+	if len(_self.Emails) != 2 {
+		fields.add("friends", errs.ErrNumberOfEmail)
+	}
+	for i := range _self.Emails {
+		fields.email(fmt.Sprintf("friends[%d]", i), &_self.Emails[i])
+	}
+	if len(fields) == 0 && _self.Emails[0] == _self.Emails[1] {
+		fields.add("friends", errs.ErrDifferentEmail)
+	}
+	return fields.err()
+}
+
+// Validate to body of user request
+func (_self *UserRequest) Validate() error {
+	if _self.Email == "" {
+		return errs.ErrBodyRequestEmpty
+	}
+
+	var fields fieldErrors
+	fields.email("email", &_self.Email)
+	return fields.err()
+}
+
+// Validate to body of requestor request
+func (_self *RequestorRequest) Validate() error {
+	if _self.Requestor == "" && _self.Target == "" {
+		return errs.ErrBodyRequestEmpty
+	}
+
+	var fields fieldErrors
+	if _self.Requestor == "" {
+		fields.add("requestor", errs.ErrRequestorFieldInvalid)
+	}
+	if _self.Target == "" {
+		fields.add("target", errs.ErrTargetFieldInvalid)
+	}
+	if _self.Requestor != "" {
+		fields.email("requestor", &_self.Requestor)
+	}
+	if _self.Target != "" {
+		fields.email("target", &_self.Target)
+	}
+	if len(fields) == 0 && _self.Target == _self.Requestor {
+		fields.add("target", errs.ErrDifferentEmail)
+	}
+	return fields.err()
+}
+
+// Validate to body of recipient request
+func (_self *RecipientsRequest) Validate() error {
+	if _self.Sender == "" && _self.Text == "" {
+		return errs.ErrBodyRequestEmpty
+	}
+
+	var fields fieldErrors
+	if _self.Sender == "" {
+		fields.add("sender", errs.ErrSenderFieldInvalid)
+	} else {
+		fields.email("sender", &_self.Sender)
+	}
+	if _self.Text == "" {
+		fields.add("text", errs.ErrTextFieldInvalid)
+	}
+	return fields.err()
+}
+
+// Validate to body of new user request
+func (_self *NewUserRequest) Validate() error {
+	if _self.Name == "" && _self.Email == "" {
+		return errs.ErrBodyRequestEmpty
+	}
+
+	var fields fieldErrors
+	if !isValidName(_self.Name) {
+		fields.add("name", errs.ErrNameFieldInvalid)
+	}
+	fields.email("email", &_self.Email)
+	if len(_self.Password) < minPasswordLength || len(_self.Password) > maxPasswordLength {
+		fields.add("password", errs.ErrPasswordFieldInvalid)
+	}
+	return fields.err()
+}
+
+// Validate to body of update user request
+func (_self *UpdateUserRequest) Validate() error {
+	if _self.Email == "" {
+		return errs.ErrBodyRequestEmpty
+	}
+
+	var fields fieldErrors
+	fields.email("email", &_self.Email)
+	if _self.Name == "" && _self.NewEmail == "" {
+		fields.add("", errs.ErrUpdateFieldEmpty)
+	}
+	if _self.Name != "" && !isValidName(_self.Name) {
+		fields.add("name", errs.ErrNameFieldInvalid)
+	}
+	if _self.NewEmail != "" {
+		fields.email("newEmail", &_self.NewEmail)
+	}
+	return fields.err()
+}
+
+// Validate to body of login request
+func (_self *LoginRequest) Validate() error {
+	if _self.Email == "" && _self.Password == "" {
+		return errs.ErrBodyRequestEmpty
+	}
+
+	var fields fieldErrors
+	fields.email("email", &_self.Email)
+	if _self.Password == "" {
+		fields.add("password", errs.ErrPasswordFieldInvalid)
+	}
+	return fields.err()
+}
+
+// Validate to body of webhook request, the repeated events are removed
+func (_self *WebhookRequest) Validate() error {
+	if _self.URL == "" && _self.Secret == "" {
+		return errs.ErrBodyRequestEmpty
+	}
+
+	var fields fieldErrors
+	if !isValidWebhookURL(_self.URL) {
+		fields.add("url", errs.ErrWebhookURLInvalid)
+	}
+	if len(_self.Secret) < minWebhookSecretLength || len(_self.Secret) > maxWebhookSecretLength {
+		fields.add("secret", errs.ErrWebhookSecretInvalid)
+	}
+
+	known := make(map[webhooks.Event]bool)
+	for _, event := range webhooks.Events {
+		known[event] = true
+	}
+	events := make([]webhooks.Event, 0)
+	seen := make(map[webhooks.Event]bool)
+	for i, event := range _self.Events {
+		if !known[event] {
+			fields.add(fmt.Sprintf("events[%d]", i), errs.ErrWebhookEventInvalid)
+			continue
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	_self.Events = events
+	return fields.err()
+}
+
+// isValidWebhookURL tells whether a URL is an absolute http or https URL without credentials
+func isValidWebhookURL(rawURL string) bool {
+	if len(rawURL) > maxWebhookURLLength {
+		return false
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || parsed.User != nil {
+		return false
+	}
+	return parsed.Scheme == "http" || parsed.Scheme == "https"
+}
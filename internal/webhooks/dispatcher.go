@@ -0,0 +1,119 @@
+package webhooks
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
+)
+
+// Store keeps the deliveries of the webhooks with the result of their attempts, responseStatus 0 means the
+// endpoint did not answer
+type Store interface {
+	// ClaimWebhookDeliveries returns up to limit pending deliveries due at now and counts one more attempt for each,
+	// they are not claimed again before now + lease so a crashed dispatcher only delays them
+	ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Delivery, error)
+	MarkWebhookDelivered(ctx context.Context, id int, responseStatus int) error
+	// RetryWebhookDelivery keeps a delivery pending until nextAttemptAt
+	RetryWebhookDelivery(ctx context.Context, id int, nextAttemptAt time.Time, responseStatus int, lastError string) error
+	// DeadLetterWebhookDelivery stops the attempts of a delivery, it stays in the log with its last error
+	DeadLetterWebhookDelivery(ctx context.Context, id int, responseStatus int, lastError string) error
+}
+
+// Dispatcher posts the pending deliveries to their webhooks. A delivery is sent at least once: it is sent again
+// when the dispatcher stops between the post and its record, the receivers use the delivery header to skip
+// the duplicates.
+type Dispatcher struct {
+	Store       Store
+	Sender      Sender
+	Backoff     notify.Backoff
+	MaxAttempts int
+	BatchSize   int
+	Interval    time.Duration
+	// Time allowed to a sender for a delivery, the claim of the delivery lasts twice as long
+	Timeout time.Duration
+	// Now is the clock of the dispatcher, nil is time.Now
+	Now func() time.Time
+}
+
+func NewDispatcher(store Store, sender Sender, cfg Config) Dispatcher {
+	return Dispatcher{
+		Store:       store,
+		Sender:      sender,
+		Backoff:     notify.Backoff{Base: cfg.BackoffBase, Max: cfg.BackoffMax},
+		MaxAttempts: cfg.MaxAttempts,
+		BatchSize:   cfg.BatchSize,
+		Interval:    cfg.PollInterval,
+		Timeout:     cfg.Timeout,
+	}
+}
+
+// Run polls the deliveries every interval until ctx is done
+func (_self Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(_self.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := _self.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("webhooks: dispatch failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch posts the due deliveries until none is left and returns the number of deliveries accepted by their
+// webhooks. A failed delivery is retried after its backoff, or dead once it reached the maximum of attempts.
+func (_self Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		deliveries, err := _self.Store.ClaimWebhookDeliveries(ctx, _self.now(), 2*_self.Timeout, _self.BatchSize)
+		if err != nil {
+			return delivered, err
+		}
+
+		for _, delivery := range deliveries {
+			ok, err := _self.deliver(ctx, delivery)
+			if err != nil {
+				return delivered, err
+			}
+			if ok {
+				delivered++
+			}
+		}
+		if len(deliveries) == 0 || len(deliveries) < _self.BatchSize {
+			return delivered, nil
+		}
+	}
+}
+
+// deliver posts a delivery and records the result, ok tells whether it was accepted
+func (_self Dispatcher) deliver(ctx context.Context, delivery Delivery) (ok bool, err error) {
+	sendCtx, cancel := context.WithTimeout(ctx, _self.Timeout)
+	responseStatus, sendErr := _self.Sender.Send(sendCtx, delivery)
+	cancel()
+
+	switch {
+	case sendErr == nil:
+		return true, _self.Store.MarkWebhookDelivered(ctx, delivery.ID, responseStatus)
+	case delivery.Attempts >= _self.MaxAttempts:
+		log.Printf("webhooks: delivery %d of %s to webhook %d is dead after %d attempts: %v",
+			delivery.ID, delivery.Event, delivery.WebhookID, delivery.Attempts, sendErr)
+		return false, _self.Store.DeadLetterWebhookDelivery(ctx, delivery.ID, responseStatus, sendErr.Error())
+	default:
+		nextAttemptAt := _self.now().Add(_self.Backoff.Delay(delivery.Attempts))
+		return false, _self.Store.RetryWebhookDelivery(ctx, delivery.ID, nextAttemptAt, responseStatus, sendErr.Error())
+	}
+}
+
+func (_self Dispatcher) now() time.Time {
+	if _self.Now != nil {
+		return _self.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,206 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore keeps the deliveries in memory, a delivery is due when it is pending and its next attempt has come
+type memoryStore struct {
+	deliveries map[int]*storedDelivery
+	claimErr   error
+}
+
+type storedDelivery struct {
+	Delivery
+	status         string
+	nextAttemptAt  time.Time
+	responseStatus int
+	lastError      string
+}
+
+func newMemoryStore(deliveries ...Delivery) *memoryStore {
+	store := &memoryStore{deliveries: map[int]*storedDelivery{}}
+	for i, delivery := range deliveries {
+		delivery.ID = i + 1
+		store.deliveries[i+1] = &storedDelivery{Delivery: delivery, status: StatusPending}
+	}
+	return store
+}
+
+func (s *memoryStore) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Delivery, error) {
+	if s.claimErr != nil {
+		return nil, s.claimErr
+	}
+	claimed := []Delivery{}
+	for id := 1; id <= len(s.deliveries) && len(claimed) < limit; id++ {
+		delivery := s.deliveries[id]
+		if delivery.status != StatusPending || delivery.nextAttemptAt.After(now) {
+			continue
+		}
+		delivery.Attempts++
+		delivery.nextAttemptAt = now.Add(lease)
+		claimed = append(claimed, delivery.Delivery)
+	}
+	return claimed, nil
+}
+
+func (s *memoryStore) MarkWebhookDelivered(ctx context.Context, id int, responseStatus int) error {
+	s.deliveries[id].status = StatusDelivered
+	s.deliveries[id].responseStatus = responseStatus
+	return nil
+}
+
+func (s *memoryStore) RetryWebhookDelivery(ctx context.Context, id int, nextAttemptAt time.Time, responseStatus int, lastError string) error {
+	s.deliveries[id].nextAttemptAt = nextAttemptAt
+	s.deliveries[id].responseStatus = responseStatus
+	s.deliveries[id].lastError = lastError
+	return nil
+}
+
+func (s *memoryStore) DeadLetterWebhookDelivery(ctx context.Context, id int, responseStatus int, lastError string) error {
+	s.deliveries[id].status = StatusDead
+	s.deliveries[id].responseStatus = responseStatus
+	s.deliveries[id].lastError = lastError
+	return nil
+}
+
+// newFlakyReceiver is an endpoint failing its first failures requests with 500, the following ones are accepted
+// when their signature is valid
+func newFlakyReceiver(t *testing.T, secret string, failures int32) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if atomic.AddInt32(&calls, 1) <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if err := Verify(secret, r.Header, body, time.Hour, time.Now()); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	now := time.Date(2022, 1, 23, 9, 0, 0, 0, time.UTC)
+	crm, crmCalls := newFlakyReceiver(t, "crm-secret-0123456789", 0)
+	analytics, analyticsCalls := newFlakyReceiver(t, "analytics-secret-0123", 100)
+	store := newMemoryStore(
+		Delivery{WebhookID: 1, Event: FriendAdded, Payload: `{"event":"friend.added"}`, URL: crm.URL, Secret: "crm-secret-0123456789"},
+		Delivery{WebhookID: 2, Event: FriendAdded, Payload: `{"event":"friend.added"}`, URL: analytics.URL, Secret: "analytics-secret-0123"},
+		Delivery{WebhookID: 1, Event: BlockCreated, Payload: `{"event":"block.created"}`, URL: crm.URL, Secret: "crm-secret-0123456789"},
+	)
+	dispatcher := NewDispatcher(store, NewHTTPSender(5*time.Second), Config{
+		BatchSize:   2,
+		MaxAttempts: 3,
+		BackoffBase: time.Minute,
+		BackoffMax:  time.Hour,
+		Timeout:     5 * time.Second,
+	})
+	// The signatures are made at the real time, the clock of the dispatcher only decides the due deliveries
+	dispatcher.Now = func() time.Time { return now }
+
+	// Every batch is dispatched, the failed delivery waits for its backoff
+	count, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, int32(2), atomic.LoadInt32(crmCalls))
+	require.Equal(t, StatusDelivered, store.deliveries[1].status)
+	require.Equal(t, http.StatusOK, store.deliveries[1].responseStatus)
+	require.Equal(t, StatusDelivered, store.deliveries[3].status)
+	require.Equal(t, StatusPending, store.deliveries[2].status)
+	require.Equal(t, http.StatusInternalServerError, store.deliveries[2].responseStatus)
+	require.Equal(t, "webhook: endpoint answered 500 Internal Server Error", store.deliveries[2].lastError)
+	require.Equal(t, now.Add(time.Minute), store.deliveries[2].nextAttemptAt)
+
+	count, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+	require.Equal(t, int32(1), atomic.LoadInt32(analyticsCalls))
+
+	// The delay doubles after each attempt until the last one
+	now = now.Add(time.Minute)
+	_, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, store.deliveries[2].Attempts)
+	require.Equal(t, now.Add(2*time.Minute), store.deliveries[2].nextAttemptAt)
+
+	now = now.Add(2 * time.Minute)
+	_, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, store.deliveries[2].Attempts)
+	require.Equal(t, StatusDead, store.deliveries[2].status)
+	require.Equal(t, int32(3), atomic.LoadInt32(analyticsCalls))
+
+	// A dead delivery is never attempted again
+	now = now.Add(24 * time.Hour)
+	count, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+	require.Equal(t, int32(3), atomic.LoadInt32(analyticsCalls))
+}
+
+func TestDispatcher_DispatchRecovered(t *testing.T) {
+	now := time.Date(2022, 1, 23, 9, 0, 0, 0, time.UTC)
+	crm, crmCalls := newFlakyReceiver(t, "crm-secret-0123456789", 1)
+	store := newMemoryStore(
+		Delivery{WebhookID: 1, Event: SubscriptionCreated, Payload: `{}`, URL: crm.URL, Secret: "crm-secret-0123456789"},
+	)
+	dispatcher := NewDispatcher(store, NewHTTPSender(5*time.Second), DefaultConfig())
+	dispatcher.Now = func() time.Time { return now }
+
+	count, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	// The retry is accepted, the error of the failed attempt is kept in the log
+	now = now.Add(DefaultConfig().BackoffBase)
+	count, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, int32(2), atomic.LoadInt32(crmCalls))
+	require.Equal(t, StatusDelivered, store.deliveries[1].status)
+	require.Equal(t, 2, store.deliveries[1].Attempts)
+	require.Equal(t, "webhook: endpoint answered 500 Internal Server Error", store.deliveries[1].lastError)
+}
+
+func TestDispatcher_DispatchFailedClaim(t *testing.T) {
+	store := newMemoryStore()
+	store.claimErr = errors.New("connection refused")
+	dispatcher := NewDispatcher(store, NewHTTPSender(time.Second), DefaultConfig())
+
+	count, err := dispatcher.Dispatch(context.Background())
+	require.EqualError(t, err, "connection refused")
+	require.Equal(t, 0, count)
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	crm, crmCalls := newFlakyReceiver(t, "crm-secret-0123456789", 0)
+	store := newMemoryStore(Delivery{WebhookID: 1, Event: BlockRemoved, Payload: `{}`, URL: crm.URL, Secret: "crm-secret-0123456789"})
+	cfg := DefaultConfig()
+	cfg.PollInterval = time.Millisecond
+	dispatcher := NewDispatcher(store, NewHTTPSender(5*time.Second), cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// The deliveries are dispatched once before the end of ctx is noticed, a cancelled post is retried later
+	dispatcher.Run(ctx)
+	require.Equal(t, int32(0), atomic.LoadInt32(crmCalls))
+	require.Equal(t, StatusPending, store.deliveries[1].status)
+	require.Equal(t, 1, store.deliveries[1].Attempts)
+}
@@ -0,0 +1,108 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of a delivery, the signature is "sha256=" followed by Sign of the timestamp and the body
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// Sign is the hex HMAC-SHA256 with secret of the timestamp in Unix seconds, a dot and the body. The timestamp is
+// part of the signature so a receiver can refuse the replays of an old delivery.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the timestamp and signature headers of a delivery received at now, the timestamp must be within
+// tolerance of now
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return fmt.Errorf("webhook: invalid timestamp %q", header.Get(HeaderTimestamp))
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("webhook: timestamp %d out of tolerance", timestamp)
+	}
+
+	signature := header.Get(HeaderSignature)
+	if !strings.HasPrefix(signature, signaturePrefix) ||
+		!hmac.Equal([]byte(signature[len(signaturePrefix):]), []byte(Sign(secret, timestamp, body))) {
+		return fmt.Errorf("webhook: invalid signature")
+	}
+	return nil
+}
+
+// HTTPSender posts the deliveries as signed JSON, an answer outside 2xx is a failure. Redirects are not followed.
+type HTTPSender struct {
+	Client *http.Client
+	// Now is the clock of the signatures, nil is time.Now
+	Now func() time.Time
+}
+
+func NewHTTPSender(timeout time.Duration) HTTPSender {
+	return HTTPSender{
+		Client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send posts the payload of a delivery to the URL of its webhook
+func (_self HTTPSender) Send(ctx context.Context, delivery Delivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("webhook: %w", err)
+	}
+
+	timestamp := _self.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "FriendManagement-Webhooks")
+	req.Header.Set(HeaderEvent, string(delivery.Event))
+	req.Header.Set(HeaderDelivery, strconv.Itoa(delivery.ID))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, signaturePrefix+Sign(delivery.Secret, timestamp, body))
+
+	resp, err := _self.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	// The answer is not used, a short read lets the connection be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook: endpoint answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (_self HTTPSender) now() time.Time {
+	if _self.Now != nil {
+		return _self.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,157 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// receivedRequest is a delivery received by a test endpoint
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver is an endpoint answering status to every request, the requests are sent to received
+func newReceiver(t *testing.T, status int, received chan<- receivedRequest) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.MethodPost, r.Method)
+		received <- receivedRequest{header: r.Header, body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPSender_Send(t *testing.T) {
+	now := time.Date(2022, 1, 23, 9, 0, 0, 0, time.UTC)
+	received := make(chan receivedRequest, 1)
+	server := newReceiver(t, http.StatusNoContent, received)
+	sender := NewHTTPSender(5 * time.Second)
+	sender.Now = func() time.Time { return now }
+
+	status, err := sender.Send(context.Background(), Delivery{
+		ID:      7,
+		Event:   FriendAdded,
+		Payload: `{"event":"friend.added","requestor":"andy@example.com","target":"john@example.com"}`,
+		URL:     server.URL + "/hooks",
+		Secret:  "crm-secret-0123456789",
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, status)
+
+	request := <-received
+	require.Equal(t, `{"event":"friend.added","requestor":"andy@example.com","target":"john@example.com"}`, string(request.body))
+	require.Equal(t, "application/json", request.header.Get("Content-Type"))
+	require.Equal(t, "friend.added", request.header.Get(HeaderEvent))
+	require.Equal(t, "7", request.header.Get(HeaderDelivery))
+	require.Equal(t, strconv.FormatInt(now.Unix(), 10), request.header.Get(HeaderTimestamp))
+	require.Equal(t, "sha256="+Sign("crm-secret-0123456789", now.Unix(), request.body), request.header.Get(HeaderSignature))
+	require.NoError(t, Verify("crm-secret-0123456789", request.header, request.body, 5*time.Minute, now.Add(time.Minute)))
+}
+
+func TestHTTPSender_Errors(t *testing.T) {
+	tcs := map[string]struct {
+		url       func(t *testing.T) string
+		expStatus int
+		expError  string
+	}{
+		"endpoint failing": {
+			url: func(t *testing.T) string {
+				return newReceiver(t, http.StatusServiceUnavailable, make(chan receivedRequest, 1)).URL
+			},
+			expStatus: http.StatusServiceUnavailable,
+			expError:  "webhook: endpoint answered 503 Service Unavailable",
+		},
+		"redirect not followed": {
+			url: func(t *testing.T) string {
+				server := httptest.NewServer(http.RedirectHandler("http://example.com/", http.StatusFound))
+				t.Cleanup(server.Close)
+				return server.URL
+			},
+			expStatus: http.StatusFound,
+			expError:  "webhook: endpoint answered 302 Found",
+		},
+		"endpoint not listening": {
+			url: func(t *testing.T) string {
+				server := httptest.NewServer(http.NotFoundHandler())
+				server.Close()
+				return server.URL
+			},
+			expError: "connection refused",
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			sender := NewHTTPSender(5 * time.Second)
+
+			status, err := sender.Send(context.Background(), Delivery{ID: 1, Event: BlockCreated, Payload: `{}`, URL: tc.url(t), Secret: "secret"})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expError)
+			require.Equal(t, tc.expStatus, status)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2022, 1, 23, 9, 0, 0, 0, time.UTC)
+	body := []byte(`{"event":"block.created"}`)
+	signedHeader := func(secret string, timestamp time.Time) http.Header {
+		header := http.Header{}
+		header.Set(HeaderTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+		header.Set(HeaderSignature, "sha256="+Sign(secret, timestamp.Unix(), body))
+		return header
+	}
+
+	tcs := map[string]struct {
+		header   http.Header
+		body     []byte
+		expError string
+	}{
+		"valid signature": {
+			header: signedHeader("crm-secret-0123456789", now),
+			body:   body,
+		},
+		"other secret": {
+			header:   signedHeader("another-secret-0123", now),
+			body:     body,
+			expError: "webhook: invalid signature",
+		},
+		"changed body": {
+			header:   signedHeader("crm-secret-0123456789", now),
+			body:     []byte(`{"event":"block.removed"}`),
+			expError: "webhook: invalid signature",
+		},
+		"replayed delivery": {
+			header:   signedHeader("crm-secret-0123456789", now.Add(-10*time.Minute)),
+			body:     body,
+			expError: "out of tolerance",
+		},
+		"missing timestamp": {
+			header:   http.Header{},
+			body:     body,
+			expError: `webhook: invalid timestamp ""`,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			err := Verify("crm-secret-0123456789", tc.header, tc.body, 5*time.Minute, now)
+			if tc.expError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expError)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+)
+
+// Event is a change of a relationship between two users
+type Event string
+
+const (
+	FriendAdded         Event = "friend.added"
+	FriendRemoved       Event = "friend.removed"
+	SubscriptionCreated Event = "subscription.created"
+	SubscriptionRemoved Event = "subscription.removed"
+	BlockCreated        Event = "block.created"
+	BlockRemoved        Event = "block.removed"
+)
+
+// Events are all the events a webhook can receive
+var Events = []Event{FriendAdded, FriendRemoved, SubscriptionCreated, SubscriptionRemoved, BlockCreated, BlockRemoved}
+
+// Status values of a delivery
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+// Webhook is an endpoint receiving the events of its filter, an empty filter receives every event.
+// The secret signs the deliveries and is never shown again after the registration.
+type Webhook struct {
+	ID        int
+	URL       string
+	Secret    string
+	Events    []Event
+	CreatedAt time.Time
+}
+
+// Payload is the JSON body of a delivery, Requestor is the user making the change and Target the other party
+type Payload struct {
+	Event      Event     `json:"event"`
+	Requestor  string    `json:"requestor"`
+	Target     string    `json:"target"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Delivery is an event queued for a webhook, Attempts counts the deliveries tried so far including the current one.
+// URL and Secret are the ones of the webhook when the delivery is claimed.
+type Delivery struct {
+	ID        int
+	WebhookID int
+	Event     Event
+	Payload   string
+	Attempts  int
+	URL       string
+	Secret    string
+}
+
+// DeliveryLog is the state of a delivery, ResponseStatus is the HTTP status of the last attempt, 0 when the
+// endpoint did not answer, and LastError is empty until an attempt fails
+type DeliveryLog struct {
+	ID             int
+	WebhookID      int
+	Event          Event
+	Payload        string
+	Status         string
+	Attempts       int
+	ResponseStatus int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Sender posts a delivery to its webhook and returns the HTTP status of the answer, an error means the delivery
+// has to be sent again later
+type Sender interface {
+	Send(ctx context.Context, delivery Delivery) (int, error)
+}
+
+// Config sets the retries of the dispatcher
+type Config struct {
+	// Interval between two polls of the deliveries and number of deliveries claimed by a poll
+	PollInterval time.Duration
+	BatchSize    int
+	// A delivery failing MaxAttempts times is dead, the delay before the next attempt doubles from
+	// BackoffBase to BackoffMax
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// Time allowed to an endpoint to answer
+	Timeout time.Duration
+}
+
+// DefaultConfig tries a delivery 8 times within about an hour, an endpoint has 10 seconds to answer
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: 5 * time.Second,
+		BatchSize:    50,
+		MaxAttempts:  8,
+		BackoffBase:  30 * time.Second,
+		BackoffMax:   30 * time.Minute,
+		Timeout:      10 * time.Second,
+	}
+}
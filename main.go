@@ -1,19 +1,25 @@
 package main
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 
 	"github.com/99designs/gqlgen/graphql/playground"
-	"github.com/99designs/gqlgen/handler"
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/auth"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/controllers"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/events"
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph"
-	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/graph/generated"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/loaders"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/notify"
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
 	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/services"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/pkg/jwt"
 	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
 
 	"github.com/joho/godotenv"
 )
@@ -31,27 +37,89 @@ func main() {
 	}
 	defer config.CloseDatabase(db)
 
+	// Create the token manager from the configured key set
+	jwtConfig, err := config.NewJWTConfig()
+	if err != nil {
+		log.Fatal("JWT config error: ", err)
+	}
+	tokens, err := jwt.NewManager(jwtConfig)
+	if err != nil {
+		log.Fatal("JWT config error: ", err)
+	}
+
+	// Read the transports and extensions of the GraphQL server
+	graphqlConfig, err := config.NewGraphQLConfig()
+	if err != nil {
+		log.Fatal("GraphQL config error: ", err)
+	}
+
+	// Create a service shared by REST and GraphQL, its lookups are batched by the loaders of each request
+	// and its writes are pushed to the GraphQL subscriptions
+	dbRepo := repository.NewDBRepo(db)
+	broker := events.NewBroker()
+	friendService := services.NewFriendService(loaders.NewRepo(dbRepo), tokens, broker)
+
+	// Deliver the notifications of the outbox in the background
+	notifyConfig, err := config.NewNotifyConfig()
+	if err != nil {
+		log.Fatal("Notify config error: ", err)
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	dispatcher := notify.NewDispatcher(dbRepo, notify.NewNotifier(notifyConfig), notifyConfig)
+	go dispatcher.Run(ctx)
+
+	// Post the webhook deliveries in the background
+	webhookConfig, err := config.NewWebhookConfig()
+	if err != nil {
+		log.Fatal("Webhook config error: ", err)
+	}
+	webhookDispatcher := webhooks.NewDispatcher(dbRepo, webhooks.NewHTTPSender(webhookConfig.Timeout), webhookConfig)
+	go webhookDispatcher.Run(ctx)
+
 	//init routers
-	r := initRoutes(db)
+	r := initRoutes(friendService, broker, tokens, graphqlConfig)
 
 	// Start server
-	log.Printf("connect to http://localhost:8080/ for GraphQL playground")
-	if err := http.ListenAndServe(":8080", r); err != nil {
+	if graphqlConfig.Playground {
+		log.Printf("connect to http://localhost:8080/ for GraphQL playground")
+	}
+	if err := http.ListenAndServe(":8080", loaders.Middleware(dbRepo)(r)); err != nil {
 		fmt.Printf("Server error %v", err)
 	}
 }
 
-func initRoutes(db *sql.DB) *chi.Mux {
+func initRoutes(friendService services.SpecService, eventSource graph.EventSource, tokens auth.TokenParser, graphqlConfig config.GraphQLConfig) *chi.Mux {
 	r := chi.NewRouter()
-	dbRepo := repository.NewDBRepo(db)
-	friendService := services.NewFriendService(dbRepo)
+	r.Use(middleware.RequestID)
+
+	//REST
+	friendController := controllers.NewFriendController(friendService)
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/users", friendController.GetUsers)
+		r.Get("/friends", friendController.GetFriends)
+		r.Get("/commonFriends", friendController.GetCommonFriends)
+		r.Get("/recipients", friendController.GetRecipientEmails)
+
+		// The writes act as the user of the Bearer token, who has to be one of the parties
+		r.Group(func(r chi.Router) {
+			r.Use(auth.Middleware(tokens))
+			r.Post("/friends", friendController.CreateFriend)
+			r.Post("/subscription", friendController.CreateSubcription)
+			r.Post("/blocking", friendController.CreateUserBlock)
+		})
+	})
 
 	//GraphQL
-	graphqlServer := handler.GraphQL(generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{
+	resolver := &graph.Resolver{
 		Service: friendService,
-	}}))
+		Events:  eventSource,
+	}
+	graphqlServer := graph.NewServer(resolver, tokens, graphqlConfig)
 
-	r.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	r.Handle("/query", graphqlServer)
+	if graphqlConfig.Playground {
+		r.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	}
+	r.With(auth.Middleware(tokens)).Handle("/query", graphqlServer)
 	return r
 }
@@ -0,0 +1,586 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/config"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/controllers"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/events"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/models"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/pagination"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/repository"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/services"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/internal/webhooks"
+	"github.com/ToTranMinhNhut/S3_FriendManagementAPI_NhutTo/pkg/jwt"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// doREST sends a request to the REST API as the user of token and decodes the JSON response
+func doREST(t *testing.T, srv *httptest.Server, token string, method string, path string, body string) map[string]interface{} {
+	req, err := http.NewRequest(method, srv.URL+path, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	result := map[string]interface{}{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	return result
+}
+
+// doGraphQL sends a query to the GraphQL API as the user of token and decodes the selected field of the response
+func doGraphQL(t *testing.T, srv *httptest.Server, token string, query string, field string) map[string]interface{} {
+	body, err := json.Marshal(map[string]string{"query": query})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/query", bytes.NewBuffer(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	result := struct {
+		Data   map[string]map[string]interface{} `json:"data"`
+		Errors []map[string]interface{}          `json:"errors"`
+	}{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Empty(t, result.Errors)
+	return result.Data[field]
+}
+
+// newTestTokens creates a token manager signing with a HS256 test key
+func newTestTokens(t *testing.T) *jwt.Manager {
+	key, err := jwt.NewSecretKey("test", []byte("test-secret-with-at-least-32-bytes"))
+	require.NoError(t, err)
+	tokens, err := jwt.NewManager(jwt.Config{
+		Issuer:       "test",
+		Audience:     "test",
+		TTL:          time.Hour,
+		SigningKeyID: key.ID,
+		Keys:         []jwt.Key{key},
+	})
+	require.NoError(t, err)
+	return tokens
+}
+
+// memoryRepo keeps the users and relationships of the repository testdata in memory
+type memoryRepo struct {
+	repository.SpecRepo
+	users         models.UserSlice
+	friends       models.FriendSlice
+	subscriptions models.SubscriptionSlice
+	blocks        models.UserBlockSlice
+}
+
+func newMemoryRepo() *memoryRepo {
+	names := []string{"john", "andy", "common", "lisa", "kate"}
+	users := make(models.UserSlice, len(names))
+	for i, name := range names {
+		users[i] = &models.User{ID: 100 + i, Name: name, Email: name + "@example.com"}
+	}
+	return &memoryRepo{
+		users: users,
+		friends: models.FriendSlice{
+			{UserID: 100, FriendID: 102},
+			{UserID: 101, FriendID: 102},
+			{UserID: 102, FriendID: 103},
+		},
+		subscriptions: models.SubscriptionSlice{
+			{SubscriptionRequestorID: 101, SubscriptionTargetID: 103},
+		},
+		blocks: models.UserBlockSlice{
+			{RequestorID: 100, TargetID: 103},
+			{RequestorID: 100, TargetID: 104},
+		},
+	}
+}
+
+// usersWhere returns the users matching keep in the order of the users table
+func (r *memoryRepo) usersWhere(keep func(user *models.User) bool) models.UserSlice {
+	users := models.UserSlice{}
+	for _, user := range r.users {
+		if keep(user) {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+// window returns the users of a page the way the repository does it
+func window(page pagination.Page, users models.UserSlice) models.UserSlice {
+	emails := make([]string, len(users))
+	for i, user := range users {
+		emails[i] = user.Email
+	}
+	result := models.UserSlice{}
+	for _, email := range pagination.Window(page, emails) {
+		result = append(result, &models.User{Email: email})
+	}
+	return result
+}
+
+func (r *memoryRepo) isFriend(userId int, otherId int) bool {
+	for _, friend := range r.friends {
+		if (friend.UserID == userId && friend.FriendID == otherId) || (friend.UserID == otherId && friend.FriendID == userId) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryRepo) isSubscriber(requestorId int, targetId int) bool {
+	for _, subscription := range r.subscriptions {
+		if subscription.SubscriptionRequestorID == requestorId && subscription.SubscriptionTargetID == targetId {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryRepo) isBlocked(userId int, otherId int) bool {
+	for _, block := range r.blocks {
+		if (block.RequestorID == userId && block.TargetID == otherId) || (block.RequestorID == otherId && block.TargetID == userId) {
+			return true
+		}
+	}
+	return false
+}
+
+// recipients returns the friends and subscribers of a sender who have no blocking relationship with the sender
+func (r *memoryRepo) recipients(senderId int) models.UserSlice {
+	return r.usersWhere(func(user *models.User) bool {
+		return user.ID != senderId && (r.isFriend(senderId, user.ID) || r.isSubscriber(user.ID, senderId)) && !r.isBlocked(senderId, user.ID)
+	})
+}
+
+func (r *memoryRepo) GetUsers(ctx context.Context) (models.UserSlice, error) {
+	return append(models.UserSlice{}, r.users...), nil
+}
+
+func (r *memoryRepo) GetUsersPage(ctx context.Context, page pagination.Page) (models.UserSlice, error) {
+	return window(page, r.users), nil
+}
+
+func (r *memoryRepo) CountUsers(ctx context.Context) (int64, error) {
+	return int64(len(r.users)), nil
+}
+
+func (r *memoryRepo) GetUserIDByEmail(ctx context.Context, email string) (int, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user.ID, nil
+		}
+	}
+	return 0, sql.ErrNoRows
+}
+
+func (r *memoryRepo) GetEmailsByUserIDs(ctx context.Context, userIDs []int) ([]string, error) {
+	emails := []string{}
+	for _, user := range r.usersWhere(func(user *models.User) bool {
+		for _, userId := range userIDs {
+			if user.ID == userId {
+				return true
+			}
+		}
+		return false
+	}) {
+		emails = append(emails, user.Email)
+	}
+	return emails, nil
+}
+
+func (r *memoryRepo) GetFriendsByID(ctx context.Context, userId int) (models.FriendSlice, error) {
+	friends := models.FriendSlice{}
+	for _, friend := range r.friends {
+		if friend.UserID == userId || friend.FriendID == userId {
+			friends = append(friends, friend)
+		}
+	}
+	return friends, nil
+}
+
+func (r *memoryRepo) GetUserBlocksByID(ctx context.Context, userId int) (models.UserBlockSlice, error) {
+	blocks := models.UserBlockSlice{}
+	for _, block := range r.blocks {
+		if block.RequestorID == userId || block.TargetID == userId {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+func (r *memoryRepo) GetFriendsPages(ctx context.Context, userIds []int, page pagination.Page) (map[int]repository.RelationPage, error) {
+	pages := make(map[int]repository.RelationPage, len(userIds))
+	for _, userId := range userIds {
+		friends := r.usersWhere(func(user *models.User) bool {
+			return r.isFriend(userId, user.ID) && !r.isBlocked(userId, user.ID)
+		})
+		emails := []string{}
+		for _, user := range window(page, friends) {
+			emails = append(emails, user.Email)
+		}
+		pages[userId] = repository.RelationPage{Emails: emails, TotalCount: int64(len(friends))}
+	}
+	return pages, nil
+}
+
+func (r *memoryRepo) IsBlockedUser(ctx context.Context, userId int, friendId int) (bool, error) {
+	return r.isBlocked(userId, friendId), nil
+}
+
+func (r *memoryRepo) IsSubscribedUser(ctx context.Context, requestorId int, targetId int) (bool, error) {
+	return r.isSubscriber(requestorId, targetId) || r.isSubscriber(targetId, requestorId), nil
+}
+
+func (r *memoryRepo) CreateSubscription(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) error {
+	r.subscriptions = append(r.subscriptions, &models.Subscription{SubscriptionRequestorID: requestorId, SubscriptionTargetID: targetId})
+	return nil
+}
+
+func (r *memoryRepo) CreateUserBlock(ctx context.Context, requestorId int, targetId int, change webhooks.Payload) error {
+	r.blocks = append(r.blocks, &models.UserBlock{RequestorID: requestorId, TargetID: targetId})
+	return nil
+}
+
+func (r *memoryRepo) GetRecipientEmails(ctx context.Context, senderId int) (models.UserSlice, error) {
+	return r.recipients(senderId), nil
+}
+
+func (r *memoryRepo) GetRecipientsPage(ctx context.Context, senderId int, page pagination.Page) (models.UserSlice, error) {
+	return window(page, r.recipients(senderId)), nil
+}
+
+func (r *memoryRepo) CountRecipients(ctx context.Context, senderId int) (int64, error) {
+	return int64(len(r.recipients(senderId))), nil
+}
+
+func (r *memoryRepo) GetRecipientsByEmails(ctx context.Context, senderId int, emails []string) (models.UserSlice, error) {
+	recipients := models.UserSlice{}
+	for _, user := range r.recipients(senderId) {
+		for _, email := range emails {
+			if user.Email == email {
+				recipients = append(recipients, user)
+			}
+		}
+	}
+	return recipients, nil
+}
+
+func (r *memoryRepo) GetMentionableUsers(ctx context.Context, senderId int, emails []string) (models.UserSlice, error) {
+	return r.usersWhere(func(user *models.User) bool {
+		for _, email := range emails {
+			if user.Email == email {
+				return user.ID != senderId && !r.isBlocked(senderId, user.ID)
+			}
+		}
+		return false
+	}), nil
+}
+
+func TestRoutes_RESTAndGraphQLAreEquivalent(t *testing.T) {
+	tokens := newTestTokens(t)
+	token, err := tokens.GenerateToken("andy@example.com")
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		restMethod string
+		restPath   string
+		restBody   string
+		query      string
+		field      string
+		// compare maps a key of the REST response to a key of the GraphQL response
+		compare   map[string]string
+		expResult map[string]interface{}
+		expWrite  bool
+	}{
+		"get users": {
+			restMethod: http.MethodGet,
+			restPath:   "/api/v1/users",
+			query:      `{ users { success emails count } }`,
+			field:      "users",
+			compare:    map[string]string{"success": "success", "users": "emails", "count": "count"},
+			expResult: map[string]interface{}{
+				"success": true,
+				"emails":  []interface{}{"andy@example.com", "common@example.com", "john@example.com", "kate@example.com", "lisa@example.com"},
+				"count":   float64(5),
+			},
+		},
+		"list friends": {
+			restMethod: http.MethodGet,
+			restPath:   "/api/v1/friends",
+			restBody:   `{"email":"andy@example.com"}`,
+			query:      `{ friendList(input: {email: "andy@example.com"}) { success friends count } }`,
+			field:      "friendList",
+			compare:    map[string]string{"success": "success", "friends": "friends", "count": "count"},
+			expResult: map[string]interface{}{
+				"success": true,
+				"friends": []interface{}{"common@example.com"},
+				"count":   float64(1),
+			},
+		},
+		"get common friends": {
+			restMethod: http.MethodGet,
+			restPath:   "/api/v1/commonFriends",
+			restBody:   `{"friends":["andy@example.com","john@example.com"]}`,
+			query:      `{ commonFriends(input: {friends: ["andy@example.com", "john@example.com"]}) { success friends count } }`,
+			field:      "commonFriends",
+			compare:    map[string]string{"success": "success", "friends": "friends", "count": "count"},
+			expResult: map[string]interface{}{
+				"success": true,
+				"friends": []interface{}{"common@example.com"},
+				"count":   float64(1),
+			},
+		},
+		"create subscription": {
+			restMethod: http.MethodPost,
+			restPath:   "/api/v1/subscription",
+			restBody:   `{"requestor":"andy@example.com","target":"john@example.com"}`,
+			query:      `mutation { subscribe(input: {requestor: "andy@example.com", target: "john@example.com"}) { success } }`,
+			field:      "subscribe",
+			compare:    map[string]string{"success": "success"},
+			expResult:  map[string]interface{}{"success": true},
+			expWrite:   true,
+		},
+		"create user block": {
+			restMethod: http.MethodPost,
+			restPath:   "/api/v1/blocking",
+			restBody:   `{"requestor":"andy@example.com","target":"kate@example.com"}`,
+			query:      `mutation { blockUpdate(input: {requestor: "andy@example.com", target: "kate@example.com"}) { success } }`,
+			field:      "blockUpdate",
+			compare:    map[string]string{"success": "success"},
+			expResult:  map[string]interface{}{"success": true},
+			expWrite:   true,
+		},
+		"get recipients": {
+			restMethod: http.MethodGet,
+			restPath:   "/api/v1/recipients",
+			restBody:   `{"sender":"lisa@example.com","text":"Hello World! kate@example.com"}`,
+			query:      `{ retrieveEmailReceiveUpdate(input: {sender: "lisa@example.com", text: "Hello World! kate@example.com"}) { success recipients } }`,
+			field:      "retrieveEmailReceiveUpdate",
+			compare:    map[string]string{"success": "success", "recipients": "recipients"},
+			expResult: map[string]interface{}{
+				"success":    true,
+				"recipients": []interface{}{"andy@example.com", "common@example.com", "kate@example.com"},
+			},
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			// Each transport runs the real service on its own copy of the same data
+			restRepo, graphRepo := newMemoryRepo(), newMemoryRepo()
+			restSrv := httptest.NewServer(initRoutes(services.NewFriendService(restRepo, tokens, nil), events.NewBroker(), tokens, config.DefaultGraphQLConfig()))
+			defer restSrv.Close()
+			graphSrv := httptest.NewServer(initRoutes(services.NewFriendService(graphRepo, tokens, nil), events.NewBroker(), tokens, config.DefaultGraphQLConfig()))
+			defer graphSrv.Close()
+
+			restResult := doREST(t, restSrv, token, tc.restMethod, tc.restPath, tc.restBody)
+			graphResult := doGraphQL(t, graphSrv, token, tc.query, tc.field)
+
+			require.Equal(t, tc.expResult, graphResult)
+			for restKey, graphKey := range tc.compare {
+				require.Contains(t, restResult, restKey)
+				if list, ok := restResult[restKey].([]interface{}); ok {
+					// the REST lists keep the order of the database, the GraphQL ones are ordered by email
+					require.ElementsMatch(t, list, graphResult[graphKey], restKey)
+				} else {
+					require.Equal(t, restResult[restKey], graphResult[graphKey], restKey)
+				}
+			}
+
+			// Both transports leave the same data behind, a write changes it
+			require.Equal(t, restRepo, graphRepo)
+			require.Equal(t, tc.expWrite, !reflect.DeepEqual(newMemoryRepo(), restRepo))
+		})
+	}
+}
+
+func TestRoutes_RESTCreateFriendIsPending(t *testing.T) {
+	tokens := newTestTokens(t)
+	token, err := tokens.GenerateToken("andy@example.com")
+	require.NoError(t, err)
+
+	var mockService controllers.SpecService
+	mockService.ExpectedCalls = []*mock.Call{
+		mockService.On("SendFriendRequest", mock.Anything, "andy@example.com", "john@example.com").Return(nil),
+	}
+	srv := httptest.NewServer(initRoutes(mockService, events.NewBroker(), tokens, config.DefaultGraphQLConfig()))
+	defer srv.Close()
+
+	// The clients of the original spec expected the friendship, they are told that it waits for the other friend
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/v1/friends", bytes.NewBufferString(`{"friends":["andy@example.com","john@example.com"]}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	result := map[string]interface{}{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	require.Equal(t, map[string]interface{}{
+		"message": "A friend request is sent to john@example.com, the friendship is created once it is accepted",
+		"pending": true,
+		"success": false,
+	}, result)
+}
+
+func TestRoutes_RESTWritesRequireToken(t *testing.T) {
+	tokens := newTestTokens(t)
+	token, err := tokens.GenerateToken("kate@example.com")
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		path      string
+		body      string
+		token     string
+		expStatus int
+	}{
+		"create friend without a token": {
+			path:      "/api/v1/friends",
+			body:      `{"friends":["andy@example.com","john@example.com"]}`,
+			expStatus: http.StatusUnauthorized,
+		},
+		"create subscription with an invalid token": {
+			path:      "/api/v1/subscription",
+			body:      `{"requestor":"andy@example.com","target":"lisa@example.com"}`,
+			token:     "invalid",
+			expStatus: http.StatusUnauthorized,
+		},
+		"create user block as another user": {
+			path:      "/api/v1/blocking",
+			body:      `{"requestor":"andy@example.com","target":"lisa@example.com"}`,
+			token:     token,
+			expStatus: http.StatusForbidden,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			// Nothing is written for the rejected requests
+			var mockService controllers.SpecService
+			srv := httptest.NewServer(initRoutes(mockService, events.NewBroker(), tokens, config.DefaultGraphQLConfig()))
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodPost, srv.URL+tc.path, bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, tc.expStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestRoutes_SubscriptionsOverWebsocket(t *testing.T) {
+	tokens := newTestTokens(t)
+	token, err := tokens.GenerateToken("andy@example.com")
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		payload   map[string]interface{}
+		expResult map[string]interface{}
+		expError  string
+	}{
+		"success with the token of the connection_init payload": {
+			payload:   map[string]interface{}{"Authorization": "Bearer " + token},
+			expResult: map[string]interface{}{"email": "andy@example.com", "friend": "john@example.com"},
+		},
+		"failed with an anonymous connection": {
+			payload:  map[string]interface{}{},
+			expError: `[{"message":"Access denied, a valid token is required","path":["friendAdded"],"extensions":{"code":"UNAUTHENTICATED","requestId":`,
+		},
+		"failed with an invalid token": {
+			payload:  map[string]interface{}{"Authorization": "Bearer invalid"},
+			expError: "expected ack message",
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			var mockService controllers.SpecService
+			broker := events.NewBroker()
+			c := client.New(initRoutes(mockService, broker, tokens, config.DefaultGraphQLConfig()), client.Path("/query"))
+
+			sub := c.WebsocketWithPayload(`subscription { friendAdded(email: "andy@example.com") { email friend } }`, tc.payload)
+			defer sub.Close()
+
+			// the subscription only gets the events published after it started
+			done := make(chan struct{})
+			defer close(done)
+			go func() {
+				for {
+					broker.Publish(context.Background(), events.Event{Kind: events.FriendAdded, Email: "andy@example.com", Friend: "john@example.com"})
+					select {
+					case <-done:
+						return
+					case <-time.After(5 * time.Millisecond):
+					}
+				}
+			}()
+
+			var resp map[string]map[string]interface{}
+			err := sub.Next(&resp)
+			if tc.expError != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expResult, resp["friendAdded"])
+			}
+		})
+	}
+}
+
+func TestRoutes_Playground(t *testing.T) {
+	tcs := map[string]struct {
+		playground bool
+		expStatus  int
+	}{
+		"playground served when it is turned on": {
+			playground: true,
+			expStatus:  http.StatusOK,
+		},
+		"playground not found when it is turned off": {
+			playground: false,
+			expStatus:  http.StatusNotFound,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			var mockService controllers.SpecService
+			cfg := config.DefaultGraphQLConfig()
+			cfg.Playground = tc.playground
+
+			rr := httptest.NewRecorder()
+			initRoutes(mockService, events.NewBroker(), newTestTokens(t), cfg).
+				ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+			require.Equal(t, tc.expStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package jwt
+
+import "errors"
+
+// Errors returned by the package, they are matched with errors.Is
+var (
+	ErrInvalidConfig           = errors.New("jwt: invalid config")
+	ErrInvalidKey              = errors.New("jwt: invalid key")
+	ErrInvalidToken            = errors.New("jwt: invalid token")
+	ErrExpiredToken            = errors.New("jwt: token is expired")
+	ErrUnknownKeyID            = errors.New("jwt: unknown key id")
+	ErrUnexpectedSigningMethod = errors.New("jwt: unexpected signing method")
+	ErrInvalidIssuer           = errors.New("jwt: invalid issuer")
+	ErrInvalidAudience         = errors.New("jwt: invalid audience")
+	ErrMissingSubject          = errors.New("jwt: missing subject")
+)
+
+// ValidationError is returned by ParseToken when a token is rejected.
+// It matches ErrInvalidToken and the reason of the rejection, such as ErrExpiredToken, with errors.Is.
+type ValidationError struct {
+	Reason error
+	Err    error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Err != nil && e.Err != e.Reason {
+		return e.Reason.Error() + ": " + e.Err.Error()
+	}
+	return e.Reason.Error()
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidToken || target == e.Reason
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// newValidationError builds a ValidationError with reason, err is the underlying cause and may be nil
+func newValidationError(reason error, err error) *ValidationError {
+	return &ValidationError{Reason: reason, Err: err}
+}
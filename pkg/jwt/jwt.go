@@ -1,42 +1,150 @@
 package jwt
 
 import (
-	"log"
+	"errors"
+	"fmt"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	jwtgo "github.com/golang-jwt/jwt/v4"
 )
 
-// secret key being used to sign tokens
-var (
-	SecretKey = []byte("secret")
-)
+// Config of a Manager.
+// Keys holds every key accepted for verification, SigningKeyID selects the one signing new tokens.
+// Rotating keys is done by adding the new key, switching SigningKeyID to it and removing the old key once
+// the tokens it signed are expired.
+type Config struct {
+	Issuer       string
+	Audience     string
+	TTL          time.Duration
+	SigningKeyID string
+	Keys         []Key
+}
+
+// Manager generates and parses tokens of a key set
+type Manager struct {
+	issuer     string
+	audience   string
+	ttl        time.Duration
+	signingKey Key
+	keys       map[string]Key
+	now        func() time.Time
+}
+
+// NewManager validates the config and returns a Manager using it
+func NewManager(cfg Config) (*Manager, error) {
+	if cfg.Issuer == "" {
+		return nil, fmt.Errorf("%w: issuer is empty", ErrInvalidConfig)
+	}
+	if cfg.Audience == "" {
+		return nil, fmt.Errorf("%w: audience is empty", ErrInvalidConfig)
+	}
+	if cfg.TTL <= 0 {
+		return nil, fmt.Errorf("%w: ttl must be positive", ErrInvalidConfig)
+	}
+
+	keys := make(map[string]Key, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		if err := key.validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+		if _, ok := keys[key.ID]; ok {
+			return nil, fmt.Errorf("%w: key id %q is duplicated", ErrInvalidConfig, key.ID)
+		}
+		keys[key.ID] = key
+	}
+
+	signingKey, ok := keys[cfg.SigningKeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: signing key %q is not in the key set", ErrInvalidConfig, cfg.SigningKeyID)
+	}
+	if signingKey.PrivateKey == nil {
+		return nil, fmt.Errorf("%w: signing key %q has no private key", ErrInvalidConfig, cfg.SigningKeyID)
+	}
+
+	return &Manager{
+		issuer:     cfg.Issuer,
+		audience:   cfg.Audience,
+		ttl:        cfg.TTL,
+		signingKey: signingKey,
+		keys:       keys,
+		now:        time.Now,
+	}, nil
+}
+
+// GenerateToken signs a token with the current signing key, subject is the email of the authenticated user
+func (_self *Manager) GenerateToken(subject string) (string, error) {
+	if subject == "" {
+		return "", ErrMissingSubject
+	}
 
-// GenerateToken generates a jwt token and assign a username to it's claims and return it
-func GenerateToken(username string) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
-	/* Create a map to store our claims */
-	claims := token.Claims.(jwt.MapClaims)
-	/* Set token claims */
-	claims["username"] = username
-	claims["exp"] = time.Now().Add(time.Hour * 24).Unix()
-	tokenString, err := token.SignedString(SecretKey)
+	now := _self.now()
+	claims := jwtgo.RegisteredClaims{
+		Subject:   subject,
+		Issuer:    _self.issuer,
+		Audience:  jwtgo.ClaimStrings{_self.audience},
+		IssuedAt:  jwtgo.NewNumericDate(now),
+		NotBefore: jwtgo.NewNumericDate(now),
+		ExpiresAt: jwtgo.NewNumericDate(now.Add(_self.ttl)),
+	}
+	token := jwtgo.NewWithClaims(jwtgo.GetSigningMethod(_self.signingKey.Algorithm), claims)
+	token.Header["kid"] = _self.signingKey.ID
+
+	tokenStr, err := token.SignedString(_self.signingKey.PrivateKey)
 	if err != nil {
-		log.Fatal("Error in Generating key")
-		return "", err
+		return "", fmt.Errorf("jwt: sign token: %w", err)
+	}
+	return tokenStr, nil
+}
+
+// ParseToken verifies a token against the key set, issuer and audience and returns its subject.
+// A rejected token returns a *ValidationError.
+func (_self *Manager) ParseToken(tokenStr string) (string, error) {
+	claims := jwtgo.RegisteredClaims{}
+	parser := jwtgo.NewParser(jwtgo.WithoutClaimsValidation())
+	if _, err := parser.ParseWithClaims(tokenStr, &claims, _self.keyFunc); err != nil {
+		return "", toValidationError(err)
+	}
+
+	// Claims are validated here with the clock of the manager
+	now := _self.now()
+	if !claims.VerifyExpiresAt(now, true) {
+		return "", newValidationError(ErrExpiredToken, nil)
+	}
+	if !claims.VerifyNotBefore(now, false) || !claims.VerifyIssuedAt(now, false) {
+		return "", newValidationError(ErrInvalidToken, errors.New("token used before issued"))
+	}
+	if !claims.VerifyIssuer(_self.issuer, true) {
+		return "", newValidationError(ErrInvalidIssuer, nil)
+	}
+	if !claims.VerifyAudience(_self.audience, true) {
+		return "", newValidationError(ErrInvalidAudience, nil)
+	}
+	if claims.Subject == "" {
+		return "", newValidationError(ErrMissingSubject, nil)
+	}
+
+	return claims.Subject, nil
+}
+
+// keyFunc selects the verification key of a token by its "kid" header.
+// The algorithm of the token has to be the one of the key, it prevents algorithm confusion attacks.
+func (_self *Manager) keyFunc(token *jwtgo.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := _self.keys[kid]
+	if !ok {
+		return nil, newValidationError(ErrUnknownKeyID, nil)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, newValidationError(ErrUnexpectedSigningMethod, nil)
 	}
-	return tokenString, nil
+	return key.PublicKey, nil
 }
 
-// ParseToken parses a jwt token and returns the username in it's claims
-func ParseToken(tokenStr string) (string, error) {
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		return SecretKey, nil
-	})
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		username := claims["username"].(string)
-		return username, nil
-	} else {
-		return "", err
+// toValidationError converts an error of the parser into a *ValidationError
+func toValidationError(err error) error {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr
 	}
+	return newValidationError(ErrInvalidToken, err)
 }
@@ -0,0 +1,335 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// newRSAKey generates a RS256 key and returns it with its private and public PEM encodings
+func newRSAKey(t *testing.T, id string) (Key, []byte, []byte) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	return Key{ID: id, Algorithm: AlgorithmRS256, PrivateKey: privateKey, PublicKey: &privateKey.PublicKey}, privatePEM, publicPEM
+}
+
+// newEdDSAKey generates an EdDSA key and returns it with its private and public PEM encodings
+func newEdDSAKey(t *testing.T, id string) (Key, []byte, []byte) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	return Key{ID: id, Algorithm: AlgorithmEdDSA, PrivateKey: privateKey, PublicKey: publicKey}, privatePEM, publicPEM
+}
+
+func newTestManager(t *testing.T, signingKeyID string, keys ...Key) *Manager {
+	manager, err := NewManager(Config{
+		Issuer:       "friend-management",
+		Audience:     "friend-management-api",
+		TTL:          time.Hour,
+		SigningKeyID: signingKeyID,
+		Keys:         keys,
+	})
+	require.NoError(t, err)
+	return manager
+}
+
+func TestManager_GenerateAndParseToken(t *testing.T) {
+	secretKey, err := NewSecretKey("hs", []byte("secret-with-at-least-thirty-two-bytes"))
+	require.NoError(t, err)
+	rsaKey, _, _ := newRSAKey(t, "rs")
+	edKey, _, _ := newEdDSAKey(t, "ed")
+
+	tcs := map[string]struct {
+		key Key
+	}{
+		"success with a HS256 key":  {key: secretKey},
+		"success with a RS256 key":  {key: rsaKey},
+		"success with an EdDSA key": {key: edKey},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			manager := newTestManager(t, tc.key.ID, tc.key)
+
+			token, err := manager.GenerateToken("andy@example.com")
+			require.NoError(t, err)
+
+			subject, err := manager.ParseToken(token)
+			require.NoError(t, err)
+			require.Equal(t, "andy@example.com", subject)
+		})
+	}
+}
+
+func TestManager_KeyRotation(t *testing.T) {
+	oldKey, _, _ := newRSAKey(t, "2021-11")
+	newKey, _, _ := newEdDSAKey(t, "2021-12")
+	oldPublicKey := Key{ID: oldKey.ID, Algorithm: oldKey.Algorithm, PublicKey: oldKey.PublicKey}
+
+	oldManager := newTestManager(t, oldKey.ID, oldKey)
+	oldToken, err := oldManager.GenerateToken("andy@example.com")
+	require.NoError(t, err)
+
+	// The new key signs, the old key is still accepted for verification
+	rotatedManager := newTestManager(t, newKey.ID, newKey, oldPublicKey)
+	subject, err := rotatedManager.ParseToken(oldToken)
+	require.NoError(t, err)
+	require.Equal(t, "andy@example.com", subject)
+
+	newToken, err := rotatedManager.GenerateToken("andy@example.com")
+	require.NoError(t, err)
+	_, err = oldManager.ParseToken(newToken)
+	require.True(t, errors.Is(err, ErrUnknownKeyID))
+
+	// The old key is removed once its tokens are expired
+	cleanedManager := newTestManager(t, newKey.ID, newKey)
+	_, err = cleanedManager.ParseToken(oldToken)
+	require.True(t, errors.Is(err, ErrUnknownKeyID))
+	require.True(t, errors.Is(err, ErrInvalidToken))
+}
+
+func TestManager_ParseToken(t *testing.T) {
+	rsaKey, _, _ := newRSAKey(t, "rs")
+	manager := newTestManager(t, rsaKey.ID, rsaKey)
+
+	sign := func(method jwtgo.SigningMethod, kid string, claims jwtgo.RegisteredClaims, key interface{}) string {
+		token := jwtgo.NewWithClaims(method, claims)
+		token.Header["kid"] = kid
+		tokenStr, err := token.SignedString(key)
+		require.NoError(t, err)
+		return tokenStr
+	}
+	validClaims := func() jwtgo.RegisteredClaims {
+		now := time.Now()
+		return jwtgo.RegisteredClaims{
+			Subject:   "andy@example.com",
+			Issuer:    "friend-management",
+			Audience:  jwtgo.ClaimStrings{"friend-management-api"},
+			IssuedAt:  jwtgo.NewNumericDate(now),
+			ExpiresAt: jwtgo.NewNumericDate(now.Add(time.Hour)),
+		}
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		token    func() string
+		expError error
+	}{
+		"success with a valid token": {
+			token: func() string {
+				return sign(jwtgo.SigningMethodRS256, "rs", validClaims(), rsaKey.PrivateKey)
+			},
+		},
+		"failed with a malformed token": {
+			token:    func() string { return "invalid" },
+			expError: ErrInvalidToken,
+		},
+		"failed with an expired token": {
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = jwtgo.NewNumericDate(time.Now().Add(-time.Minute))
+				return sign(jwtgo.SigningMethodRS256, "rs", claims, rsaKey.PrivateKey)
+			},
+			expError: ErrExpiredToken,
+		},
+		"failed with a token without expiration": {
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = nil
+				return sign(jwtgo.SigningMethodRS256, "rs", claims, rsaKey.PrivateKey)
+			},
+			expError: ErrExpiredToken,
+		},
+		"failed with another issuer": {
+			token: func() string {
+				claims := validClaims()
+				claims.Issuer = "another"
+				return sign(jwtgo.SigningMethodRS256, "rs", claims, rsaKey.PrivateKey)
+			},
+			expError: ErrInvalidIssuer,
+		},
+		"failed with another audience": {
+			token: func() string {
+				claims := validClaims()
+				claims.Audience = jwtgo.ClaimStrings{"another"}
+				return sign(jwtgo.SigningMethodRS256, "rs", claims, rsaKey.PrivateKey)
+			},
+			expError: ErrInvalidAudience,
+		},
+		"failed with a missing subject": {
+			token: func() string {
+				claims := validClaims()
+				claims.Subject = ""
+				return sign(jwtgo.SigningMethodRS256, "rs", claims, rsaKey.PrivateKey)
+			},
+			expError: ErrMissingSubject,
+		},
+		"failed with an unknown key id": {
+			token: func() string {
+				return sign(jwtgo.SigningMethodRS256, "unknown", validClaims(), rsaKey.PrivateKey)
+			},
+			expError: ErrUnknownKeyID,
+		},
+		"failed with a HS256 token signed by the RS256 public key": {
+			token: func() string {
+				return sign(jwtgo.SigningMethodHS256, "rs", validClaims(), publicDER)
+			},
+			expError: ErrUnexpectedSigningMethod,
+		},
+		"failed with an unsigned token": {
+			token: func() string {
+				return sign(jwtgo.SigningMethodNone, "rs", validClaims(), jwtgo.UnsafeAllowNoneSignatureType)
+			},
+			expError: ErrUnexpectedSigningMethod,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			subject, err := manager.ParseToken(tc.token())
+			if tc.expError != nil {
+				require.True(t, errors.Is(err, tc.expError), err)
+				require.True(t, errors.Is(err, ErrInvalidToken), err)
+				var validationErr *ValidationError
+				require.True(t, errors.As(err, &validationErr))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "andy@example.com", subject)
+			}
+		})
+	}
+}
+
+func TestNewManager(t *testing.T) {
+	rsaKey, _, _ := newRSAKey(t, "rs")
+	edKey, _, _ := newEdDSAKey(t, "ed")
+	publicOnlyKey := Key{ID: "public", Algorithm: AlgorithmRS256, PublicKey: rsaKey.PublicKey}
+
+	tcs := map[string]struct {
+		cfg      Config
+		expError error
+	}{
+		"success with several verification keys": {
+			cfg: Config{Issuer: "iss", Audience: "aud", TTL: time.Hour, SigningKeyID: "rs", Keys: []Key{rsaKey, edKey, publicOnlyKey}},
+		},
+		"failed with an empty issuer": {
+			cfg:      Config{Audience: "aud", TTL: time.Hour, SigningKeyID: "rs", Keys: []Key{rsaKey}},
+			expError: ErrInvalidConfig,
+		},
+		"failed with an unknown signing key": {
+			cfg:      Config{Issuer: "iss", Audience: "aud", TTL: time.Hour, SigningKeyID: "unknown", Keys: []Key{rsaKey}},
+			expError: ErrInvalidConfig,
+		},
+		"failed with a signing key without private key": {
+			cfg:      Config{Issuer: "iss", Audience: "aud", TTL: time.Hour, SigningKeyID: "public", Keys: []Key{publicOnlyKey}},
+			expError: ErrInvalidConfig,
+		},
+		"failed with a duplicated key id": {
+			cfg:      Config{Issuer: "iss", Audience: "aud", TTL: time.Hour, SigningKeyID: "rs", Keys: []Key{rsaKey, rsaKey}},
+			expError: ErrInvalidConfig,
+		},
+		"failed with a key not matching its algorithm": {
+			cfg: Config{Issuer: "iss", Audience: "aud", TTL: time.Hour, SigningKeyID: "rs", Keys: []Key{
+				{ID: "rs", Algorithm: AlgorithmRS256, PrivateKey: edKey.PrivateKey, PublicKey: edKey.PublicKey},
+			}},
+			expError: ErrInvalidConfig,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			_, err := NewManager(tc.cfg)
+			if tc.expError != nil {
+				require.True(t, errors.Is(err, tc.expError), err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	_, rsaPrivatePEM, rsaPublicPEM := newRSAKey(t, "rs")
+	_, edPrivatePEM, edPublicPEM := newEdDSAKey(t, "ed")
+
+	tcs := map[string]struct {
+		algorithm  string
+		data       []byte
+		expPrivate bool
+		expError   error
+	}{
+		"success with a RS256 private key": {
+			algorithm:  AlgorithmRS256,
+			data:       rsaPrivatePEM,
+			expPrivate: true,
+		},
+		"success with a RS256 public key": {
+			algorithm: AlgorithmRS256,
+			data:      rsaPublicPEM,
+		},
+		"success with an EdDSA private key": {
+			algorithm:  AlgorithmEdDSA,
+			data:       edPrivatePEM,
+			expPrivate: true,
+		},
+		"success with an EdDSA public key": {
+			algorithm: AlgorithmEdDSA,
+			data:      edPublicPEM,
+		},
+		"success with a HS256 secret": {
+			algorithm:  AlgorithmHS256,
+			data:       []byte("secret-with-at-least-thirty-two-bytes\n"),
+			expPrivate: true,
+		},
+		"failed with a short HS256 secret": {
+			algorithm: AlgorithmHS256,
+			data:      []byte("secret"),
+			expError:  ErrInvalidKey,
+		},
+		"failed with a key of another algorithm": {
+			algorithm: AlgorithmRS256,
+			data:      edPublicPEM,
+			expError:  ErrInvalidKey,
+		},
+		"failed with an unsupported algorithm": {
+			algorithm: "none",
+			data:      rsaPublicPEM,
+			expError:  ErrInvalidKey,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			key, err := ParseKey("kid", tc.algorithm, tc.data)
+			if tc.expError != nil {
+				require.True(t, errors.Is(err, tc.expError), err)
+			} else {
+				require.NoError(t, err)
+				require.NoError(t, key.validate())
+				require.Equal(t, tc.expPrivate, key.PrivateKey != nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	jwtgo "github.com/golang-jwt/jwt/v4"
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// Minimum length of a HS256 secret, shorter secrets can be brute forced
+const minSecretLength = 32
+
+// Key is a key of the key set, it is referenced by ID in the "kid" header of a token.
+// PrivateKey is only needed by the key signing new tokens, keys without it are verification keys of rotated out signers.
+type Key struct {
+	ID         string
+	Algorithm  string
+	PrivateKey interface{} // []byte, *rsa.PrivateKey or ed25519.PrivateKey
+	PublicKey  interface{} // []byte, *rsa.PublicKey or ed25519.PublicKey
+}
+
+// NewSecretKey builds a HS256 key from a shared secret
+func NewSecretKey(id string, secret []byte) (Key, error) {
+	if len(secret) < minSecretLength {
+		return Key{}, fmt.Errorf("%w: secret of key %q must have at least %d bytes", ErrInvalidKey, id, minSecretLength)
+	}
+	return Key{ID: id, Algorithm: AlgorithmHS256, PrivateKey: secret, PublicKey: secret}, nil
+}
+
+// LoadKey reads a key from a file, it is a raw secret for HS256 and a PEM encoded private or public key otherwise
+func LoadKey(id string, algorithm string, path string) (Key, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	return ParseKey(id, algorithm, data)
+}
+
+// ParseKey decodes a key of algorithm, data is a raw secret for HS256 and a PEM encoded private or public key otherwise
+func ParseKey(id string, algorithm string, data []byte) (Key, error) {
+	isPrivate := bytes.Contains(data, []byte("PRIVATE KEY"))
+
+	switch algorithm {
+	case AlgorithmHS256:
+		return NewSecretKey(id, bytes.TrimSpace(data))
+	case AlgorithmRS256:
+		if isPrivate {
+			privateKey, err := jwtgo.ParseRSAPrivateKeyFromPEM(data)
+			if err != nil {
+				return Key{}, fmt.Errorf("%w: key %q: %v", ErrInvalidKey, id, err)
+			}
+			return Key{ID: id, Algorithm: algorithm, PrivateKey: privateKey, PublicKey: &privateKey.PublicKey}, nil
+		}
+		publicKey, err := jwtgo.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return Key{}, fmt.Errorf("%w: key %q: %v", ErrInvalidKey, id, err)
+		}
+		return Key{ID: id, Algorithm: algorithm, PublicKey: publicKey}, nil
+	case AlgorithmEdDSA:
+		if isPrivate {
+			privateKey, err := jwtgo.ParseEdPrivateKeyFromPEM(data)
+			if err != nil {
+				return Key{}, fmt.Errorf("%w: key %q: %v", ErrInvalidKey, id, err)
+			}
+			edPrivateKey := privateKey.(ed25519.PrivateKey)
+			return Key{ID: id, Algorithm: algorithm, PrivateKey: edPrivateKey, PublicKey: edPrivateKey.Public()}, nil
+		}
+		publicKey, err := jwtgo.ParseEdPublicKeyFromPEM(data)
+		if err != nil {
+			return Key{}, fmt.Errorf("%w: key %q: %v", ErrInvalidKey, id, err)
+		}
+		return Key{ID: id, Algorithm: algorithm, PublicKey: publicKey}, nil
+	default:
+		return Key{}, fmt.Errorf("%w: algorithm %q of key %q is not supported", ErrInvalidKey, algorithm, id)
+	}
+}
+
+// validate checks the key types match the algorithm of the key
+func (_self Key) validate() error {
+	if strings.TrimSpace(_self.ID) == "" {
+		return fmt.Errorf("%w: key id is empty", ErrInvalidKey)
+	}
+
+	var validPrivate, validPublic bool
+	switch _self.Algorithm {
+	case AlgorithmHS256:
+		secret, ok := _self.PublicKey.([]byte)
+		validPublic = ok && len(secret) >= minSecretLength
+		_, validPrivate = _self.PrivateKey.([]byte)
+	case AlgorithmRS256:
+		_, validPublic = _self.PublicKey.(*rsa.PublicKey)
+		_, validPrivate = _self.PrivateKey.(*rsa.PrivateKey)
+	case AlgorithmEdDSA:
+		_, validPublic = _self.PublicKey.(ed25519.PublicKey)
+		_, validPrivate = _self.PrivateKey.(ed25519.PrivateKey)
+	default:
+		return fmt.Errorf("%w: algorithm %q of key %q is not supported", ErrInvalidKey, _self.Algorithm, _self.ID)
+	}
+
+	if !validPublic {
+		return fmt.Errorf("%w: verification key of %q does not match algorithm %s", ErrInvalidKey, _self.ID, _self.Algorithm)
+	}
+	if _self.PrivateKey != nil && !validPrivate {
+		return fmt.Errorf("%w: signing key of %q does not match algorithm %s", ErrInvalidKey, _self.ID, _self.Algorithm)
+	}
+	return nil
+}